@@ -17,8 +17,17 @@ type Config struct {
 	// Enable CBF (Counting Bloom Filter) that allows items deleting. Item counter size is 16 bits.
 	CBF bool
 	// Hasher to calculate hash sum of the items.
-	// Mandatory param.
+	// Mandatory param (except of pbtk.HashStrategyDouble128 strategy).
 	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
+	// 128-bit hasher to calculate hash sum of the items.
+	// Mandatory param for pbtk.HashStrategyDouble128 strategy.
+	Hasher128 pbtk.Hasher128
+	// Strategy of k hash sums calculation.
+	// If this param omitted, pbtk.HashStrategySalt will use (k salted hash sums of the item).
+	// Caution! Filters built with different strategies aren't compatible, so dumps must be read with the same strategy.
+	HashStrategy pbtk.HashStrategy
 	// Setting up this section enables concurrent read/write operations.
 	Concurrent *ConcurrentConfig
 	// Metrics writer handler.
@@ -64,6 +73,16 @@ func (c *Config) WithHasher(hasher pbtk.Hasher) *Config {
 	return c
 }
 
+func (c *Config) WithHasher128(hasher pbtk.Hasher128) *Config {
+	c.Hasher128 = hasher
+	return c
+}
+
+func (c *Config) WithHashStrategy(strategy pbtk.HashStrategy) *Config {
+	c.HashStrategy = strategy
+	return c
+}
+
 func (c *Config) WithWriteAttemptsLimit(limit uint64) *Config {
 	if c.Concurrent == nil {
 		c.Concurrent = &ConcurrentConfig{}
@@ -77,6 +96,11 @@ func (c *Config) WithMetricsWriter(mw amq.MetricsWriter) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
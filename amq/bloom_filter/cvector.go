@@ -75,8 +75,18 @@ func (vec *cvector) Capacity() uint64 {
 	return uint64(len(vec.buf)) * 2
 }
 
-func (vec *cvector) Popcnt() uint64 {
-	return 0 // useless for Bloom
+// Popcnt returns number of non-zero counters.
+func (vec *cvector) Popcnt() (r uint64) {
+	for i := 0; i < len(vec.buf); i++ {
+		c := vec.buf[i]
+		if c>>16 > 0 {
+			r++
+		}
+		if c&math.MaxUint16 > 0 {
+			r++
+		}
+	}
+	return
 }
 
 func (vec *cvector) Difference(_ bitvector.Interface) (uint64, error) {
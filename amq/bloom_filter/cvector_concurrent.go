@@ -83,8 +83,18 @@ func (vec *ccnvector) Capacity() uint64 {
 	return uint64(len(vec.buf)) * 2
 }
 
-func (vec *ccnvector) Popcnt() uint64 {
-	return 0 // useless for Bloom
+// Popcnt returns number of non-zero counters.
+func (vec *ccnvector) Popcnt() (r uint64) {
+	for i := 0; i < len(vec.buf); i++ {
+		c := atomic.LoadUint32(&vec.buf[i])
+		if c>>16 > 0 {
+			r++
+		}
+		if c&math.MaxUint16 > 0 {
+			r++
+		}
+	}
+	return
 }
 
 func (vec *ccnvector) Difference(_ bitvector.Interface) (uint64, error) {
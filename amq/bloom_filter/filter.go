@@ -3,6 +3,7 @@ package bloom
 import (
 	"fmt"
 	"io"
+	"math"
 	"sync"
 
 	"github.com/koykov/bitvector"
@@ -58,8 +59,9 @@ func (f *filter[T]) Set(key T) error {
 	if f.once.Do(f.init); f.err != nil {
 		return f.err
 	}
+	var dh pbtk.DoubleHash
 	for i := uint64(0); i < f.k; i++ {
-		h, err := f.h(key, i)
+		h, err := f.h(key, i, &dh)
 		if err != nil {
 			return f.mw().Set(err)
 		}
@@ -83,8 +85,9 @@ func (f *filter[T]) Unset(key T) error {
 	if f.once.Do(f.init); f.err != nil {
 		return f.err
 	}
+	var dh pbtk.DoubleHash
 	for i := uint64(0); i < f.k; i++ {
-		h, err := f.h(key, i)
+		h, err := f.h(key, i, &dh)
 		if err != nil {
 			return f.mw().Unset(err)
 		}
@@ -119,8 +122,9 @@ func (f *filter[T]) Contains(key T) bool {
 	if f.once.Do(f.init); f.err != nil {
 		return false
 	}
+	var dh pbtk.DoubleHash
 	for i := uint64(0); i < f.k; i++ {
-		h, err := f.h(key, i)
+		h, err := f.h(key, i, &dh)
 		if err != nil {
 			return f.mw().Contains(false)
 		}
@@ -146,6 +150,18 @@ func (f *filter[T]) Size() uint64 {
 	return f.vec.Size()
 }
 
+// FPP returns current false positive probability calculated from the fraction of set bits: (X/m)^k.
+// Estimation assumes keys added using Set method, since HSet sets only one bit per key.
+// Caution! It takes a pass over all filter bits.
+func (f *filter[T]) FPP() float64 {
+	if f.once.Do(f.init); f.err != nil {
+		return 0
+	}
+	fpp := math.Pow(float64(f.vec.Popcnt())/float64(f.m), float64(f.k))
+	f.mw().FPP(fpp)
+	return fpp
+}
+
 func (f *filter[T]) ReadFrom(r io.Reader) (int64, error) {
 	if f.once.Do(f.init); f.err != nil {
 		return 0, f.err
@@ -187,13 +203,13 @@ func (f *filter[T]) Reset() {
 }
 
 func (f *filter[T]) init() {
+	f.SetEncoding(f.conf.Encoding)
 	c := f.conf
 	if c.ItemsNumber == 0 {
 		f.err = amq.ErrNoItemsNumber
 		return
 	}
-	if c.Hasher == nil {
-		f.err = pbtk.ErrNoHasher
+	if f.err = c.HashStrategy.Check(c.Hasher, c.Hasher128); f.err != nil {
 		return
 	}
 	if c.MetricsWriter == nil {
@@ -225,8 +241,8 @@ func (f *filter[T]) init() {
 	f.mw().Capacity(f.m)
 }
 
-func (f *filter[T]) h(key T, salt uint64) (uint64, error) {
-	return f.HashSalt(f.conf.Hasher, key, salt)
+func (f *filter[T]) h(key T, i uint64, dh *pbtk.DoubleHash) (uint64, error) {
+	return f.HashStrategyK(f.conf.HashStrategy, f.conf.Hasher, f.conf.Hasher128, key, i, dh)
 }
 
 func (f *filter[T]) mw() amq.MetricsWriter {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/amq"
 )
 
@@ -31,6 +32,30 @@ func TestFilter(t *testing.T) {
 		}
 		amq.TestMeConcurrently(t, f)
 	})
+	t.Run("fpp", func(t *testing.T) {
+		f, err := NewFilter[[]byte](NewConfig(1e5, testFPP, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		amq.TestMeFPP(t, f, 1e5)
+	})
+	t.Run("double", func(t *testing.T) {
+		f, err := NewFilter[[]byte](NewConfig(testSz, testFPP, testh).
+			WithHashStrategy(pbtk.HashStrategyDouble))
+		if err != nil {
+			t.Fatal(err)
+		}
+		amq.TestMe(t, f)
+	})
+	t.Run("double128", func(t *testing.T) {
+		f, err := NewFilter[[]byte](NewConfig(testSz, testFPP, nil).
+			WithHasher128(xxhash.Hasher128[[]byte]{}).
+			WithHashStrategy(pbtk.HashStrategyDouble128))
+		if err != nil {
+			t.Fatal(err)
+		}
+		amq.TestMe(t, f)
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, f amq.Filter[string], path string, expect int64) {
 			_ = f.Set("foobar")
@@ -102,6 +127,13 @@ func TestCountingFilter(t *testing.T) {
 		}
 		amq.TestMeConcurrently(t, f)
 	})
+	t.Run("fpp", func(t *testing.T) {
+		f, err := NewCountingFilter[[]byte](NewConfig(1e5, testFPP, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		amq.TestMeFPP(t, f, 1e5)
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, f amq.Filter[string], path string, expect int64) {
 			_ = f.Set("foobar")
@@ -173,6 +205,14 @@ func BenchmarkFilter(b *testing.B) {
 		}
 		amq.BenchMeConcurrently(b, f)
 	})
+	b.Run("double", func(b *testing.B) {
+		f, err := NewFilter[[]byte](NewConfig(testSz, testFPP, testh).
+			WithHashStrategy(pbtk.HashStrategyDouble))
+		if err != nil {
+			b.Fatal(err)
+		}
+		amq.BenchMe(b, f)
+	})
 }
 
 func BenchmarkCountingFilter(b *testing.B) {
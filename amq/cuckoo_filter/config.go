@@ -14,6 +14,8 @@ type Config struct {
 	// Hasher to calculate hash sum of the items.
 	// Mandatory param.
 	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
 	// How many kicks may filter do to set the item.
 	KicksLimit uint64
 	// Setting up this section enables concurrent read/write operations.
@@ -65,6 +67,11 @@ func (c *Config) WithMetricsWriter(mw amq.MetricsWriter) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
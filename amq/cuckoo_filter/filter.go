@@ -3,6 +3,7 @@ package cuckoo
 import (
 	"fmt"
 	"io"
+	"math"
 	"math/bits"
 	"math/rand"
 	"sync"
@@ -162,6 +163,18 @@ func (f *filter[T]) Size() uint64 {
 	return f.vec.size()
 }
 
+// FPP returns current false positive probability calculated from the load factor α and fingerprint size:
+// 1-(1-1/255)^(2bα), since lookup checks 2 buckets of b slots and fingerprint takes one of 255 values.
+func (f *filter[T]) FPP() float64 {
+	if f.once.Do(f.init); f.err != nil {
+		return 0
+	}
+	lf := float64(f.vec.size()) / float64(f.vec.capacity()*bucketsz)
+	fpp := 1 - math.Pow(1-1/255., 2*bucketsz*min(lf, 1))
+	f.mw().FPP(fpp)
+	return fpp
+}
+
 func (f *filter[T]) ReadFrom(r io.Reader) (int64, error) {
 	if f.once.Do(f.init); f.err != nil {
 		return 0, f.err
@@ -211,6 +224,7 @@ func (f *filter[T]) hcalcI2FP(hkey, bp uint64) (i0, i1 uint64, fp byte, err erro
 }
 
 func (f *filter[T]) init() {
+	f.SetEncoding(f.conf.Encoding)
 	c := f.conf
 	if c.ItemsNumber == 0 {
 		f.err = amq.ErrNoItemsNumber
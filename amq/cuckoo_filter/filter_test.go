@@ -28,6 +28,13 @@ func TestFilter(t *testing.T) {
 		}
 		amq.TestMeConcurrently(t, f)
 	})
+	t.Run("fpp", func(t *testing.T) {
+		f, err := NewFilter[[]byte](NewConfig(1<<16, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		amq.TestMeFPP(t, f, 6e4)
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, f amq.Filter[string], path string, expect int64) {
 			_ = f.Set("foobar")
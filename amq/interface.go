@@ -29,3 +29,9 @@ type Filter[T pbtk.Hashable] interface {
 	// Reset flushes the filter.
 	Reset()
 }
+
+// FPPEstimator describes filter that may estimate false positive probability at the current fill.
+type FPPEstimator interface {
+	// FPP returns current false positive probability.
+	FPP() float64
+}
@@ -2,6 +2,7 @@ package amq
 
 type MetricsWriter interface {
 	Capacity(cap uint64)
+	FPP(fpp float64)
 	Set(err error) error
 	Unset(err error) error
 	Contains(positive bool) bool
@@ -11,6 +12,7 @@ type MetricsWriter interface {
 type DummyMetricsWriter struct{}
 
 func (DummyMetricsWriter) Capacity(_ uint64)           {}
+func (DummyMetricsWriter) FPP(_ float64)               {}
 func (DummyMetricsWriter) Set(err error) error         { return err }
 func (DummyMetricsWriter) Unset(err error) error       { return err }
 func (DummyMetricsWriter) Contains(positive bool) bool { return positive }
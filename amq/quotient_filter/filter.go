@@ -2,6 +2,7 @@ package quotient
 
 import (
 	"io"
+	"math"
 	"sync"
 	"unsafe"
 
@@ -299,6 +300,18 @@ func (f *filter[T]) Size() uint64 {
 	return f.s
 }
 
+// FPP returns current false positive probability calculated from the load factor α and remainder bits r:
+// 1-e^(-α/2^r).
+func (f *filter[T]) FPP() float64 {
+	if f.once.Do(f.init); f.err != nil {
+		return 0
+	}
+	lf := float64(f.s) / float64(uint64(1)<<f.qbits)
+	fpp := -math.Expm1(-lf / float64(uint64(1)<<f.rbits))
+	f.mw().FPP(fpp)
+	return fpp
+}
+
 // Reset flushes filter data.
 func (f *filter[T]) Reset() {
 	if f.once.Do(f.init); f.err != nil {
@@ -323,6 +336,7 @@ func (f *filter[T]) WriteTo(w io.Writer) (int64, error) {
 }
 
 func (f *filter[T]) init() {
+	f.SetEncoding(f.conf.Encoding)
 	c := f.conf
 	if c.ItemsNumber == 0 {
 		f.err = amq.ErrNoItemsNumber
@@ -22,6 +22,14 @@ func TestFilter(t *testing.T) {
 	amq.TestMe(t, f)
 }
 
+func TestFilterFPP(t *testing.T) {
+	f, err := NewFilter[[]byte](NewConfig(1e5, testFPP, testh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	amq.TestMeFPP(t, f, 1e5)
+}
+
 func BenchmarkFilter(b *testing.B) {
 	f, err := NewFilter[[]byte](NewConfig(testSz, testFPP, testh))
 	if err != nil {
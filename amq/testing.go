@@ -2,6 +2,7 @@ package amq
 
 import (
 	"context"
+	"encoding/binary"
 	"math"
 	"sync"
 	"sync/atomic"
@@ -40,6 +41,36 @@ func TestMe[T []byte](t *testing.T, f Filter[T]) {
 	})
 }
 
+// TestMeFPP fills the filter with n keys and checks that estimated false positive probability matches
+// the observed one.
+func TestMeFPP(t *testing.T, f Filter[[]byte], n int) {
+	fe, ok := f.(FPPEstimator)
+	if !ok {
+		t.Fatal("filter doesn't implement FPPEstimator")
+	}
+	f.Reset()
+	if fpp := fe.FPP(); fpp != 0 {
+		t.Errorf("empty filter must have zero FPP, got %f", fpp)
+	}
+	var buf [8]byte
+	for i := 0; i < n; i++ {
+		binary.LittleEndian.PutUint64(buf[:], uint64(i))
+		_ = f.Set(buf[:])
+	}
+	const probes = 1e5
+	var fp int
+	for i := 0; i < probes; i++ {
+		binary.LittleEndian.PutUint64(buf[:], uint64(n+i))
+		if f.Contains(buf[:]) {
+			fp++
+		}
+	}
+	expect, actual := fe.FPP(), float64(fp)/probes
+	if math.Abs(expect-actual) > expect*.25+1e-3 {
+		t.Errorf("FPP estimation mismatch: expected %f, observed %f", expect, actual)
+	}
+}
+
 func TestMeConcurrently[T []byte](t *testing.T, f Filter[T]) {
 	pbtk.EachTestingDataset(func(_ int, ds *pbtk.TestingDataset[[]byte]) {
 		if len(ds.All) == 0 {
@@ -9,6 +9,8 @@ type Config struct {
 	// Hasher to calculate hash sum of the items.
 	// Mandatory param.
 	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
 	// Metrics writer handler.
 	MetricsWriter amq.MetricsWriter
 }
@@ -22,6 +24,11 @@ func (c *Config) WithMetricsWriter(mw amq.MetricsWriter) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
@@ -266,6 +266,20 @@ func (f *filter[T]) Size() uint64 {
 	return f.len
 }
 
+// FPP returns false positive probability of the filter.
+// It's fixed and depends only on fingerprint size (8 bits), since the filter builds over fixed set of keys.
+func (f *filter[T]) FPP() float64 {
+	if f.once.Do(f.init); f.err != nil {
+		return 0
+	}
+	var fpp float64
+	if f.len > 0 {
+		fpp = 1 / 256.
+	}
+	f.conf.MetricsWriter.FPP(fpp)
+	return fpp
+}
+
 func (f *filter[T]) Reset() {
 	if f.once.Do(f.init); f.err != nil || f.len == 0 {
 		return
@@ -356,6 +370,7 @@ func (f *filter[T]) ReadFrom(r io.Reader) (n int64, err error) {
 }
 
 func (f *filter[T]) tinyinit() {
+	f.SetEncoding(f.conf.Encoding)
 	if f.conf.Hasher == nil {
 		f.err = pbtk.ErrNoHasher
 		return
@@ -2,6 +2,7 @@ package xor
 
 import (
 	"math"
+	"math/rand"
 	"os"
 	"sync/atomic"
 	"testing"
@@ -40,6 +41,28 @@ func TestFilter(t *testing.T) {
 			}
 		})
 	})
+	t.Run("fpp", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		hkeys := make([]uint64, 1e5)
+		for i := 0; i < len(hkeys); i++ {
+			hkeys[i] = rng.Uint64()
+		}
+		f, err := NewFilterWithHKeys(&Config{Hasher: testh}, hkeys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		const probes = 1e6
+		var fp int
+		for i := 0; i < probes; i++ {
+			if f.HContains(rng.Uint64()) {
+				fp++
+			}
+		}
+		expect, actual := f.(amq.FPPEstimator).FPP(), float64(fp)/probes
+		if math.Abs(expect-actual) > expect*.1 {
+			t.Errorf("FPP estimation mismatch: expected %f, observed %f", expect, actual)
+		}
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, f amq.Filter[string], path string, expect int64) {
 			_ = f.Set("foobar")
@@ -1,12 +1,26 @@
 package pbtk
 
 import (
+	"encoding/binary"
 	"strconv"
 	"unicode/utf8"
 	"unsafe"
 )
 
-type Base[T Hashable] struct{}
+type Base[T Hashable] struct {
+	enc Encoding
+}
+
+// SetEncoding sets up encoding of keys before hashing.
+// Must be called before the first use, usually in structure init.
+func (b *Base[T]) SetEncoding(enc Encoding) {
+	b.enc = enc
+}
+
+// Encoding returns current keys encoding.
+func (b Base[T]) Encoding() Encoding {
+	return b.enc
+}
 
 // HashSalt calculates hash sum of data + salt using given hasher.
 func (b Base[T]) HashSalt(hasher Hasher, data T, salt uint64) (uint64, error) {
@@ -40,42 +54,22 @@ func (b Base[T]) hash(hasher Hasher, hasher128 Hasher128, data T, salt uint64, s
 	h.ptr, h.cap = uintptr(unsafe.Pointer(&a)), bufsz
 	buf := *(*[]byte)(unsafe.Pointer(&h))
 
+	if b.enc&EncodingTypeTag != 0 {
+		if buf, err = appendTypeTag(buf, data); err != nil {
+			return
+		}
+	}
 	switch x := any(data).(type) {
-	// int
-	case int:
-		buf = strconv.AppendInt(buf, int64(x), 10)
-	case int8:
-		buf = strconv.AppendInt(buf, int64(x), 10)
-	case int16:
-		buf = strconv.AppendInt(buf, int64(x), 10)
-	case int32:
-		buf = strconv.AppendInt(buf, int64(x), 10)
-	case int64:
-		buf = strconv.AppendInt(buf, x, 10)
-	// uint
-	case uint:
-		buf = strconv.AppendUint(buf, uint64(x), 10)
-	case uint8:
-		buf = strconv.AppendUint(buf, uint64(x), 10)
-	case uint16:
-		buf = strconv.AppendUint(buf, uint64(x), 10)
-	case uint32:
-		buf = strconv.AppendUint(buf, uint64(x), 10)
-	case uint64:
-		buf = strconv.AppendUint(buf, x, 10)
-	case uintptr:
-		buf = strconv.AppendUint(buf, uint64(x), 10)
-	// float
-	case float32:
-		buf = strconv.AppendFloat(buf, float64(x), 'f', -1, 32)
-	case float64:
-		buf = strconv.AppendFloat(buf, x, 'f', -1, 64)
 	// byteseq
 	case []byte:
-		if len(x) > cap(buf) {
-			buf = x
-		} else {
+		switch {
+		case len(x) <= cap(buf)-len(buf):
 			buf = append(buf, x...)
+		case len(buf) == 0:
+			buf = x
+		default:
+			// type tag already written, so the key must be copied
+			buf = append(append(make([]byte, 0, len(buf)+len(x)+24), buf...), x...)
 		}
 	case string:
 		buf = append(buf, x...)
@@ -86,11 +80,23 @@ func (b Base[T]) hash(hasher Hasher, hasher128 Hasher128, data T, salt uint64, s
 				buf = utf8.AppendRune(buf, x[i])
 			}
 		}
+	// numeric
 	default:
-		return r, ErrEncoding
+		if b.enc&EncodingBinary != 0 {
+			buf, err = appendNumBinary(buf, data)
+		} else {
+			buf, err = appendNumText(buf, data)
+		}
+		if err != nil {
+			return
+		}
 	}
 	if saltext {
-		buf = strconv.AppendUint(buf, salt, 10)
+		if b.enc&EncodingBinary != 0 {
+			buf = binary.LittleEndian.AppendUint64(buf, salt)
+		} else {
+			buf = strconv.AppendUint(buf, salt, 10)
+		}
 	}
 	switch {
 	case hasher != nil:
@@ -0,0 +1,91 @@
+package pbtk
+
+import (
+	"hash/fnv"
+	"testing"
+)
+
+type testHasher struct{}
+
+func (testHasher) Sum64(data []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	return h.Sum64()
+}
+
+func TestBaseEncoding(t *testing.T) {
+	var h testHasher
+	t.Run("text", func(t *testing.T) {
+		var bi Base[int64]
+		var bs Base[string]
+		hi, _ := bi.Hash(h, 1)
+		hs, _ := bs.Hash(h, "1")
+		if hi != hs {
+			t.Errorf("text encoding must keep legacy behavior: %d != %d", hi, hs)
+		}
+		hi, _ = bi.HashSalt(h, 1, 5)
+		if expect := h.Sum64([]byte("15")); hi != expect {
+			t.Errorf("text encoding must keep legacy salt behavior: %d != %d", hi, expect)
+		}
+	})
+	t.Run("binary", func(t *testing.T) {
+		var bi Base[int64]
+		var bs Base[string]
+		bi.SetEncoding(EncodingBinary)
+		bs.SetEncoding(EncodingBinary)
+		hi, _ := bi.Hash(h, 1)
+		hs, _ := bs.Hash(h, "1")
+		if hi == hs {
+			t.Error("binary encoding must avoid collision of int64(1) and \"1\"")
+		}
+		if expect := h.Sum64([]byte{1, 0, 0, 0, 0, 0, 0, 0}); hi != expect {
+			t.Errorf("binary encoding mismatch: %d != %d", hi, expect)
+		}
+	})
+	t.Run("type tag", func(t *testing.T) {
+		var bi Base[int64]
+		var bu Base[uint64]
+		var bs Base[string]
+		var bb Base[[]byte]
+		for _, enc := range []Encoding{EncodingTypeTag, EncodingBinary | EncodingTypeTag} {
+			bi.SetEncoding(enc)
+			bu.SetEncoding(enc)
+			bs.SetEncoding(enc)
+			bb.SetEncoding(enc)
+			hi, _ := bi.Hash(h, 1)
+			hu, _ := bu.Hash(h, 1)
+			if hi == hu {
+				t.Errorf("encoding %d: type tag must avoid collision of int64(1) and uint64(1)", enc)
+			}
+			hs, _ := bs.Hash(h, "foobar")
+			hb, _ := bb.Hash(h, []byte("foobar"))
+			if hs != hb {
+				t.Errorf("encoding %d: byte sequences must share the same tag", enc)
+			}
+			long := make([]byte, 100)
+			hb, _ = bb.Hash(h, long)
+			if expect := h.Sum64(append([]byte{tagBytes}, long...)); hb != expect {
+				t.Errorf("encoding %d: long key hash mismatch: %d != %d", enc, hb, expect)
+			}
+		}
+	})
+}
+
+func BenchmarkBaseEncoding(b *testing.B) {
+	var h testHasher
+	b.Run("text", func(b *testing.B) {
+		var bi Base[int64]
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = bi.Hash(h, int64(i))
+		}
+	})
+	b.Run("binary", func(b *testing.B) {
+		var bi Base[int64]
+		bi.SetEncoding(EncodingBinary)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = bi.Hash(h, int64(i))
+		}
+	})
+}
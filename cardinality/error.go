@@ -0,0 +1,5 @@
+package cardinality
+
+import "errors"
+
+var ErrIncompatible = errors.New("incompatible estimators")
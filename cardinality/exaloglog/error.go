@@ -0,0 +1,8 @@
+package exaloglog
+
+import "errors"
+
+var (
+	ErrInvalidPrecision  = errors.New("precision must be in range [4..18]")
+	ErrPrecisionMismatch = errors.New("precision mismatch")
+)
@@ -0,0 +1,111 @@
+package exaloglog
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Statistics of registers array required to build its log-likelihood function.
+//
+// Each register state is an observation of Poisson processes with rates λ·2^-e for each update value. Likelihood of the
+// whole registers array is exp(-λ·a)·Π(1-exp(-λ·2^-e))^b[e], where a accumulates rates of values known as not
+// updated (values greater than maximal ones and unset history bits) and b[e] counts values known as updated.
+type likelihood struct {
+	max   [256]uint64 // registers count by maximal value
+	set   [65]uint64  // updated values count by rate exponent
+	unset [65]uint64  // not updated values count by rate exponent
+}
+
+func (l *likelihood) add(r uint32, p uint64) {
+	u := uint64(r >> paramD)
+	l.max[u]++
+	if u == 0 {
+		return
+	}
+	eu := rateExp(u, p)
+	l.set[eu]++
+	if u > paramD && (u-1)>>paramT < 64-p-paramT {
+		// fast path: all history bits belong to real values of uncapped levels, thus group k has rate exponent eu-k
+		masks := &histGroups[(u-1)&(1<<paramT-1)]
+		for k := 0; k < len(masks); k++ {
+			if masks[k] == 0 {
+				continue
+			}
+			n := uint64(bits.OnesCount32(masks[k]))
+			set := uint64(bits.OnesCount32(r & masks[k]))
+			l.set[eu-uint64(k)] += set
+			l.unset[eu-uint64(k)] += n - set
+		}
+		return
+	}
+	// walk over history bits level by level, all values of the level have the same rate
+	lo := uint64(1)
+	if u > paramD {
+		lo = u - paramD
+	}
+	for v := u - 1; v >= lo && v > 0; {
+		vl := max((v-1)>>paramT<<paramT+1, lo)
+		n := v - vl + 1
+		mask := uint32(1<<n-1) << (paramD - (u - vl))
+		set := uint64(bits.OnesCount32(r & mask))
+		e := rateExp(v, p)
+		l.set[e] += set
+		l.unset[e] += n - set
+		v = vl - 1
+	}
+}
+
+// History bits grouped by levels relative to the level of maximal value u, indexed by position of u within its level.
+// Group 0 contains values of the same level as u.
+var histGroups = func() (g [1 << paramT][paramD>>paramT + 2]uint32) {
+	for f := uint64(0); f < 1<<paramT; f++ {
+		for d := uint64(1); d <= paramD; d++ {
+			// value u-d lies k levels below u
+			k := (d + (1<<paramT - 1 - f)) >> paramT
+			g[f][k] |= 1 << (paramD - d)
+		}
+	}
+	return
+}()
+
+// estimate solves maximum likelihood equation and returns number of keys per register.
+// Derivative of log-likelihood Σ(b[e]·2^-e/(exp(x·2^-e)-1)) - a decreases monotonically, so bisection by log2(x) is
+// enough.
+func (l *likelihood) estimate(p uint64) float64 {
+	var a float64
+	for u := 0; u < len(l.max); u++ {
+		if c := l.max[u]; c > 0 {
+			a += float64(c) * tailRate(uint64(u), p)
+		}
+	}
+	var empty = true
+	for e := 0; e < len(l.set); e++ {
+		a += float64(l.unset[e]) * pow2n(uint64(e))
+		empty = empty && l.set[e] == 0
+	}
+	if empty {
+		return 0
+	}
+	if a == 0 {
+		return math.Inf(1)
+	}
+	lo, hi := -70., 70.
+	for i := 0; i < 64 && hi-lo > 1e-12; i++ {
+		mid := (lo + hi) / 2
+		x := math.Exp2(mid)
+		d := -a
+		for e := 0; e < len(l.set); e++ {
+			if l.set[e] == 0 {
+				continue
+			}
+			r := pow2n(uint64(e))
+			d += float64(l.set[e]) * r / math.Expm1(x*r)
+		}
+		if d > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return math.Exp2((lo + hi) / 2)
+}
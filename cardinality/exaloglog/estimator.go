@@ -0,0 +1,167 @@
+package exaloglog
+
+import (
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+// ExaLogLog estimator implementation.
+// By default, estimator doesn't support concurrent read/write operations.
+// If you want to use concurrent read/write operations, fill up Concurrent section in Config object.
+type estimator[T pbtk.Hashable] struct {
+	pbtk.Base[T]
+	conf *Config
+	once sync.Once
+	m    float64
+	vec  vector
+
+	err error
+}
+
+func NewEstimator[T pbtk.Hashable](config *Config) (cardinality.Estimator[T], error) {
+	if config == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	e := &estimator[T]{
+		conf: config.copy(),
+	}
+	if e.once.Do(e.init); e.err != nil {
+		return nil, e.err
+	}
+	return e, nil
+}
+
+func (e *estimator[T]) Add(key T) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Add(e.err)
+	}
+	hkey, err := e.Hash(e.conf.Hasher, key)
+	if err != nil {
+		return e.mw().Add(err)
+	}
+	return e.hadd(hkey)
+}
+
+func (e *estimator[T]) HAdd(hkey uint64) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Add(e.err)
+	}
+	return e.hadd(hkey)
+}
+
+func (e *estimator[T]) hadd(hkey uint64) error {
+	p := e.conf.Precision
+	idx := hkey >> (64 - p)
+	// next t bits refine the update value
+	f := hkey >> (64 - p - paramT) & (1<<paramT - 1)
+	// fill index and refine bits with ones to limit nlz with 64-p-t
+	nlz := uint64(bits.LeadingZeros64(^(^hkey << (p + paramT))))
+	return e.mw().Add(e.vec.add(idx, nlz<<paramT+f+1))
+}
+
+func (e *estimator[T]) Estimate() uint64 {
+	if e.once.Do(e.init); e.err != nil {
+		return 0
+	}
+	return e.mw().Estimate(round(e.estimate()))
+}
+
+// EstimateWithBounds returns estimation and its bounds for given confidence level.
+// Relative standard error of ExaLogLog(2, 24) is about 0.34/√m, where m is the number of registers.
+func (e *estimator[T]) EstimateWithBounds(confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	x := e.estimate()
+	d := pbtk.ZScore(confidence) * .34 / math.Sqrt(e.m) * x
+	est, lo, hi = round(x), round(max(x-d, 0)), round(x+d)
+	e.mw().Estimate(est)
+	return
+}
+
+func (e *estimator[T]) estimate() float64 {
+	l := e.vec.likelihood()
+	return e.m * l.estimate(e.conf.Precision)
+}
+
+func round(x float64) uint64 {
+	if x >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(math.Round(x))
+}
+
+// Merge adds all keys of other ExaLogLog estimator to the current one.
+// Other estimator must have the same key type, precision and hasher.
+func (e *estimator[T]) Merge(other cardinality.Estimator[T]) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.err
+	}
+	o, ok := other.(*estimator[T])
+	if !ok {
+		return cardinality.ErrIncompatible
+	}
+	if o.once.Do(o.init); o.err != nil {
+		return o.err
+	}
+	if o.conf.Precision != e.conf.Precision {
+		return ErrPrecisionMismatch
+	}
+	regs := o.vec.registers(make([]uint32, 0, int(o.m)))
+	return e.vec.merge(regs)
+}
+
+func (e *estimator[T]) WriteTo(w io.Writer) (n int64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	return e.vec.writeTo(w)
+}
+
+func (e *estimator[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	return e.vec.readFrom(r)
+}
+
+func (e *estimator[T]) Reset() {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	e.vec.reset()
+}
+
+func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
+	p := e.conf.Precision
+	if p < 4 || p > 18 {
+		e.err = ErrInvalidPrecision
+		return
+	}
+	if e.conf.Hasher == nil {
+		e.err = pbtk.ErrNoHasher
+		return
+	}
+	if e.conf.MetricsWriter == nil {
+		e.conf.MetricsWriter = cardinality.DummyMetricsWriter{}
+	}
+
+	e.m = float64(uint64(1) << p)
+	if e.conf.Concurrent != nil {
+		e.vec = newCnvec(p, e.conf.Concurrent.WriteAttemptsLimit)
+	} else {
+		e.vec = newSyncvec(p)
+	}
+}
+
+func (e *estimator[T]) mw() cardinality.MetricsWriter {
+	return e.conf.MetricsWriter
+}
@@ -0,0 +1,208 @@
+package exaloglog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+const testP = 14
+
+var testh = xxhash.Hasher64[[]byte]{}
+
+// splitmix64 finalizer
+func testHkey(i uint64) uint64 {
+	z := (i + 1) * 0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+func TestRegister(t *testing.T) {
+	var r uint32
+	for _, k := range []uint64{10, 8, 12, 11, 40} {
+		r = update(r, k)
+	}
+	// max value 40, value 12 is 28 positions lower, so it was shifted out of history
+	if r != 40<<paramD {
+		t.Errorf("unexpected register %032b", r)
+	}
+	r = 0
+	for _, k := range []uint64{10, 8, 12, 11} {
+		r = update(r, k)
+	}
+	// max value 12, values 11, 10 and 8 are in history
+	if expect := uint32(12<<paramD | 1<<23 | 1<<22 | 1<<20); r != expect {
+		t.Errorf("unexpected register %032b, expected %032b", r, expect)
+	}
+	if m := merge(update(0, 13), r); m != 13<<paramD|1<<23|1<<22|1<<21|1<<19 {
+		t.Errorf("unexpected merged register %032b", m)
+	}
+	if m := merge(r, 0); m != r {
+		t.Errorf("unexpected merged register %032b", m)
+	}
+}
+
+func TestEstimator(t *testing.T) {
+	t.Run("sync", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(testP, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMe(t, est, 0.03)
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(testP, testh).
+			WithConcurrency().WithWriteAttemptsLimit(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeConcurrently(t, est, 0.03)
+	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(8, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeBounds(t, est, .95)
+	})
+	t.Run("writer", func(t *testing.T) {
+		testWrite := func(t *testing.T, est cardinality.Estimator[string], path string, expect int64) {
+			// use predefined hash keys to keep dump independent of hasher
+			for i := uint64(0); i < 1e5; i++ {
+				_ = est.HAdd(testHkey(i))
+			}
+			fh, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = fh.Close() }()
+			n, err := est.WriteTo(fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != expect {
+				t.Fatalf("expected %d bytes, got %d", expect, n)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testP, testh))
+			testWrite(t, f, "testdata/estimator.bin", 65560)
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testP, testh).WithConcurrency())
+			testWrite(t, f, "testdata/concurrent_estimator.bin", 65560)
+		})
+	})
+	t.Run("reader", func(t *testing.T) {
+		testRead := func(t *testing.T, est cardinality.Estimator[string], path string, expectBytes int64, expectEst uint64) {
+			fh, err := os.OpenFile(path, os.O_RDONLY, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = fh.Close() }()
+			n, err := est.ReadFrom(fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != expectBytes {
+				t.Fatalf("expected %d bytes, got %d", expectBytes, n)
+			}
+			if e := est.Estimate(); e != expectEst {
+				t.Errorf("expected %d estimate, got %d", expectEst, e)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testP, testh))
+			testRead(t, f, "testdata/estimator.bin", 65560, 100052)
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testP, testh).WithConcurrency())
+			testRead(t, f, "testdata/concurrent_estimator.bin", 65560, 100052)
+		})
+		t.Run("cross", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testP, testh).WithConcurrency())
+			testRead(t, f, "testdata/estimator.bin", 65560, 100052)
+		})
+		t.Run("precision", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testP, testh))
+			var buf bytes.Buffer
+			_, _ = f.WriteTo(&buf)
+			g, _ := NewEstimator[string](NewConfig(testP-1, testh))
+			if _, err := g.ReadFrom(&buf); err != ErrPrecisionMismatch {
+				t.Errorf("expected precision mismatch error, got %v", err)
+			}
+		})
+	})
+	t.Run("merge", func(t *testing.T) {
+		testMerge := func(t *testing.T, conf *Config) {
+			a, _ := NewEstimator[[]byte](conf)
+			b, _ := NewEstimator[[]byte](conf)
+			u, _ := NewEstimator[[]byte](conf)
+			var buf [8]byte
+			for i := uint64(0); i < 1e6; i++ {
+				binary.LittleEndian.PutUint64(buf[:], i)
+				_ = u.Add(buf[:])
+				if i%3 != 0 {
+					_ = a.Add(buf[:])
+				}
+				if i%3 != 1 {
+					_ = b.Add(buf[:])
+				}
+			}
+			if err := a.(cardinality.Merger[[]byte]).Merge(b); err != nil {
+				t.Fatal(err)
+			}
+			// merged registers must be equal to registers of union
+			if e, expect := a.Estimate(), u.Estimate(); e != expect {
+				t.Errorf("merged estimation mismatch: expected %d, got %d", expect, e)
+			}
+			if diff := math.Abs(1 - float64(a.Estimate())/1e6); diff > .03 {
+				t.Errorf("estimation too inaccurate: ratio delta need %f, got %f", .03, diff)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			testMerge(t, NewConfig(testP, testh))
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			testMerge(t, NewConfig(testP, testh).WithConcurrency())
+		})
+		t.Run("precision", func(t *testing.T) {
+			a, _ := NewEstimator[[]byte](NewConfig(testP, testh))
+			b, _ := NewEstimator[[]byte](NewConfig(testP-1, testh))
+			if err := a.(cardinality.Merger[[]byte]).Merge(b); err != ErrPrecisionMismatch {
+				t.Errorf("expected precision mismatch error, got %v", err)
+			}
+		})
+	})
+	t.Run("signature", func(t *testing.T) {
+		f, _ := NewEstimator[string](NewConfig(testP, testh))
+		if _, err := f.ReadFrom(bytes.NewReader(make([]byte, 24))); err != pbtk.ErrInvalidSignature {
+			t.Errorf("expected invalid signature error, got %v", err)
+		}
+	})
+}
+
+func BenchmarkEstimator(b *testing.B) {
+	b.Run("sync", func(b *testing.B) {
+		est, err := NewEstimator[[]byte](NewConfig(testP, testh))
+		if err != nil {
+			b.Fatal(err)
+		}
+		cardinality.BenchMe(b, est)
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		est, err := NewEstimator[[]byte](NewConfig(testP, testh).
+			WithConcurrency().WithWriteAttemptsLimit(5))
+		if err != nil {
+			b.Fatal(err)
+		}
+		cardinality.BenchMeConcurrently(b, est)
+	})
+}
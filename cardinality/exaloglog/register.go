@@ -0,0 +1,71 @@
+package exaloglog
+
+import "math"
+
+// ExaLogLog params: t - number of hash bits used to refine geometric distribution of update values, d - number of
+// history bits (update values lower than maximal one).
+const (
+	paramT = 2
+	paramD = 24
+
+	histMask = 1<<paramD - 1
+)
+
+// Register layout (32 bits): 8 high bits contain the maximal update value u, 24 low bits indicate whether values
+// u-1...u-24 were also updated (bit 23 corresponds to u-1). Zero register means empty one.
+
+// update returns register r with added update value k.
+func update(r uint32, k uint64) uint32 {
+	u := uint64(r >> paramD)
+	switch {
+	case k > u:
+		// shift history, old maximal value becomes history bit
+		hist := (r&histMask | 1<<paramD) >> (k - u)
+		if u == 0 {
+			hist = 0
+		}
+		return uint32(k)<<paramD | hist&histMask
+	case k < u && u-k <= paramD:
+		return r | 1<<(paramD-(u-k))
+	}
+	return r
+}
+
+// merge returns union of registers a and b.
+func merge(a, b uint32) uint32 {
+	if a>>paramD < b>>paramD {
+		a, b = b, a
+	}
+	if b == 0 {
+		return a
+	}
+	d := a>>paramD - b>>paramD
+	return a | (b&histMask|1<<paramD)>>d&histMask
+}
+
+// rateExp returns e that update value k has rate 2^-e.
+func rateExp(k, p uint64) uint64 {
+	j, jmax := (k-1)>>paramT, 64-p-paramT
+	if j == jmax {
+		return j + paramT
+	}
+	return j + 1 + paramT
+}
+
+// tailRate returns sum of rates of update values greater than k.
+func tailRate(k, p uint64) float64 {
+	if k == 0 {
+		return 1
+	}
+	j, jmax := (k-1)>>paramT, 64-p-paramT
+	rest := float64(1<<paramT - 1 - (k-1)&(1<<paramT-1))
+	if j == jmax {
+		return rest * pow2n(jmax+paramT)
+	}
+	return rest*pow2n(j+1+paramT) + pow2n(j+1)
+}
+
+// pow2n returns 2^-e.
+func pow2n(e uint64) float64 {
+	return math.Float64frombits((1023 - e) << 52)
+}
@@ -0,0 +1,19 @@
+package exaloglog
+
+import "io"
+
+const (
+	dumpSignature = 0x3d8f1b6ae47c0521
+	dumpVersion   = 1.0
+)
+
+// registers vector interface
+type vector interface {
+	add(idx, k uint64) error
+	merge(regs []uint32) error
+	likelihood() likelihood
+	registers(dst []uint32) []uint32
+	reset()
+	writeTo(w io.Writer) (n int64, err error)
+	readFrom(r io.Reader) (n int64, err error)
+}
@@ -0,0 +1,130 @@
+package exaloglog
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync/atomic"
+
+	"github.com/koykov/pbtk"
+)
+
+type cnvec struct {
+	p   uint64
+	lim uint64
+	buf []uint32
+}
+
+func (vec *cnvec) add(idx, k uint64) error {
+	for i := uint64(0); i < vec.lim; i++ {
+		o := atomic.LoadUint32(&vec.buf[idx])
+		n := update(o, k)
+		if n == o || atomic.CompareAndSwapUint32(&vec.buf[idx], o, n) {
+			return nil
+		}
+	}
+	return pbtk.ErrWriteLimitExceed
+}
+
+func (vec *cnvec) merge(regs []uint32) error {
+	_ = regs[len(vec.buf)-1]
+	for i := 0; i < len(vec.buf); i++ {
+		var ok bool
+		for j := uint64(0); j < vec.lim; j++ {
+			o := atomic.LoadUint32(&vec.buf[i])
+			n := merge(o, regs[i])
+			if ok = n == o || atomic.CompareAndSwapUint32(&vec.buf[i], o, n); ok {
+				break
+			}
+		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
+	}
+	return nil
+}
+
+func (vec *cnvec) likelihood() (l likelihood) {
+	for i := 0; i < len(vec.buf); i++ {
+		l.add(atomic.LoadUint32(&vec.buf[i]), vec.p)
+	}
+	return
+}
+
+func (vec *cnvec) registers(dst []uint32) []uint32 {
+	for i := 0; i < len(vec.buf); i++ {
+		dst = append(dst, atomic.LoadUint32(&vec.buf[i]))
+	}
+	return dst
+}
+
+func (vec *cnvec) reset() {
+	for i := 0; i < len(vec.buf); i++ {
+		atomic.StoreUint32(&vec.buf[i], 0)
+	}
+}
+
+func (vec *cnvec) writeTo(w io.Writer) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], vec.p)
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(vec.buf); i += blocksz / 4 {
+		off := 0
+		for j := i; j < len(vec.buf) && off < blocksz; j++ {
+			binary.LittleEndian.PutUint32(blk[off:], atomic.LoadUint32(&vec.buf[j]))
+			off += 4
+		}
+		m, err = w.Write(blk[:off])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (vec *cnvec) readFrom(r io.Reader) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if err = checkHeader(buf[:], vec.p); err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(vec.buf); i += blocksz / 4 {
+		sz := min(blocksz, (len(vec.buf)-i)*4)
+		m, err = io.ReadFull(r, blk[:sz])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		for j := 0; j < sz; j += 4 {
+			atomic.StoreUint32(&vec.buf[i+j/4], binary.LittleEndian.Uint32(blk[j:]))
+		}
+	}
+	return
+}
+
+func newCnvec(p, lim uint64) *cnvec {
+	return &cnvec{p: p, lim: lim + 1, buf: make([]uint32, 1<<p)}
+}
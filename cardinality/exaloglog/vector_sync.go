@@ -0,0 +1,125 @@
+package exaloglog
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"unsafe"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/simd/memclr64"
+)
+
+type syncvec struct {
+	p   uint64
+	buf []uint32
+}
+
+func (vec *syncvec) add(idx, k uint64) error {
+	vec.buf[idx] = update(vec.buf[idx], k)
+	return nil
+}
+
+func (vec *syncvec) merge(regs []uint32) error {
+	buf := vec.buf
+	_ = regs[len(buf)-1]
+	for i := 0; i < len(buf); i++ {
+		buf[i] = merge(buf[i], regs[i])
+	}
+	return nil
+}
+
+func (vec *syncvec) likelihood() (l likelihood) {
+	for i := 0; i < len(vec.buf); i++ {
+		l.add(vec.buf[i], vec.p)
+	}
+	return
+}
+
+func (vec *syncvec) registers(dst []uint32) []uint32 {
+	return append(dst, vec.buf...)
+}
+
+func (vec *syncvec) reset() {
+	memclr64.ClearUnsafe(unsafe.Pointer(&vec.buf[0]), len(vec.buf)*4)
+}
+
+func (vec *syncvec) writeTo(w io.Writer) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], vec.p)
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(vec.buf); i += blocksz / 4 {
+		off := 0
+		for j := i; j < len(vec.buf) && off < blocksz; j++ {
+			binary.LittleEndian.PutUint32(blk[off:], vec.buf[j])
+			off += 4
+		}
+		m, err = w.Write(blk[:off])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (vec *syncvec) readFrom(r io.Reader) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if err = checkHeader(buf[:], vec.p); err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(vec.buf); i += blocksz / 4 {
+		sz := min(blocksz, (len(vec.buf)-i)*4)
+		m, err = io.ReadFull(r, blk[:sz])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		for j := 0; j < sz; j += 4 {
+			vec.buf[i+j/4] = binary.LittleEndian.Uint32(blk[j:])
+		}
+	}
+	return
+}
+
+func checkHeader(buf []byte, p uint64) error {
+	sign, ver, p_ := binary.LittleEndian.Uint64(buf[0:8]), binary.LittleEndian.Uint64(buf[8:16]),
+		binary.LittleEndian.Uint64(buf[16:24])
+	if sign != dumpSignature {
+		return pbtk.ErrInvalidSignature
+	}
+	if ver != math.Float64bits(dumpVersion) {
+		return pbtk.ErrVersionMismatch
+	}
+	if p_ != p {
+		return ErrPrecisionMismatch
+	}
+	return nil
+}
+
+func newSyncvec(p uint64) *syncvec {
+	return &syncvec{p: p, buf: make([]uint32, 1<<p)}
+}
@@ -1,6 +1,7 @@
 package hyperbitbit
 
 import (
+	"encoding/binary"
 	"io"
 	"math"
 	"math/bits"
@@ -10,12 +11,20 @@ import (
 	"github.com/koykov/pbtk/cardinality"
 )
 
+const (
+	dumpSignature = 0x1e0a5d3c7b64f9a2
+	dumpVersion   = 1.0
+)
+
+// HyperBitBit estimator implementation.
+// By default, estimator doesn't support concurrent read/write operations.
+// If you want to use concurrent read/write operations, fill up Concurrent section in Config object.
 type estimator[T pbtk.Hashable] struct {
 	pbtk.Base[T]
-	conf   *Config
-	once   sync.Once
-	n      uint64 // lg N
-	sketch [2]uint64
+	conf *Config
+	once sync.Once
+	n0   uint64 // initial lg N
+	vec  vector
 
 	err error
 }
@@ -49,18 +58,7 @@ func (e *estimator[T]) HAdd(hkey uint64) error {
 func (e *estimator[T]) hadd(hkey uint64) error {
 	const d = 6
 	k, z := e.klz(hkey, d)
-	if z > e.n {
-		e.sketch[0] |= 1 << k
-	}
-	if z > e.n+1 {
-		e.sketch[1] |= 1 << k
-	}
-	if bits.OnesCount64(e.sketch[0]) > 31 {
-		e.sketch[0] = e.sketch[1]
-		e.sketch[1] = 0
-		e.n++
-	}
-	return e.mw().Add(nil)
+	return e.mw().Add(e.vec.add(k, z))
 }
 
 func (e *estimator[T]) klz(hkey uint64, d uint64) (k, z uint64) {
@@ -82,16 +80,45 @@ func (e *estimator[T]) Estimate() uint64 {
 	if e.once.Do(e.init); e.err != nil {
 		return 0
 	}
-	est := float64(e.n) + 5.4 + float64(bits.OnesCount64(e.sketch[0]))/32
+	st := e.vec.load()
+	est := float64(st.n) + 5.4 + float64(bits.OnesCount64(st.sketch[0]))/32
 	est = math.Pow(2, est)
 	return uint64(est)
 }
 
+// Merge adds all keys of other HyperBitBit estimator to the current one.
+// Other estimator must have the same key type and use the same hasher.
+func (e *estimator[T]) Merge(other cardinality.Estimator[T]) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.err
+	}
+	o, ok := other.(*estimator[T])
+	if !ok {
+		return cardinality.ErrIncompatible
+	}
+	if o.once.Do(o.init); o.err != nil {
+		return o.err
+	}
+	return e.vec.merge(o.vec.load())
+}
+
 func (e *estimator[T]) WriteTo(w io.Writer) (n int64, err error) {
 	if e.once.Do(e.init); e.err != nil {
 		err = e.err
 		return
 	}
+	st := e.vec.load()
+	var (
+		buf [40]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], st.n)
+	binary.LittleEndian.PutUint64(buf[24:32], st.sketch[0])
+	binary.LittleEndian.PutUint64(buf[32:40], st.sketch[1])
+	m, err = w.Write(buf[:])
+	n = int64(m)
 	return
 }
 
@@ -100,6 +127,28 @@ func (e *estimator[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		err = e.err
 		return
 	}
+	var (
+		buf [40]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n = int64(m)
+	if err != nil {
+		return
+	}
+
+	sign, ver := binary.LittleEndian.Uint64(buf[0:8]), binary.LittleEndian.Uint64(buf[8:16])
+	if sign != dumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if ver != math.Float64bits(dumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	var st state
+	st.n = binary.LittleEndian.Uint64(buf[16:24])
+	st.sketch[0] = binary.LittleEndian.Uint64(buf[24:32])
+	st.sketch[1] = binary.LittleEndian.Uint64(buf[32:40])
+	e.vec.store(st)
 	return
 }
 
@@ -107,12 +156,11 @@ func (e *estimator[T]) Reset() {
 	if e.once.Do(e.init); e.err != nil {
 		return
 	}
-	e.n = e.conf.ItemsNumber
-	e.sketch[0] = 0
-	e.sketch[1] = 0
+	e.vec.store(state{n: e.n0})
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Hasher == nil {
 		e.err = pbtk.ErrNoHasher
 		return
@@ -123,7 +171,12 @@ func (e *estimator[T]) init() {
 	if e.conf.ItemsNumber == 0 {
 		e.conf.ItemsNumber = defaultN
 	}
-	e.n = uint64(math.Log(float64(e.conf.ItemsNumber)))
+	e.n0 = uint64(math.Log(float64(e.conf.ItemsNumber)))
+	if e.conf.Concurrent != nil {
+		e.vec = newCnvec(e.n0, e.conf.Concurrent.WriteAttemptsLimit)
+	} else {
+		e.vec = newSyncvec(e.n0)
+	}
 }
 
 func (e *estimator[T]) mw() cardinality.MetricsWriter {
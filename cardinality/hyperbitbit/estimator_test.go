@@ -1,9 +1,14 @@
 package hyperbitbit
 
 import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/cardinality"
 )
 
@@ -19,6 +24,111 @@ func TestEstimator(t *testing.T) {
 		}
 		cardinality.TestMe(t, est, -1) // disable delta checking due to HBB may be too inaccurate, especial on small datasets
 	})
+	t.Run("concurrent", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(testN, testh).
+			WithConcurrency().WithWriteAttemptsLimit(5))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeConcurrently(t, est, -1)
+	})
+	t.Run("writer", func(t *testing.T) {
+		testWrite := func(t *testing.T, est cardinality.Estimator[string], path string, expect int64) {
+			// use predefined hash keys to keep dump independent of hasher
+			for i := uint64(0); i < 1e5; i++ {
+				_ = est.HAdd(i * 0x9e3779b97f4a7c15)
+			}
+			fh, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = fh.Close() }()
+			n, err := est.WriteTo(fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != expect {
+				t.Fatalf("expected %d bytes, got %d", expect, n)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testN, testh))
+			testWrite(t, f, "testdata/estimator.bin", 40)
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testN, testh).WithConcurrency())
+			testWrite(t, f, "testdata/concurrent_estimator.bin", 40)
+		})
+	})
+	t.Run("reader", func(t *testing.T) {
+		testRead := func(t *testing.T, est cardinality.Estimator[string], path string, expectBytes int64, expectEst uint64) {
+			fh, err := os.OpenFile(path, os.O_RDONLY, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = fh.Close() }()
+			n, err := est.ReadFrom(fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != expectBytes {
+				t.Fatalf("expected %d bytes, got %d", expectBytes, n)
+			}
+			if e := est.Estimate(); e != expectEst {
+				t.Errorf("expected %d estimate, got %d", expectEst, e)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testN, testh))
+			testRead(t, f, "testdata/estimator.bin", 40, 393908)
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testN, testh).WithConcurrency())
+			testRead(t, f, "testdata/concurrent_estimator.bin", 40, 393908)
+		})
+		t.Run("signature", func(t *testing.T) {
+			f, _ := NewEstimator[string](NewConfig(testN, testh))
+			if _, err := f.ReadFrom(bytes.NewReader(make([]byte, 40))); err != pbtk.ErrInvalidSignature {
+				t.Errorf("expected invalid signature error, got %v", err)
+			}
+		})
+	})
+	t.Run("merge", func(t *testing.T) {
+		testMerge := func(t *testing.T, conf *Config) {
+			a, _ := NewEstimator[[]byte](conf)
+			b, _ := NewEstimator[[]byte](conf)
+			u, _ := NewEstimator[[]byte](conf)
+			var buf [8]byte
+			for i := uint64(0); i < 1e6; i++ {
+				binary.LittleEndian.PutUint64(buf[:], i)
+				_ = u.Add(buf[:])
+				if i%2 == 0 {
+					_ = a.Add(buf[:])
+				} else {
+					_ = b.Add(buf[:])
+				}
+			}
+			if err := a.(cardinality.Merger[[]byte]).Merge(b); err != nil {
+				t.Fatal(err)
+			}
+			e, expect := float64(a.Estimate()), float64(u.Estimate())
+			if diff := math.Abs(1 - e/expect); diff > .25 {
+				t.Errorf("merged estimation too far from union: %f vs %f", e, expect)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			testMerge(t, NewConfig(testN, testh))
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			testMerge(t, NewConfig(testN, testh).WithConcurrency())
+		})
+		t.Run("incompatible", func(t *testing.T) {
+			a, _ := NewEstimator[[]byte](NewConfig(testN, testh))
+			if err := a.(cardinality.Merger[[]byte]).Merge(nil); err != cardinality.ErrIncompatible {
+				t.Errorf("expected incompatible error, got %v", err)
+			}
+		})
+	})
 }
 
 func BenchmarkEstimator(b *testing.B) {
@@ -29,4 +139,12 @@ func BenchmarkEstimator(b *testing.B) {
 		}
 		cardinality.BenchMe(b, est)
 	})
+	b.Run("concurrent", func(b *testing.B) {
+		est, err := NewEstimator[[]byte](NewConfig(testN, testh).
+			WithConcurrency().WithWriteAttemptsLimit(5))
+		if err != nil {
+			b.Fatal(err)
+		}
+		cardinality.BenchMeConcurrently(b, est)
+	})
 }
@@ -0,0 +1,58 @@
+package hyperbitbit
+
+import "math/bits"
+
+// HBB state: lg N and two sketch words.
+type state struct {
+	n      uint64
+	sketch [2]uint64
+}
+
+func (s state) add(k, z uint64) state {
+	if z > s.n {
+		s.sketch[0] |= 1 << k
+	}
+	if z > s.n+1 {
+		s.sketch[1] |= 1 << k
+	}
+	return s.shift()
+}
+
+// merge combines two states aligning them to the greater lg N.
+func (s state) merge(o state) state {
+	if o.n > s.n {
+		s, o = o, s
+	}
+	switch s.n - o.n {
+	case 0:
+		s.sketch[0] |= o.sketch[0]
+		s.sketch[1] |= o.sketch[1]
+	case 1:
+		// second word of other state describes the same level as the first word of current state
+		s.sketch[0] |= o.sketch[1]
+	default:
+		// other state contains nothing new for current levels
+	}
+	return s.shift()
+}
+
+// clone moves state copy to the heap.
+func (s state) clone() *state {
+	return &s
+}
+
+func (s state) shift() state {
+	if bits.OnesCount64(s.sketch[0]) > 31 {
+		s.sketch[0] = s.sketch[1]
+		s.sketch[1] = 0
+		s.n++
+	}
+	return s
+}
+
+type vector interface {
+	add(k, z uint64) error
+	merge(s state) error
+	load() state
+	store(s state)
+}
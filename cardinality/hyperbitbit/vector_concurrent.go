@@ -0,0 +1,56 @@
+package hyperbitbit
+
+import (
+	"sync/atomic"
+
+	"github.com/koykov/pbtk"
+)
+
+// Concurrent vector keeps state as immutable value and replaces it using CAS.
+// New state allocates only if add changes it, what happens rarely (at most 64 bits per level may be set).
+type cnvec struct {
+	p   atomic.Pointer[state]
+	lim uint64
+}
+
+func (vec *cnvec) add(k, z uint64) error {
+	for i := uint64(0); i < vec.lim; i++ {
+		o := vec.p.Load()
+		n := o.add(k, z)
+		if n == *o {
+			return nil
+		}
+		if vec.p.CompareAndSwap(o, n.clone()) {
+			return nil
+		}
+	}
+	return pbtk.ErrWriteLimitExceed
+}
+
+func (vec *cnvec) merge(s state) error {
+	for i := uint64(0); i < vec.lim; i++ {
+		o := vec.p.Load()
+		n := o.merge(s)
+		if n == *o {
+			return nil
+		}
+		if vec.p.CompareAndSwap(o, n.clone()) {
+			return nil
+		}
+	}
+	return pbtk.ErrWriteLimitExceed
+}
+
+func (vec *cnvec) load() state {
+	return *vec.p.Load()
+}
+
+func (vec *cnvec) store(s state) {
+	vec.p.Store(&s)
+}
+
+func newCnvec(n, lim uint64) *cnvec {
+	vec := &cnvec{lim: lim + 1}
+	vec.store(state{n: n})
+	return vec
+}
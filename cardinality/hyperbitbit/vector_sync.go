@@ -0,0 +1,27 @@
+package hyperbitbit
+
+type syncvec struct {
+	s state
+}
+
+func (vec *syncvec) add(k, z uint64) error {
+	vec.s = vec.s.add(k, z)
+	return nil
+}
+
+func (vec *syncvec) merge(s state) error {
+	vec.s = vec.s.merge(s)
+	return nil
+}
+
+func (vec *syncvec) load() state {
+	return vec.s
+}
+
+func (vec *syncvec) store(s state) {
+	vec.s = s
+}
+
+func newSyncvec(n uint64) *syncvec {
+	return &syncvec{s: state{n: n}}
+}
@@ -1,36 +1,40 @@
 package hyperloglog
 
 import (
+	_ "embed"
 	"encoding/binary"
 	"math"
-	"os"
 )
 
 // empirical bias correction pairs
-// loads from local binary due to huge size
+// loads from embedded binary due to huge size
 var bias [15][][2]float64
 
+//go:embed bias.bin
+var biasBin []byte
+
 func init() {
-	fh, err := os.OpenFile("bias.bin", os.O_RDONLY, 0644)
-	if err != nil {
-		return
+	buf := biasBin
+	next := func() (uint64, bool) {
+		if len(buf) < 8 {
+			return 0, false
+		}
+		v := binary.LittleEndian.Uint64(buf)
+		buf = buf[8:]
+		return v, true
 	}
-	defer func() { _ = fh.Close() }()
 	for i := 0; i < 15; i++ {
-		var buf [8]byte
-		if _, err = fh.Read(buf[:]); err != nil {
+		n, ok := next()
+		if !ok {
 			return
 		}
-		n := binary.LittleEndian.Uint64(buf[:])
+		bias[i] = make([][2]float64, 0, n)
 		for j := uint64(0); j < n; j++ {
-			if _, err = fh.Read(buf[:]); err != nil {
-				return
-			}
-			dist := binary.LittleEndian.Uint64(buf[:])
-			if _, err = fh.Read(buf[:]); err != nil {
+			dist, ok1 := next()
+			bias_, ok2 := next()
+			if !ok1 || !ok2 {
 				return
 			}
-			bias_ := binary.LittleEndian.Uint64(buf[:])
 			bias[i] = append(bias[i], [2]float64{math.Float64frombits(dist), math.Float64frombits(bias_)})
 		}
 	}
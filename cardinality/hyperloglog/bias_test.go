@@ -10,6 +10,15 @@ var biasStages = []struct {
 	{14, 189094.71188332525, 185139.77039464746},
 }
 
+func TestBiasTable(t *testing.T) {
+	// table must be available regardless of working directory
+	for i := 0; i < len(bias); i++ {
+		if len(bias[i]) == 0 {
+			t.Errorf("empty bias table for precision %d", i+4)
+		}
+	}
+}
+
 func TestBias(t *testing.T) {
 	fuzzeq := func(a, b, e float64) bool {
 		return a-e <= b && a+e >= b
@@ -2,4 +2,8 @@ package hyperloglog
 
 import "errors"
 
-var ErrInvalidPrecision = errors.New("precision must be in range [4..18]")
+var (
+	ErrInvalidPrecision  = errors.New("precision must be in range [4..18]")
+	ErrPrecisionMismatch = errors.New("precision mismatch")
+	ErrUnknownSetMethod  = errors.New("unknown set estimation method")
+)
@@ -69,7 +69,11 @@ func (e *estimator[T]) Estimate() uint64 {
 	if e.once.Do(e.init); e.err != nil || e.vec.capacity() == 0 {
 		return 0
 	}
-	est, nz := e.vec.estimate()
+	return e.mw().Estimate(uint64(e.estimate(e.vec)))
+}
+
+func (e *estimator[T]) estimate(vec vector) float64 {
+	est, nz := vec.estimate()
 
 	if est < 5*e.m {
 		est = est - biasEstimation(e.conf.Precision-4, est)
@@ -80,9 +84,41 @@ func (e *estimator[T]) Estimate() uint64 {
 		h = e.linearEstimation(nz)
 	}
 	if h <= threshold[e.conf.Precision-4] {
-		return e.mw().Estimate(uint64(h))
+		return h
+	}
+	return est
+}
+
+// EstimateWithBounds returns estimation and its bounds for given confidence level.
+// Relative standard error of HyperLogLog is 1.04/√m, where m is the number of registers.
+func (e *estimator[T]) EstimateWithBounds(confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil || e.vec.capacity() == 0 {
+		return
+	}
+	x := e.estimate(e.vec)
+	d := pbtk.ZScore(confidence) * 1.04 / math.Sqrt(e.m) * x
+	est, lo, hi = uint64(x), uint64(max(x-d, 0)), uint64(x+d)
+	e.mw().Estimate(est)
+	return
+}
+
+// Merge adds all keys of other HyperLogLog estimator to the current one.
+// Other estimator must have the same key type, precision and hasher.
+func (e *estimator[T]) Merge(other cardinality.Estimator[T]) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.err
+	}
+	o, ok := other.(*estimator[T])
+	if !ok {
+		return cardinality.ErrIncompatible
+	}
+	if o.once.Do(o.init); o.err != nil {
+		return o.err
+	}
+	if o.conf.Precision != e.conf.Precision {
+		return ErrPrecisionMismatch
 	}
-	return e.mw().Estimate(uint64(est))
+	return e.vec.merge(o.vec.registers(make([]uint8, 0, int(o.m))))
 }
 
 func (e *estimator[T]) linearEstimation(z float64) float64 {
@@ -113,6 +149,7 @@ func (e *estimator[T]) Reset() {
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Precision < 4 || e.conf.Precision > 18 {
 		e.err = ErrInvalidPrecision
 		return
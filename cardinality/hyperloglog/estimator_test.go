@@ -1,7 +1,9 @@
 package hyperloglog
 
 import (
+	"math/rand"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
@@ -28,6 +30,43 @@ func TestEstimator(t *testing.T) {
 		}
 		cardinality.TestMeConcurrently(t, est, 0.06)
 	})
+	t.Run("concurrent registers", func(t *testing.T) {
+		// concurrent estimator must estimate the same as sync estimator filled with the same keys
+		const workers, keys = 8, 1e4
+		conf := NewConfig(10, testh)
+		est, _ := NewEstimator[[]byte](conf)
+		cest, _ := NewEstimator[[]byte](conf.copy().WithConcurrency().WithWriteAttemptsLimit(100))
+		hkeys := make([]uint64, keys)
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < keys; i++ {
+			hkeys[i] = rng.Uint64()
+			_ = est.HAdd(hkeys[i])
+		}
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < keys; i += workers {
+					if err := cest.HAdd(hkeys[i]); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		if e, ce := est.Estimate(), cest.Estimate(); e != ce {
+			t.Errorf("expected %d estimate, got %d", e, ce)
+		}
+	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(10, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeBounds(t, est, .95)
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, est cardinality.Estimator[string], path string, expect int64) {
 			_ = est.Add("foobar")
@@ -0,0 +1,281 @@
+package hyperloglog
+
+import (
+	"math"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+// SetMethod represents method of set operations cardinality estimation.
+type SetMethod uint8
+
+const (
+	// SetMethodJointML uses joint maximum likelihood estimator, see https://arxiv.org/abs/1706.07290 for details.
+	// It considers registers of both sketches together and gives much more precise intersection and difference
+	// estimations, especially if sets differ in size.
+	SetMethodJointML SetMethod = iota
+	// SetMethodInclusionExclusion estimates union using merged registers and derives the rest using
+	// inclusion-exclusion principle, e.g. |A ∩ B| = |A| + |B| - |A ∪ B|. It's fast, but noisy, since errors of three
+	// estimations add up.
+	SetMethodInclusionExclusion
+)
+
+// Sets contains estimated cardinalities of two sets and their combinations.
+type Sets struct {
+	A, B         uint64 // |A|, |B|
+	Union        uint64 // |A ∪ B|
+	Intersection uint64 // |A ∩ B|
+	AMinusB      uint64 // |A \ B|
+	BMinusA      uint64 // |B \ A|
+}
+
+// EstimateSets estimates cardinalities of set operations between two HyperLogLog estimators.
+// Both estimators must have the same key type, precision and hasher.
+func EstimateSets[T pbtk.Hashable](a, b cardinality.Estimator[T], method SetMethod) (s Sets, err error) {
+	ea, ok := a.(*estimator[T])
+	if !ok {
+		return s, cardinality.ErrIncompatible
+	}
+	eb, ok := b.(*estimator[T])
+	if !ok {
+		return s, cardinality.ErrIncompatible
+	}
+	if ea.once.Do(ea.init); ea.err != nil {
+		return s, ea.err
+	}
+	if eb.once.Do(eb.init); eb.err != nil {
+		return s, eb.err
+	}
+	if ea.conf.Precision != eb.conf.Precision {
+		return s, ErrPrecisionMismatch
+	}
+	ra := ea.vec.registers(make([]uint8, 0, int(ea.m)))
+	rb := eb.vec.registers(make([]uint8, 0, int(eb.m)))
+
+	switch method {
+	case SetMethodJointML:
+		na, nb, nx := jointML(ra, rb, 64-ea.conf.Precision)
+		na, nb, nx = na*ea.m, nb*ea.m, nx*ea.m
+		s.A, s.B = round(na+nx), round(nb+nx)
+		s.Union = round(na + nb + nx)
+		s.Intersection = round(nx)
+		s.AMinusB, s.BMinusA = round(na), round(nb)
+	case SetMethodInclusionExclusion:
+		ru := make([]uint8, len(ra))
+		for i := 0; i < len(ra); i++ {
+			ru[i] = max(ra[i], rb[i])
+		}
+		na := ea.estimate(&syncvec{a: ea.a, m: ea.m, buf: ra})
+		nb := ea.estimate(&syncvec{a: ea.a, m: ea.m, buf: rb})
+		nu := ea.estimate(&syncvec{a: ea.a, m: ea.m, buf: ru})
+		nu = max(nu, na, nb)
+		s.A, s.B, s.Union = round(na), round(nb), round(nu)
+		s.Intersection = round(max(na+nb-nu, 0))
+		s.AMinusB, s.BMinusA = round(nu-nb), round(nu-na)
+	default:
+		err = ErrUnknownSetMethod
+	}
+	return
+}
+
+// Intersection estimates |A ∩ B| of two HyperLogLog estimators.
+func Intersection[T pbtk.Hashable](a, b cardinality.Estimator[T], method SetMethod) (uint64, error) {
+	s, err := EstimateSets(a, b, method)
+	return s.Intersection, err
+}
+
+// Difference estimates |A \ B| of two HyperLogLog estimators.
+func Difference[T pbtk.Hashable](a, b cardinality.Estimator[T], method SetMethod) (uint64, error) {
+	s, err := EstimateSets(a, b, method)
+	return s.AMinusB, err
+}
+
+// jointML finds rates (keys per register) of A\B, B\A and A∩B parts that maximize likelihood of registers pairs.
+//
+// Register of each sketch is a maximum of values produced by Poisson processes of its parts, e.g.
+// Ka = max(K(A\B), K(A∩B)), thus probability of registers pair (Ka, Kb) may be expressed using CDF of register value
+// F(k) = exp(-λ·2^-k) for k < q and F(q) = 1.
+func jointML(ra, rb []uint8, q uint64) (na, nb, nx float64) {
+	// registers pairs histogram
+	w := q + 1
+	hist := make([]uint64, w*w)
+	for i := 0; i < len(ra); i++ {
+		hist[uint64(ra[i])*w+uint64(rb[i])]++
+	}
+	type pair struct {
+		i, j uint64
+		c    float64
+	}
+	pairs := make([]pair, 0, 64)
+	for k, c := range hist {
+		if c > 0 {
+			pairs = append(pairs, pair{i: uint64(k) / w, j: uint64(k) % w, c: float64(c)})
+		}
+	}
+
+	// exponent of CDF: log F(k) = -λ·rate(k)
+	rate := func(k uint64) float64 {
+		if k >= q {
+			return 0
+		}
+		return math.Exp2(-float64(k))
+	}
+	// log(F(k) - F(k-1))
+	logDelta := func(l float64, k uint64) float64 {
+		switch {
+		case k == 0:
+			return -l
+		case k < q:
+			r := math.Exp2(-float64(k))
+			return -l*r + math.Log(-math.Expm1(-l*r))
+		default:
+			return math.Log(-math.Expm1(-l * math.Exp2(-float64(q-1))))
+		}
+	}
+	nll := func(y [3]float64) float64 {
+		a, b, x := math.Exp(y[0]), math.Exp(y[1]), math.Exp(y[2])
+		var ll float64
+		for _, p := range pairs {
+			var lp float64
+			switch {
+			case p.i < p.j:
+				lp = logDelta(b, p.j) + logDelta(a+x, p.i)
+			case p.i > p.j:
+				lp = logDelta(a, p.i) + logDelta(b+x, p.j)
+			default:
+				k := p.i
+				// either A∩B produced k and both A\B, B\A didn't exceed it, or both A\B and B\A produced k and A∩B
+				// is less than k
+				t1 := logDelta(x, k) - (a+b)*rate(k)
+				t2 := math.Inf(-1)
+				if k > 0 {
+					t2 = -x*rate(k-1) + logDelta(a, k) + logDelta(b, k)
+				}
+				lp = logSumExp(t1, t2)
+			}
+			ll += p.c * lp
+		}
+		return -ll
+	}
+
+	// start from inclusion-exclusion-like approximation by linear counting of registers
+	m := float64(len(ra))
+	var za, zb, zu float64
+	for i := 0; i < len(ra); i++ {
+		if ra[i] == 0 {
+			za++
+		}
+		if rb[i] == 0 {
+			zb++
+		}
+		if ra[i] == 0 && rb[i] == 0 {
+			zu++
+		}
+	}
+	lc := func(z float64) float64 { return -math.Log(max(z, .5) / m) }
+	la, lb, lu := lc(za), lc(zb), lc(zu)
+	const floor = 1e-6
+	x0 := [3]float64{
+		math.Log(max(lu-lb, floor)),
+		math.Log(max(lu-la, floor)),
+		math.Log(max(la+lb-lu, floor)),
+	}
+	y := minimize(nll, x0)
+	return math.Exp(y[0]), math.Exp(y[1]), math.Exp(y[2])
+}
+
+func logSumExp(a, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	if math.IsInf(a, -1) {
+		return a
+	}
+	return a + math.Log1p(math.Exp(b-a))
+}
+
+// minimize finds minimum of smooth function f using damped Newton method with numerical derivatives.
+func minimize(f func([3]float64) float64, y [3]float64) [3]float64 {
+	const (
+		n       = 3
+		maxIter = 100
+		h       = 1e-3
+		tol     = 1e-12
+	)
+	fy := f(y)
+	shift := func(y [3]float64, i int, di float64, j int, dj float64) [3]float64 {
+		y[i] += di
+		y[j] += dj
+		return y
+	}
+	for iter := 0; iter < maxIter; iter++ {
+		var (
+			g [n]float64
+			H [n][n]float64
+		)
+		for i := 0; i < n; i++ {
+			fp, fm := f(shift(y, i, h, i, 0)), f(shift(y, i, -h, i, 0))
+			g[i] = (fp - fm) / (2 * h)
+			H[i][i] = (fp - 2*fy + fm) / (h * h)
+			for j := 0; j < i; j++ {
+				H[i][j] = (f(shift(y, i, h, j, h)) - f(shift(y, i, h, j, -h)) -
+					f(shift(y, i, -h, j, h)) + f(shift(y, i, -h, j, -h))) / (4 * h * h)
+				H[j][i] = H[i][j]
+			}
+		}
+		// damp hessian until step becomes descent one and decreases the function
+		var (
+			mu   float64
+			done bool
+		)
+		for k := 0; k < 64; k++ {
+			A := H
+			for i := 0; i < n; i++ {
+				A[i][i] += mu
+			}
+			d, ok := solve3(A, [n]float64{-g[0], -g[1], -g[2]})
+			if ok && d[0]*g[0]+d[1]*g[1]+d[2]*g[2] < 0 {
+				yn := [n]float64{y[0] + d[0], y[1] + d[1], y[2] + d[2]}
+				if fn := f(yn); fn <= fy {
+					done = fy-fn <= tol*(math.Abs(fy)+tol)
+					y, fy = yn, fn
+					break
+				}
+			}
+			mu = max(mu*4, 1e-3*(math.Abs(H[0][0])+math.Abs(H[1][1])+math.Abs(H[2][2])+1))
+		}
+		if done {
+			break
+		}
+	}
+	return y
+}
+
+// solve3 solves 3x3 linear system A·x = b using Cramer's rule.
+func solve3(A [3][3]float64, b [3]float64) (x [3]float64, ok bool) {
+	det := func(M [3][3]float64) float64 {
+		return M[0][0]*(M[1][1]*M[2][2]-M[1][2]*M[2][1]) -
+			M[0][1]*(M[1][0]*M[2][2]-M[1][2]*M[2][0]) +
+			M[0][2]*(M[1][0]*M[2][1]-M[1][1]*M[2][0])
+	}
+	d := det(A)
+	if d == 0 || math.IsNaN(d) {
+		return x, false
+	}
+	for i := 0; i < 3; i++ {
+		M := A
+		for j := 0; j < 3; j++ {
+			M[j][i] = b[j]
+		}
+		x[i] = det(M) / d
+	}
+	return x, true
+}
+
+func round(x float64) uint64 {
+	if x <= 0 {
+		return 0
+	}
+	return uint64(math.Round(x))
+}
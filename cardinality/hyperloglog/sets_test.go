@@ -0,0 +1,114 @@
+package hyperloglog
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/koykov/pbtk/cardinality"
+)
+
+func TestSets(t *testing.T) {
+	fill := func(p uint64, na, nb, nx int) (a, b cardinality.Estimator[[]byte]) {
+		rng := rand.New(rand.NewSource(1))
+		a, _ = NewEstimator[[]byte](NewConfig(p, testh))
+		b, _ = NewEstimator[[]byte](NewConfig(p, testh))
+		for i := 0; i < na; i++ {
+			_ = a.HAdd(rng.Uint64())
+		}
+		for i := 0; i < nb; i++ {
+			_ = b.HAdd(rng.Uint64())
+		}
+		for i := 0; i < nx; i++ {
+			h := rng.Uint64()
+			_ = a.HAdd(h)
+			_ = b.HAdd(h)
+		}
+		return
+	}
+	check := func(t *testing.T, name string, expect, actual uint64, delta float64) {
+		if diff := math.Abs(1 - float64(actual)/float64(expect)); diff > delta {
+			t.Errorf("%s estimation too inaccurate: expected %d, got %d", name, expect, actual)
+		}
+	}
+	cases := []struct {
+		name       string
+		na, nb, nx int
+		delta      float64
+	}{
+		{"equal", 1e5, 1e5, 1e5, .05},
+		{"small", 100, 20, 50, .1},
+		{"skewed", 1e6, 1e5, 1e5, .1},
+	}
+	for _, c := range cases {
+		a, b := fill(14, c.na, c.nb, c.nx)
+		for _, m := range []struct {
+			name   string
+			method SetMethod
+		}{{"ml", SetMethodJointML}, {"inclusion-exclusion", SetMethodInclusionExclusion}} {
+			t.Run(c.name+"/"+m.name, func(t *testing.T) {
+				s, err := EstimateSets(a, b, m.method)
+				if err != nil {
+					t.Fatal(err)
+				}
+				check(t, "A", uint64(c.na+c.nx), s.A, c.delta)
+				check(t, "B", uint64(c.nb+c.nx), s.B, c.delta)
+				check(t, "union", uint64(c.na+c.nb+c.nx), s.Union, c.delta)
+				check(t, "intersection", uint64(c.nx), s.Intersection, c.delta)
+				check(t, "A\\B", uint64(c.na), s.AMinusB, c.delta)
+				check(t, "B\\A", uint64(c.nb), s.BMinusA, c.delta)
+			})
+		}
+	}
+	t.Run("disjoint", func(t *testing.T) {
+		a, b := fill(14, 1e5, 1e5, 0)
+		x, err := Intersection(a, b, SetMethodJointML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x > 1e3 {
+			t.Errorf("intersection of disjoint sets too big: %d", x)
+		}
+	})
+	t.Run("precision", func(t *testing.T) {
+		a, _ := NewEstimator[[]byte](NewConfig(14, testh))
+		b, _ := NewEstimator[[]byte](NewConfig(12, testh))
+		if _, err := Difference(a, b, SetMethodJointML); err != ErrPrecisionMismatch {
+			t.Errorf("expected precision mismatch error, got %v", err)
+		}
+	})
+	t.Run("merge", func(t *testing.T) {
+		a, b := fill(14, 1e5, 1e5, 1e5)
+		s, _ := EstimateSets(a, b, SetMethodInclusionExclusion)
+		if err := a.(cardinality.Merger[[]byte]).Merge(b); err != nil {
+			t.Fatal(err)
+		}
+		if e := a.Estimate(); e != s.Union {
+			t.Errorf("merged estimation mismatch: expected %d, got %d", s.Union, e)
+		}
+	})
+}
+
+func BenchmarkSets(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	x, _ := NewEstimator[[]byte](NewConfig(14, testh))
+	y, _ := NewEstimator[[]byte](NewConfig(14, testh))
+	for i := 0; i < 1e6; i++ {
+		h := rng.Uint64()
+		_ = x.HAdd(h)
+		if i%3 == 0 {
+			_ = y.HAdd(h)
+		}
+	}
+	for _, m := range []struct {
+		name   string
+		method SetMethod
+	}{{"ml", SetMethodJointML}, {"inclusion-exclusion", SetMethodInclusionExclusion}} {
+		b.Run(m.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = EstimateSets(x, y, m.method)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import "io"
 // dense vector interface
 type vector interface {
 	add(idx uint64, val uint8) error
+	merge(regs []uint8) error
+	registers(dst []uint8) []uint8
 	estimate() (float64, float64)
 	capacity() uint64
 	size() uint64
@@ -28,7 +28,7 @@ func (vec *cnvec) add(idx uint64, val uint8) error {
 		if o8 := uint8((o >> (off * 8)) & 0xff); o8 > val {
 			return nil
 		}
-		n := o | uint32(val)<<(off*8)
+		n := o&^(0xff<<(off*8)) | uint32(val)<<(off*8)
 		if atomic.CompareAndSwapUint32(&vec.buf[pos], o, n) {
 			atomic.AddUint64(&vec.s, 1)
 			return nil
@@ -37,6 +37,40 @@ func (vec *cnvec) add(idx uint64, val uint8) error {
 	return pbtk.ErrWriteLimitExceed
 }
 
+func (vec *cnvec) merge(regs []uint8) error {
+	_ = regs[int(vec.m)-1]
+	for i := 0; i < len(vec.buf); i++ {
+		var ok bool
+		for j := uint64(0); j < vec.lim; j++ {
+			o := atomic.LoadUint32(&vec.buf[i])
+			n := o
+			for k := 0; k < 4 && i*4+k < len(regs); k++ {
+				off := k * 8
+				if r := regs[i*4+k]; r > uint8(n>>off) {
+					n = n&^(0xff<<off) | uint32(r)<<off
+				}
+			}
+			if ok = n == o || atomic.CompareAndSwapUint32(&vec.buf[i], o, n); ok {
+				break
+			}
+		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
+	}
+	return nil
+}
+
+func (vec *cnvec) registers(dst []uint8) []uint8 {
+	for i := 0; i < len(vec.buf); i++ {
+		n32 := atomic.LoadUint32(&vec.buf[i])
+		for k := 0; k < 4 && i*4+k < int(vec.m); k++ {
+			dst = append(dst, uint8(n32>>(k*8)))
+		}
+	}
+	return dst
+}
+
 func (vec *cnvec) estimate() (raw, nz float64) {
 	// _, _, _ = vec.buf[len(vec.buf)-1], pow2d1[math.MaxUint8-1], nzt[math.MaxUint8-1]
 	for i := 0; i < len(vec.buf); i++ {
@@ -29,6 +29,22 @@ func (vec *syncvec) add(idx uint64, val uint8) error {
 	return nil
 }
 
+func (vec *syncvec) merge(regs []uint8) error {
+	buf := vec.buf
+	_ = regs[len(buf)-1]
+	for i := 0; i < len(buf); i++ {
+		if regs[i] > buf[i] {
+			buf[i] = regs[i]
+			vec.s++
+		}
+	}
+	return nil
+}
+
+func (vec *syncvec) registers(dst []uint8) []uint8 {
+	return append(dst, vec.buf...)
+}
+
 func (vec *syncvec) estimate() (raw, nz float64) {
 	buf := vec.buf
 	_, _, _ = buf[len(buf)-1], pow2d1[math.MaxUint8-1], nzt[math.MaxUint8-1]
@@ -19,3 +19,18 @@ type Estimator[T pbtk.Hashable] interface {
 	// Reset flushes the counter.
 	Reset()
 }
+
+// Merger describes estimator that may absorb the state of other estimator of the same type and config.
+type Merger[T pbtk.Hashable] interface {
+	// Merge adds all keys of other estimator to the current one.
+	Merge(other Estimator[T]) error
+}
+
+// BoundsEstimator describes estimator that reports error bounds of cardinality estimation.
+// Implemented by all estimators except HyperBitBit, which has no established error model.
+type BoundsEstimator interface {
+	// EstimateWithBounds returns estimation and its lower and upper bounds for given confidence level, e.g. 0.95.
+	// Bounds derive from error model of particular structure, thus true cardinality is in range [lo..hi] with given
+	// probability.
+	EstimateWithBounds(confidence float64) (est, lo, hi uint64)
+}
@@ -60,8 +60,32 @@ func (e *estimator[T]) Estimate() uint64 {
 	if e.once.Do(e.init); e.err != nil {
 		return e.mw().Estimate(0)
 	}
+	return e.mw().Estimate(uint64(e.estimate()))
+}
+
+// EstimateWithBounds returns estimation and its bounds for given confidence level.
+// Standard error of Linear Counting is √(m(e^t-t-1)), where m is the bitmap size and t is the load factor n/m.
+// Upper bound is unlimited if bitmap is full.
+func (e *estimator[T]) EstimateWithBounds(confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	x, m := e.estimate(), float64(e.m)
+	if math.IsInf(x, 1) {
+		est, lo, hi = math.MaxUint64, uint64(m), math.MaxUint64
+		e.mw().Estimate(est)
+		return
+	}
+	t := x / m
+	d := pbtk.ZScore(confidence) * math.Sqrt(m*(math.Exp(t)-t-1))
+	est, lo, hi = uint64(x), uint64(max(x-d, 0)), uint64(x+d)
+	e.mw().Estimate(est)
+	return
+}
+
+func (e *estimator[T]) estimate() float64 {
 	m, n := float64(e.m), float64(e.vec.Popcnt())
-	return e.mw().Estimate(uint64(math.Floor(math.Abs(-m * math.Log(1-n/m)))))
+	return math.Floor(math.Abs(-m * math.Log(1-n/m)))
 }
 
 func (e *estimator[T]) WriteTo(w io.Writer) (n int64, err error) {
@@ -88,6 +112,7 @@ func (e *estimator[T]) Reset() {
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Hasher == nil {
 		e.err = pbtk.ErrNoHasher
 		return
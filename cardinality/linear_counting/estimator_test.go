@@ -28,6 +28,13 @@ func TestEstimator(t *testing.T) {
 		}
 		cardinality.TestMeConcurrently(t, est, 0.05)
 	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(1e5, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeBounds(t, est, .95)
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, est cardinality.Estimator[string], path string, expect int64) {
 			_ = est.Add("foobar")
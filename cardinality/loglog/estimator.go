@@ -61,8 +61,25 @@ func (e *estimator[T]) Estimate() uint64 {
 	if e.once.Do(e.init); e.err != nil {
 		return e.mw().Estimate(0)
 	}
+	return e.mw().Estimate(uint64(e.estimate()))
+}
+
+// EstimateWithBounds returns estimation and its bounds for given confidence level.
+// Relative standard error of LogLog is 1.30/√m, where m is the number of registers.
+func (e *estimator[T]) EstimateWithBounds(confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	x := e.estimate()
+	d := pbtk.ZScore(confidence) * 1.30 / math.Sqrt(e.m) * x
+	est, lo, hi = uint64(x), uint64(max(x-d, 0)), uint64(x+d)
+	e.mw().Estimate(est)
+	return
+}
+
+func (e *estimator[T]) estimate() float64 {
 	raw, nz := e.vec.estimate()
-	return e.mw().Estimate(uint64(e.a * e.m * (e.m - nz) / (betaEstimation(nz) + raw)))
+	return e.a * e.m * (e.m - nz) / (betaEstimation(nz) + raw)
 }
 
 func (e *estimator[T]) WriteTo(w io.Writer) (n int64, err error) {
@@ -89,6 +106,7 @@ func (e *estimator[T]) Reset() {
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Precision < 4 || e.conf.Precision > 18 {
 		e.err = ErrInvalidPrecision
 		return
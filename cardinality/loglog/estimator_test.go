@@ -31,6 +31,13 @@ func TestEstimator(t *testing.T) {
 		}
 		cardinality.TestMeConcurrently(t, est, testD)
 	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(10, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeBounds(t, est, .95)
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, est cardinality.Estimator[string], path string, expect int64) {
 			_ = est.Add("foobar")
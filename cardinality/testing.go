@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"math"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -54,6 +55,41 @@ func TestMe[T []byte](t *testing.T, est Estimator[T], delta float64) {
 	})
 }
 
+// TestMeBounds checks that estimation bounds cover true cardinality with given confidence.
+// Estimator must implement BoundsEstimator interface.
+func TestMeBounds[T []byte](t *testing.T, est Estimator[T], confidence float64) {
+	best, ok := est.(BoundsEstimator)
+	if !ok {
+		t.Fatal("estimator doesn't implement BoundsEstimator")
+	}
+	const (
+		trials = 50
+		uniq   = 5e4
+	)
+	rng := rand.New(rand.NewSource(1))
+	var covered int
+	for i := 0; i < trials; i++ {
+		est.Reset()
+		for j := 0; j < uniq; j++ {
+			_ = est.HAdd(rng.Uint64())
+		}
+		e, lo, hi := best.EstimateWithBounds(confidence)
+		if lo > e || e > hi {
+			t.Fatalf("estimation %d out of bounds [%d..%d]", e, lo, hi)
+		}
+		if _, lo1, hi1 := best.EstimateWithBounds(confidence / 2); lo1 < lo || hi1 > hi {
+			t.Fatalf("bounds [%d..%d] of lower confidence must be narrower than [%d..%d]", lo1, hi1, lo, hi)
+		}
+		if lo <= uniq && uniq <= hi {
+			covered++
+		}
+	}
+	// allow some slack since number of trials is small
+	if ratio := float64(covered) / trials; ratio < confidence-.1 {
+		t.Errorf("bounds coverage too low: need %f, got %f", confidence, ratio)
+	}
+}
+
 func TestMeConcurrently[T []byte](t *testing.T, est Estimator[T], delta float64) {
 	t.Run("distinct counting", func(t *testing.T) {
 		est.Reset()
@@ -136,7 +172,7 @@ func TestMeConcurrently[T []byte](t *testing.T, est Estimator[T], delta float64)
 			e := est.Estimate()
 			ratio := float64(e) / float64(len(ds.All))
 			diff := math.Abs(1 - ratio)
-			if diff > delta {
+			if delta >= 0 && diff > delta {
 				t.Errorf("estimation too inaccurate: ratio delta need %f, got %f", delta, diff)
 			}
 		})
@@ -0,0 +1,54 @@
+package theta
+
+import (
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+const defaultK = 4096
+
+type Config struct {
+	// Nominal entries number - how many minimal hash values sketch keeps.
+	// Relative standard error of estimation is about 1/√K.
+	// If this param omitted, defaultK (4096) will use instead.
+	K uint64
+	// Hasher to calculate hash sum of the items.
+	// Mandatory param.
+	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
+	// Metrics writer handler.
+	MetricsWriter cardinality.MetricsWriter
+}
+
+func NewConfig(k uint64, hasher pbtk.Hasher) *Config {
+	return &Config{
+		K:      k,
+		Hasher: hasher,
+	}
+}
+
+func (c *Config) WithK(k uint64) *Config {
+	c.K = k
+	return c
+}
+
+func (c *Config) WithHasher(hasher pbtk.Hasher) *Config {
+	c.Hasher = hasher
+	return c
+}
+
+func (c *Config) WithMetricsWriter(mw cardinality.MetricsWriter) *Config {
+	c.MetricsWriter = mw
+	return c
+}
+
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
+func (c *Config) copy() *Config {
+	cpy := *c
+	return &cpy
+}
@@ -0,0 +1,8 @@
+package theta
+
+import "errors"
+
+var (
+	ErrInvalidK  = errors.New("nominal entries number must be at least 16")
+	ErrKMismatch = errors.New("nominal entries number mismatch")
+)
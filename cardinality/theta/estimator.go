@@ -0,0 +1,264 @@
+package theta
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+const (
+	dumpSignature = 0x51c6e8d2a09b3f47
+	dumpVersion   = 1.0
+)
+
+// Theta sketch (KMV - K minimal values) implementation.
+// Sketch keeps K minimal hash values and threshold theta - all hashes greater or equal to it are skipped.
+// Estimator is race protected: adding of hashes above theta (the most frequent case on large datasets) is lock-free,
+// the rest operations are protected by mutex.
+type estimator[T pbtk.Hashable] struct {
+	pbtk.Base[T]
+	conf  *Config
+	once  sync.Once
+	theta uint64
+	mux   sync.Mutex
+	tab   table
+
+	err error
+}
+
+func NewEstimator[T pbtk.Hashable](config *Config) (Estimator[T], error) {
+	if config == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	e := &estimator[T]{
+		conf: config.copy(),
+	}
+	if e.once.Do(e.init); e.err != nil {
+		return nil, e.err
+	}
+	return e, nil
+}
+
+func (e *estimator[T]) Add(key T) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Add(e.err)
+	}
+	hkey, err := e.Hash(e.conf.Hasher, key)
+	if err != nil {
+		return e.mw().Add(err)
+	}
+	return e.hadd(hkey)
+}
+
+func (e *estimator[T]) HAdd(hkey uint64) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Add(e.err)
+	}
+	return e.hadd(hkey)
+}
+
+func (e *estimator[T]) hadd(hkey uint64) error {
+	if hkey >= atomic.LoadUint64(&e.theta) {
+		return e.mw().Add(nil)
+	}
+	e.mux.Lock()
+	e.haddLF(hkey)
+	e.mux.Unlock()
+	return e.mw().Add(nil)
+}
+
+// haddLF adds hash value to the table, must be called under lock.
+func (e *estimator[T]) haddLF(hkey uint64) {
+	if hkey == 0 {
+		hkey = 1 // zero marks empty slot
+	}
+	if hkey >= e.theta {
+		return
+	}
+	if e.tab.add(hkey) && e.tab.full() {
+		atomic.StoreUint64(&e.theta, e.tab.rebuild())
+	}
+}
+
+func (e *estimator[T]) Estimate() uint64 {
+	if e.once.Do(e.init); e.err != nil {
+		return 0
+	}
+	e.mux.Lock()
+	c, theta := e.tab.c, e.theta
+	e.mux.Unlock()
+	est, _, _ := bounds(c, theta, 0)
+	return e.mw().Estimate(est)
+}
+
+func (e *estimator[T]) EstimateWithBounds(confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	e.mux.Lock()
+	c, theta := e.tab.c, e.theta
+	e.mux.Unlock()
+	est, lo, hi = bounds(c, theta, confidence)
+	e.mw().Estimate(est)
+	return
+}
+
+// Snapshot returns immutable compact copy of the sketch.
+func (e *estimator[T]) Snapshot() *Snapshot {
+	if e.once.Do(e.init); e.err != nil {
+		return &Snapshot{theta: math.MaxUint64}
+	}
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	s := &Snapshot{theta: e.theta, hashes: e.tab.values(make([]uint64, 0, e.tab.c))}
+	slices.Sort(s.hashes)
+	return s
+}
+
+// Merge adds all keys of other theta sketch to the current one.
+func (e *estimator[T]) Merge(other cardinality.Estimator[T]) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.err
+	}
+	o, ok := other.(*estimator[T])
+	if !ok {
+		return cardinality.ErrIncompatible
+	}
+	s := o.Snapshot()
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if s.theta < e.theta {
+		// drop own hashes that other sketch can't confirm
+		vals := e.tab.values(make([]uint64, 0, e.tab.c))
+		e.tab.reset()
+		atomic.StoreUint64(&e.theta, s.theta)
+		for i := 0; i < len(vals); i++ {
+			e.haddLF(vals[i])
+		}
+	}
+	for i := 0; i < len(s.hashes); i++ {
+		e.haddLF(s.hashes[i])
+	}
+	return nil
+}
+
+func (e *estimator[T]) WriteTo(w io.Writer) (n int64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	s := e.Snapshot()
+	var (
+		buf [40]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], e.conf.K)
+	binary.LittleEndian.PutUint64(buf[24:32], s.theta)
+	binary.LittleEndian.PutUint64(buf[32:40], uint64(len(s.hashes)))
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(s.hashes); i += blocksz / 8 {
+		off := 0
+		for j := i; j < len(s.hashes) && off < blocksz; j++ {
+			binary.LittleEndian.PutUint64(blk[off:], s.hashes[j])
+			off += 8
+		}
+		m, err = w.Write(blk[:off])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (e *estimator[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	var (
+		buf [40]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	sign, ver, k, theta, c := binary.LittleEndian.Uint64(buf[0:8]), binary.LittleEndian.Uint64(buf[8:16]),
+		binary.LittleEndian.Uint64(buf[16:24]), binary.LittleEndian.Uint64(buf[24:32]),
+		binary.LittleEndian.Uint64(buf[32:40])
+	if sign != dumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if ver != math.Float64bits(dumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	if k != e.conf.K {
+		return n, ErrKMismatch
+	}
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.tab.reset()
+	atomic.StoreUint64(&e.theta, theta)
+	var b [8]byte
+	for i := uint64(0); i < c; i++ {
+		m, err = io.ReadFull(r, b[:])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		e.haddLF(binary.LittleEndian.Uint64(b[:]))
+	}
+	return
+}
+
+func (e *estimator[T]) Reset() {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	e.mux.Lock()
+	e.tab.reset()
+	atomic.StoreUint64(&e.theta, math.MaxUint64)
+	e.mux.Unlock()
+}
+
+func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
+	if e.conf.K == 0 {
+		e.conf.K = defaultK
+	}
+	if e.conf.K < 16 {
+		e.err = ErrInvalidK
+		return
+	}
+	if e.conf.Hasher == nil {
+		e.err = pbtk.ErrNoHasher
+		return
+	}
+	if e.conf.MetricsWriter == nil {
+		e.conf.MetricsWriter = cardinality.DummyMetricsWriter{}
+	}
+	e.theta = math.MaxUint64
+	e.tab.init(e.conf.K)
+}
+
+func (e *estimator[T]) mw() cardinality.MetricsWriter {
+	return e.conf.MetricsWriter
+}
@@ -0,0 +1,190 @@
+package theta
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+const testK = 4096
+
+var testh = xxhash.Hasher64[[]byte]{}
+
+func TestEstimator(t *testing.T) {
+	t.Run("sync", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(testK, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMe(t, est, 0.05)
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(testK, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeConcurrently(t, est, 0.05)
+	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(1024, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cardinality.TestMeBounds(t, est, .95)
+	})
+	t.Run("exact", func(t *testing.T) {
+		est, _ := NewEstimator[uint64](NewConfig(testK, testh))
+		for i := uint64(0); i < testK; i++ {
+			_ = est.Add(i)
+			_ = est.Add(i)
+		}
+		if e, lo, hi := est.EstimateWithBounds(.99); e != testK || lo != testK || hi != testK {
+			t.Errorf("expected exact estimation %d, got %d [%d..%d]", testK, e, lo, hi)
+		}
+	})
+	t.Run("writer", func(t *testing.T) {
+		testWrite := func(t *testing.T, est cardinality.Estimator[string], path string, expect int64) {
+			rng := rand.New(rand.NewSource(1))
+			for i := 0; i < 1e5; i++ {
+				_ = est.HAdd(rng.Uint64())
+			}
+			fh, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() { _ = fh.Close() }()
+			n, err := est.WriteTo(fh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != expect {
+				t.Fatalf("expected %d bytes, got %d", expect, n)
+			}
+		}
+		f, _ := NewEstimator[string](NewConfig(256, testh))
+		testWrite(t, f, "testdata/estimator.bin", 3264)
+	})
+	t.Run("reader", func(t *testing.T) {
+		fh, err := os.OpenFile("testdata/estimator.bin", os.O_RDONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = fh.Close() }()
+		est, _ := NewEstimator[string](NewConfig(256, testh))
+		n, err := est.ReadFrom(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 3264 {
+			t.Fatalf("expected %d bytes, got %d", 3264, n)
+		}
+		if e := est.Estimate(); e != 99292 {
+			t.Errorf("expected %d estimate, got %d", 99292, e)
+		}
+		g, _ := NewEstimator[string](NewConfig(512, testh))
+		_, _ = fh.Seek(0, 0)
+		if _, err = g.ReadFrom(fh); err != ErrKMismatch {
+			t.Errorf("expected K mismatch error, got %v", err)
+		}
+		if _, err = g.ReadFrom(bytes.NewReader(make([]byte, 40))); err != pbtk.ErrInvalidSignature {
+			t.Errorf("expected invalid signature error, got %v", err)
+		}
+	})
+	t.Run("merge", func(t *testing.T) {
+		a, _ := NewEstimator[[]byte](NewConfig(testK, testh))
+		b, _ := NewEstimator[[]byte](NewConfig(testK, testh))
+		u, _ := NewEstimator[[]byte](NewConfig(testK, testh))
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 1e6; i++ {
+			h := rng.Uint64()
+			_ = u.HAdd(h)
+			if i%3 != 0 {
+				_ = a.HAdd(h)
+			}
+			if i%3 != 1 {
+				_ = b.HAdd(h)
+			}
+		}
+		if err := a.Merge(b); err != nil {
+			t.Fatal(err)
+		}
+		// sketches may retain different number of hashes, so compare with tolerance
+		if e, expect := a.Estimate(), u.Estimate(); math.Abs(1-float64(e)/float64(expect)) > .02 {
+			t.Errorf("merged estimation mismatch: expected %d, got %d", expect, e)
+		}
+	})
+}
+
+func TestSetOperations(t *testing.T) {
+	const na, nb, nx = 4e5, 2e5, 1e5
+	a, _ := NewEstimator[[]byte](NewConfig(testK, testh))
+	b, _ := NewEstimator[[]byte](NewConfig(testK, testh))
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < na; i++ {
+		_ = a.HAdd(rng.Uint64())
+	}
+	for i := 0; i < nb; i++ {
+		_ = b.HAdd(rng.Uint64())
+	}
+	for i := 0; i < nx; i++ {
+		h := rng.Uint64()
+		_ = a.HAdd(h)
+		_ = b.HAdd(h)
+	}
+	sa, sb := a.Snapshot(), b.Snapshot()
+	check := func(t *testing.T, s *Snapshot, expect uint64) {
+		e, lo, hi := s.EstimateWithBounds(.999)
+		if expect < lo || expect > hi {
+			t.Errorf("expected value %d is out of bounds %d [%d..%d]", expect, e, lo, hi)
+		}
+		if diff := math.Abs(1 - float64(e)/float64(expect)); diff > .1 {
+			t.Errorf("estimation too inaccurate: expected %d, got %d", expect, e)
+		}
+	}
+	t.Run("union", func(t *testing.T) {
+		s := Union(testK, sa, sb)
+		if s.Len() != testK {
+			t.Errorf("expected %d retained hashes, got %d", testK, s.Len())
+		}
+		check(t, s, na+nb+nx)
+	})
+	t.Run("intersection", func(t *testing.T) {
+		check(t, Intersection(sa, sb), nx)
+	})
+	t.Run("difference", func(t *testing.T) {
+		check(t, Difference(sa, sb), na)
+		check(t, Difference(sb, sa), nb)
+	})
+	t.Run("empty", func(t *testing.T) {
+		c, _ := NewEstimator[[]byte](NewConfig(testK, testh))
+		if e := Intersection(sa, c.Snapshot()).Estimate(); e != 0 {
+			t.Errorf("expected empty intersection, got %d", e)
+		}
+		if e := Union(0, sa, c.Snapshot()).Estimate(); e != sa.Estimate() {
+			t.Errorf("expected union equal to A %d, got %d", sa.Estimate(), e)
+		}
+	})
+}
+
+func BenchmarkEstimator(b *testing.B) {
+	b.Run("sync", func(b *testing.B) {
+		est, err := NewEstimator[[]byte](NewConfig(testK, testh))
+		if err != nil {
+			b.Fatal(err)
+		}
+		cardinality.BenchMe(b, est)
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		est, err := NewEstimator[[]byte](NewConfig(testK, testh))
+		if err != nil {
+			b.Fatal(err)
+		}
+		cardinality.BenchMeConcurrently(b, est)
+	})
+}
@@ -0,0 +1,15 @@
+package theta
+
+import (
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/cardinality"
+)
+
+// Estimator describes theta sketch interface.
+type Estimator[T pbtk.Hashable] interface {
+	cardinality.Estimator[T]
+	cardinality.Merger[T]
+	cardinality.BoundsEstimator
+	// Snapshot returns immutable compact copy of the sketch to use in set operations.
+	Snapshot() *Snapshot
+}
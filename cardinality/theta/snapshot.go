@@ -0,0 +1,129 @@
+package theta
+
+import (
+	"math"
+	"slices"
+
+	"github.com/koykov/pbtk"
+)
+
+// Snapshot is an immutable compact theta sketch: threshold and sorted list of retained hash values.
+// Snapshots are operands and results of set operations.
+type Snapshot struct {
+	theta  uint64
+	hashes []uint64
+}
+
+// Theta returns sampling probability of the sketch, 1 means that sketch contains all hashes (exact mode).
+func (s *Snapshot) Theta() float64 {
+	return thetaf(s.theta)
+}
+
+// Len returns number of retained hash values.
+func (s *Snapshot) Len() int {
+	return len(s.hashes)
+}
+
+// Estimate returns approximate number of unique keys.
+func (s *Snapshot) Estimate() uint64 {
+	est, _, _ := bounds(uint64(len(s.hashes)), s.theta, 0)
+	return est
+}
+
+// EstimateWithBounds returns estimation and its lower and upper bounds for given confidence level, e.g. 0.95.
+func (s *Snapshot) EstimateWithBounds(confidence float64) (est, lo, hi uint64) {
+	return bounds(uint64(len(s.hashes)), s.theta, confidence)
+}
+
+// Union returns union of given snapshots.
+// Param k limits the number of retained hashes of the result; zero k means no limit.
+func Union(k uint64, ss ...*Snapshot) *Snapshot {
+	r := &Snapshot{theta: minTheta(ss)}
+	var n int
+	for _, s := range ss {
+		n += len(s.hashes)
+	}
+	r.hashes = make([]uint64, 0, n)
+	for _, s := range ss {
+		for _, h := range s.hashes {
+			if h < r.theta {
+				r.hashes = append(r.hashes, h)
+			}
+		}
+	}
+	slices.Sort(r.hashes)
+	r.hashes = slices.Compact(r.hashes)
+	if k > 0 && uint64(len(r.hashes)) > k {
+		r.theta = r.hashes[k]
+		r.hashes = r.hashes[:k]
+	}
+	return r
+}
+
+// Intersection returns intersection of given snapshots.
+func Intersection(ss ...*Snapshot) *Snapshot {
+	r := &Snapshot{theta: minTheta(ss)}
+	if len(ss) == 0 {
+		return r
+	}
+	for _, h := range ss[0].hashes {
+		if h >= r.theta {
+			break
+		}
+		ok := true
+		for _, s := range ss[1:] {
+			if _, ok = slices.BinarySearch(s.hashes, h); !ok {
+				break
+			}
+		}
+		if ok {
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	return r
+}
+
+// Difference returns difference a \ b.
+func Difference(a, b *Snapshot) *Snapshot {
+	r := &Snapshot{theta: min(a.theta, b.theta)}
+	for _, h := range a.hashes {
+		if h >= r.theta {
+			break
+		}
+		if _, ok := slices.BinarySearch(b.hashes, h); !ok {
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	return r
+}
+
+func minTheta(ss []*Snapshot) uint64 {
+	theta := uint64(math.MaxUint64)
+	for _, s := range ss {
+		theta = min(theta, s.theta)
+	}
+	return theta
+}
+
+// thetaf converts threshold to sampling probability.
+func thetaf(theta uint64) float64 {
+	if theta == math.MaxUint64 {
+		return 1
+	}
+	return float64(theta) / (1 << 64)
+}
+
+// bounds calculates estimation of sketch with c retained hashes and given threshold.
+// Number of retained hashes is approximately binomially distributed with n trials and probability θ, thus estimation
+// c/θ has standard deviation √(c·(1-θ))/θ. Bounds are calculated using normal approximation, lower bound can't be less
+// than c, since c keys are observed exactly.
+func bounds(c, theta uint64, confidence float64) (est, lo, hi uint64) {
+	t := thetaf(theta)
+	e := float64(c) / t
+	est = uint64(math.Round(e))
+	z := pbtk.ZScore(confidence)
+	d := z * math.Sqrt(float64(c)*(1-t)) / t
+	lo = uint64(math.Round(max(e-d, float64(c))))
+	hi = uint64(math.Round(e + d))
+	return
+}
@@ -0,0 +1,66 @@
+package theta
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// Open addressing hash table of retained hash values. Zero value marks empty slot.
+type table struct {
+	k   uint64
+	buf []uint64
+	c   uint64
+}
+
+func (t *table) init(k uint64) {
+	t.k = k
+	// keep load factor at most 1/2 before rebuild
+	sz := uint64(1) << (bits.Len64(2*k-1) + 1)
+	t.buf = make([]uint64, sz)
+}
+
+// add inserts h to the table and returns true if h wasn't present.
+func (t *table) add(h uint64) bool {
+	mask := uint64(len(t.buf) - 1)
+	for i := h & mask; ; i = (i + 1) & mask {
+		switch t.buf[i] {
+		case h:
+			return false
+		case 0:
+			t.buf[i] = h
+			t.c++
+			return true
+		}
+	}
+}
+
+// full checks if table must be rebuilt.
+func (t *table) full() bool {
+	return t.c >= uint64(len(t.buf))/2
+}
+
+// rebuild keeps k minimal values and returns new theta.
+func (t *table) rebuild() uint64 {
+	vals := t.values(make([]uint64, 0, t.c))
+	slices.Sort(vals)
+	theta := vals[t.k]
+	t.reset()
+	for i := uint64(0); i < t.k; i++ {
+		t.add(vals[i])
+	}
+	return theta
+}
+
+func (t *table) values(dst []uint64) []uint64 {
+	for i := 0; i < len(t.buf); i++ {
+		if t.buf[i] != 0 {
+			dst = append(dst, t.buf[i])
+		}
+	}
+	return dst
+}
+
+func (t *table) reset() {
+	clear(t.buf)
+	t.c = 0
+}
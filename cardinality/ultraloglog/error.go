@@ -0,0 +1,9 @@
+package ultraloglog
+
+import "errors"
+
+var (
+	ErrInvalidPrecision  = errors.New("precision must be in range [4..18]")
+	ErrUnknownMethod     = errors.New("unknown estimation method")
+	ErrPrecisionMismatch = errors.New("precision mismatch")
+)
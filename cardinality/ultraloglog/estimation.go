@@ -0,0 +1,171 @@
+package ultraloglog
+
+import "math"
+
+// FGRA estimator params, see https://arxiv.org/abs/2308.16862 for details.
+const fgraTau = 0.8194911375910897
+
+// Register contribution coefficients depending on two low bits of the register.
+var fgraEta = [4]float64{4.663135422063788, 2.1378502137958524, 2.781144650979996, 0.9824082545153715}
+
+// Mean value of λ^τ·E[g(r)] over the period of log2(λ), where λ is a number of keys per register. It allows to invert
+// the sum of registers contributions back to λ.
+var fgraZeta = fgraCalcZeta()
+
+func fgraCalcZeta() float64 {
+	const samples = 256
+	var sum float64
+	for s := 0; s < samples; s++ {
+		lambda := math.Exp2(32 + float64(s)/samples)
+		var eg float64
+		// levels far from log2(λ) are negligible
+		for j := 3; j < 96; j++ {
+			mu := lambda * math.Exp2(-float64(j))
+			pmax := math.Exp(-mu) * -math.Expm1(-mu)
+			for b := 0; b < 4; b++ {
+				p := pmax
+				if b&2 != 0 {
+					p *= -math.Expm1(-2 * mu)
+				} else {
+					p *= math.Exp(-2 * mu)
+				}
+				if b&1 != 0 {
+					p *= -math.Expm1(-4 * mu)
+				} else {
+					p *= math.Exp(-4 * mu)
+				}
+				eg += p * fgraEta[b] * math.Exp2(-fgraTau*float64(j))
+			}
+		}
+		sum += eg * math.Pow(lambda, fgraTau)
+	}
+	return sum / samples
+}
+
+// fgraTable builds registers contributions table for given precision.
+// Contribution is defined only for registers with known both low bits and uncapped update position, otherwise it's zero
+// and estimator must fall back to ML.
+func fgraTable(p uint64) (t [256]float64) {
+	for r := 0; r < 256; r++ {
+		u := uint64(r >> 2)
+		if u < p+1 || u > 61 {
+			continue
+		}
+		t[r] = fgraEta[r&3] * math.Exp2(-fgraTau*float64(u-p+2))
+	}
+	return
+}
+
+// fgraApplicable checks if registers histogram doesn't contain registers that FGRA can't handle.
+func fgraApplicable(hist *[256]uint64, p uint64) bool {
+	lo, hi := (p+1)<<2, uint64(62<<2)
+	for r := uint64(0); r < lo; r++ {
+		if hist[r] > 0 {
+			return false
+		}
+	}
+	for r := hi; r < 256; r++ {
+		if hist[r] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func fgraEstimate(hist *[256]uint64, table *[256]float64, m float64) float64 {
+	var sum float64
+	for r := 0; r < 256; r++ {
+		if c := hist[r]; c > 0 {
+			sum += float64(c) * table[r]
+		}
+	}
+	return m * math.Pow(m*fgraZeta/sum, 1/fgraTau)
+}
+
+// mlEstimate solves maximum likelihood equation for registers histogram.
+//
+// Each register state is an observation of Poisson processes with rates λ·2^-e for each update position. Likelihood of
+// the whole registers array is exp(-λ·a)·Π(1-exp(-λ·2^-e))^b[e], where a accumulates rates of positions known as not
+// updated and b[e] counts positions known as updated.
+func mlEstimate(hist *[256]uint64, p uint64, m float64) float64 {
+	var (
+		a float64
+		b [65]float64
+	)
+	for r := 0; r < 256; r++ {
+		c := hist[r]
+		if c == 0 {
+			continue
+		}
+		cf := float64(c)
+		if r == 0 {
+			a += cf // no position updated
+			continue
+		}
+		u := uint64(r >> 2)
+		if u < 63 {
+			a += cf * math.Exp2(-float64(u-p+2)) // tail of positions greater than u
+		}
+		b[rateExp(u, p)] += cf
+		if q := u - 1; q >= p-1 {
+			if r&2 != 0 {
+				b[rateExp(q, p)] += cf
+			} else {
+				a += cf * math.Exp2(-float64(rateExp(q, p)))
+			}
+		}
+		if q := u - 2; q >= p-1 {
+			if r&1 != 0 {
+				b[rateExp(q, p)] += cf
+			} else {
+				a += cf * math.Exp2(-float64(rateExp(q, p)))
+			}
+		}
+	}
+	if a == 0 {
+		return math.Inf(1)
+	}
+	return m * mlSolve(a, b[:])
+}
+
+// rateExp returns e that update position q has rate 2^-e.
+func rateExp(q, p uint64) uint64 {
+	if q == 63 {
+		return 64 - p
+	}
+	return q - p + 2
+}
+
+// mlSolve finds root of likelihood derivative Σ(b[e]·2^-e/(exp(x·2^-e)-1)) - a = 0.
+// Derivative decreases monotonically, so bisection by log2(x) is enough.
+func mlSolve(a float64, b []float64) float64 {
+	var empty = true
+	for i := 0; i < len(b); i++ {
+		if b[i] > 0 {
+			empty = false
+			break
+		}
+	}
+	if empty {
+		return 0
+	}
+	lo, hi := -70., 70.
+	for i := 0; i < 64 && hi-lo > 1e-12; i++ {
+		mid := (lo + hi) / 2
+		x := math.Exp2(mid)
+		d := -a
+		for e := 0; e < len(b); e++ {
+			if b[e] == 0 {
+				continue
+			}
+			r := math.Exp2(-float64(e))
+			d += b[e] * r / math.Expm1(x*r)
+		}
+		if d > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return math.Exp2((lo + hi) / 2)
+}
@@ -0,0 +1,34 @@
+package ultraloglog
+
+import "math/bits"
+
+// Register layout (8 bits): 6 high bits contain the maximal update position u, 2 low bits indicate whether positions u-1
+// and u-2 were also updated. Zero register means empty one. Update position is p-1+nlz, where nlz is a number of
+// leading zeros of the hash bits remaining after index, thus u-2 is always non-negative.
+
+// unpack converts register to the bitmask of updated positions (known part of).
+func unpack(r uint8) uint64 {
+	if r == 0 {
+		return 0
+	}
+	return (4 | uint64(r&3)) << (r>>2 - 2)
+}
+
+// pack converts bitmask of updated positions to the register.
+func pack(mask uint64) uint8 {
+	if mask == 0 {
+		return 0
+	}
+	u := uint8(63 - bits.LeadingZeros64(mask))
+	return u<<2 | uint8(mask>>(u-2))&3
+}
+
+// update returns register r with added position pos.
+func update(r uint8, pos uint64) uint8 {
+	return pack(unpack(r) | 1<<pos)
+}
+
+// merge returns union of registers a and b.
+func merge(a, b uint8) uint8 {
+	return pack(unpack(a) | unpack(b))
+}
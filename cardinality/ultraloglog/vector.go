@@ -0,0 +1,19 @@
+package ultraloglog
+
+import "io"
+
+const (
+	dumpSignature = 0x7a6ac3d1e0b59f10
+	dumpVersion   = 1.0
+)
+
+// registers vector interface
+type vector interface {
+	add(idx, pos uint64) error
+	merge(regs []uint8) error
+	histogram() [256]uint64
+	registers(dst []uint8) []uint8
+	reset()
+	writeTo(w io.Writer) (n int64, err error)
+	readFrom(r io.Reader) (n int64, err error)
+}
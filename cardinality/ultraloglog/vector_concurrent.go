@@ -0,0 +1,145 @@
+package ultraloglog
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync/atomic"
+
+	"github.com/koykov/pbtk"
+)
+
+// Concurrent vector packs 4 registers to each uint32 word and updates them using CAS.
+type cnvec struct {
+	p   uint64
+	lim uint64
+	buf []uint32
+}
+
+func (vec *cnvec) add(idx, pos uint64) error {
+	i, off := idx/4, idx%4*8
+	for j := uint64(0); j < vec.lim; j++ {
+		o := atomic.LoadUint32(&vec.buf[i])
+		r := uint8(o >> off)
+		nr := update(r, pos)
+		if nr == r {
+			return nil
+		}
+		n := o&^(0xff<<off) | uint32(nr)<<off
+		if atomic.CompareAndSwapUint32(&vec.buf[i], o, n) {
+			return nil
+		}
+	}
+	return pbtk.ErrWriteLimitExceed
+}
+
+func (vec *cnvec) merge(regs []uint8) error {
+	_ = regs[len(vec.buf)*4-1]
+	for i := 0; i < len(vec.buf); i++ {
+		var ok bool
+		for j := uint64(0); j < vec.lim; j++ {
+			o := atomic.LoadUint32(&vec.buf[i])
+			var n uint32
+			for k := 0; k < 4; k++ {
+				off := k * 8
+				n |= uint32(merge(uint8(o>>off), regs[i*4+k])) << off
+			}
+			if ok = n == o || atomic.CompareAndSwapUint32(&vec.buf[i], o, n); ok {
+				break
+			}
+		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
+	}
+	return nil
+}
+
+func (vec *cnvec) histogram() (hist [256]uint64) {
+	for i := 0; i < len(vec.buf); i++ {
+		w := atomic.LoadUint32(&vec.buf[i])
+		hist[w&0xff]++
+		hist[w>>8&0xff]++
+		hist[w>>16&0xff]++
+		hist[w>>24]++
+	}
+	return
+}
+
+func (vec *cnvec) registers(dst []uint8) []uint8 {
+	for i := 0; i < len(vec.buf); i++ {
+		dst = binary.LittleEndian.AppendUint32(dst, atomic.LoadUint32(&vec.buf[i]))
+	}
+	return dst
+}
+
+func (vec *cnvec) reset() {
+	for i := 0; i < len(vec.buf); i++ {
+		atomic.StoreUint32(&vec.buf[i], 0)
+	}
+}
+
+func (vec *cnvec) writeTo(w io.Writer) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], vec.p)
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(vec.buf); i += blocksz / 4 {
+		off := 0
+		for j := i; j < len(vec.buf) && off < blocksz; j++ {
+			binary.LittleEndian.PutUint32(blk[off:], atomic.LoadUint32(&vec.buf[j]))
+			off += 4
+		}
+		m, err = w.Write(blk[:off])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (vec *cnvec) readFrom(r io.Reader) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if err = checkHeader(buf[:], vec.p); err != nil {
+		return
+	}
+
+	const blocksz = 4096
+	var blk [blocksz]byte
+	for i := 0; i < len(vec.buf); i += blocksz / 4 {
+		sz := min(blocksz, (len(vec.buf)-i)*4)
+		m, err = io.ReadFull(r, blk[:sz])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		for j := 0; j < sz; j += 4 {
+			atomic.StoreUint32(&vec.buf[i+j/4], binary.LittleEndian.Uint32(blk[j:]))
+		}
+	}
+	return
+}
+
+func newCnvec(p, lim uint64) *cnvec {
+	return &cnvec{p: p, lim: lim + 1, buf: make([]uint32, (1<<p)/4)}
+}
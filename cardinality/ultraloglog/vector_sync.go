@@ -0,0 +1,101 @@
+package ultraloglog
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/simd/memclr64"
+)
+
+type syncvec struct {
+	p   uint64
+	buf []uint8
+}
+
+func (vec *syncvec) add(idx, pos uint64) error {
+	vec.buf[idx] = update(vec.buf[idx], pos)
+	return nil
+}
+
+func (vec *syncvec) merge(regs []uint8) error {
+	buf := vec.buf
+	_ = regs[len(buf)-1]
+	for i := 0; i < len(buf); i++ {
+		buf[i] = merge(buf[i], regs[i])
+	}
+	return nil
+}
+
+func (vec *syncvec) histogram() (hist [256]uint64) {
+	for i := 0; i < len(vec.buf); i++ {
+		hist[vec.buf[i]]++
+	}
+	return
+}
+
+func (vec *syncvec) registers(dst []uint8) []uint8 {
+	return append(dst, vec.buf...)
+}
+
+func (vec *syncvec) reset() {
+	memclr64.ClearBytes(vec.buf)
+}
+
+func (vec *syncvec) writeTo(w io.Writer) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], vec.p)
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	m, err = w.Write(vec.buf)
+	n += int64(m)
+	return
+}
+
+func (vec *syncvec) readFrom(r io.Reader) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if err = checkHeader(buf[:], vec.p); err != nil {
+		return
+	}
+
+	m, err = io.ReadFull(r, vec.buf)
+	n += int64(m)
+	return
+}
+
+func checkHeader(buf []byte, p uint64) error {
+	sign, ver, p_ := binary.LittleEndian.Uint64(buf[0:8]), binary.LittleEndian.Uint64(buf[8:16]),
+		binary.LittleEndian.Uint64(buf[16:24])
+	if sign != dumpSignature {
+		return pbtk.ErrInvalidSignature
+	}
+	if ver != math.Float64bits(dumpVersion) {
+		return pbtk.ErrVersionMismatch
+	}
+	if p_ != p {
+		return ErrPrecisionMismatch
+	}
+	return nil
+}
+
+func newSyncvec(p uint64) *syncvec {
+	return &syncvec{p: p, buf: make([]uint8, 1<<p)}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/amq"
+	"github.com/koykov/pbtk/amq/bloom_filter"
+	"github.com/koykov/pbtk/amq/cuckoo_filter"
+	"github.com/koykov/pbtk/amq/quotient_filter"
+	"github.com/koykov/pbtk/amq/xor_filter"
+)
+
+type amqImpl struct {
+	name string
+	// Filter is parametrized by FPP.
+	fpp bool
+	// Filter is static, i.e. builds from the whole set of keys.
+	static bool
+	new    func(n uint64, fpp float64, keys [][]byte) (amq.Filter[[]byte], error)
+}
+
+var amqImpls = []amqImpl{
+	{name: "bloom_filter", fpp: true, new: func(n uint64, fpp float64, _ [][]byte) (amq.Filter[[]byte], error) {
+		return bloom.NewFilter[[]byte](bloom.NewConfig(n, fpp, hasher))
+	}},
+	{name: "bloom_filter/counting", fpp: true, new: func(n uint64, fpp float64, _ [][]byte) (amq.Filter[[]byte], error) {
+		return bloom.NewCountingFilter[[]byte](bloom.NewConfig(n, fpp, hasher))
+	}},
+	{name: "quotient_filter", fpp: true, new: func(n uint64, fpp float64, _ [][]byte) (amq.Filter[[]byte], error) {
+		return quotient.NewFilter[[]byte](quotient.NewConfig(n, fpp, hasher))
+	}},
+	{name: "cuckoo_filter", new: func(n uint64, _ float64, _ [][]byte) (amq.Filter[[]byte], error) {
+		return cuckoo.NewFilter[[]byte](cuckoo.NewConfig(n, hasher))
+	}},
+	{name: "xor_filter", static: true, new: func(_ uint64, _ float64, keys [][]byte) (amq.Filter[[]byte], error) {
+		return xor.NewFilterWithKeys[[]byte](xor.NewConfig(hasher), keys)
+	}},
+}
+
+// evalAMQ fills filters with n distinct keys and probes n other keys. Error is the observed false positive rate.
+func evalAMQ(o *options) (rs []result) {
+	for _, n := range o.n {
+		keys := distinctKeys(o.seed, n, 1)
+		negs := absentKeys(o.seed, n)
+		for _, impl := range amqImpls {
+			fpps := o.fpp
+			if !impl.fpp {
+				fpps = []float64{0}
+			}
+			for _, fpp := range fpps {
+				rs = append(rs, evalFilter(impl, n, fpp, keys, negs))
+			}
+		}
+	}
+	return
+}
+
+func evalFilter(impl amqImpl, n uint64, fpp float64, keys, negs [][]byte) result {
+	r := result{Domain: "amq", Impl: impl.name, Workload: fmt.Sprintf("uniq=%d", n), Metric: "fpp"}
+	if impl.fpp {
+		r.Params = fmt.Sprintf("fpp=%g", fpp)
+	}
+
+	var (
+		f   amq.Filter[[]byte]
+		err error
+	)
+	h0 := liveHeap()
+	if impl.static {
+		t := measure(1, func(int) { f, err = impl.new(n, fpp, keys) })
+		r.setWrite(throughput{ops: t.ops * float64(len(keys)), allocs: t.allocs / float64(len(keys)),
+			bytes: t.bytes / float64(len(keys))})
+	} else {
+		if f, err = impl.new(n, fpp, keys); err == nil {
+			r.setWrite(measure(len(keys), func(i int) {
+				if err1 := f.Set(keys[i]); err1 != nil && err == nil {
+					err = err1
+				}
+			}))
+		}
+	}
+	if err != nil {
+		return r.fail(err)
+	}
+	r.MemBytes = heapDelta(h0)
+	r.DumpBytes = dumpSize(f)
+
+	var fp, fn int
+	r.ReadOps = measure(len(negs), func(i int) {
+		if f.Contains(negs[i]) {
+			fp++
+		}
+	}).ops
+	for i := 0; i < len(keys); i++ {
+		if !f.Contains(keys[i]) {
+			fn++
+		}
+	}
+	r.Error = float64(fp) / float64(len(negs))
+	if fn > 0 {
+		return r.fail(fmt.Errorf("%d false negatives", fn))
+	}
+	runtime.KeepAlive(f)
+	return r
+}
+
+// distinctKeys returns keys of cardinality stream.
+func distinctKeys(seed int64, uniq, repeats uint64) [][]byte {
+	s := pbtk.NewCardinalityStream(seed, uniq, repeats)
+	keys := make([][]byte, 0, s.Len())
+	for {
+		key, ok := s.AppendNext(nil)
+		if !ok {
+			return keys
+		}
+		keys = append(keys, key)
+	}
+}
+
+// absentKeys returns n keys that don't occur in cardinality stream of the same seed.
+func absentKeys(seed int64, n uint64) [][]byte {
+	w := pbtk.NewWords(seed)
+	keys := make([][]byte, 0, n)
+	for i := uint64(0); i < n; i++ {
+		keys = append(keys, w.AppendWord(nil, n+i))
+	}
+	return keys
+}
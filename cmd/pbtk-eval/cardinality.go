@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+
+	"github.com/koykov/pbtk/cardinality"
+	"github.com/koykov/pbtk/cardinality/exaloglog"
+	"github.com/koykov/pbtk/cardinality/hyperbitbit"
+	"github.com/koykov/pbtk/cardinality/hyperloglog"
+	"github.com/koykov/pbtk/cardinality/linear_counting"
+	"github.com/koykov/pbtk/cardinality/loglog"
+	"github.com/koykov/pbtk/cardinality/theta"
+	"github.com/koykov/pbtk/cardinality/ultraloglog"
+)
+
+// Number of times each key repeats in cardinality workload.
+const cardinalityRepeats = 3
+
+type cardinalityImpl struct {
+	name string
+	// Estimator is parametrized by precision, otherwise by expected number of items.
+	precision bool
+	new       func(p, n uint64) (cardinality.Estimator[[]byte], error)
+}
+
+var cardinalityImpls = []cardinalityImpl{
+	{name: "hyperloglog", precision: true, new: func(p, _ uint64) (cardinality.Estimator[[]byte], error) {
+		return hyperloglog.NewEstimator[[]byte](hyperloglog.NewConfig(p, hasher))
+	}},
+	{name: "loglog", precision: true, new: func(p, _ uint64) (cardinality.Estimator[[]byte], error) {
+		return loglog.NewEstimator[[]byte](loglog.NewConfig(p, hasher))
+	}},
+	{name: "ultraloglog", precision: true, new: func(p, _ uint64) (cardinality.Estimator[[]byte], error) {
+		return ultraloglog.NewEstimator[[]byte](ultraloglog.NewConfig(p, hasher))
+	}},
+	{name: "exaloglog", precision: true, new: func(p, _ uint64) (cardinality.Estimator[[]byte], error) {
+		return exaloglog.NewEstimator[[]byte](exaloglog.NewConfig(p, hasher))
+	}},
+	{name: "theta", precision: true, new: func(p, _ uint64) (cardinality.Estimator[[]byte], error) {
+		// sketch of 2^p entries
+		return theta.NewEstimator[[]byte](theta.NewConfig(1<<p, hasher))
+	}},
+	{name: "linear_counting", new: func(_, n uint64) (cardinality.Estimator[[]byte], error) {
+		return linear.NewEstimator[[]byte](linear.NewConfig(n, hasher))
+	}},
+	{name: "hyperbitbit", new: func(_, n uint64) (cardinality.Estimator[[]byte], error) {
+		return hyperbitbit.NewEstimator[[]byte](hyperbitbit.NewConfig(n, hasher))
+	}},
+}
+
+// evalCardinality adds n distinct keys (each repeats several times) to estimators. Error is the relative error of
+// estimation.
+func evalCardinality(o *options) (rs []result) {
+	for _, n := range o.n {
+		keys := distinctKeys(o.seed, n, cardinalityRepeats)
+		for _, impl := range cardinalityImpls {
+			ps := o.precision
+			if !impl.precision {
+				ps = []uint64{0}
+			}
+			for _, p := range ps {
+				rs = append(rs, evalCardinalityEstimator(impl, p, n, keys))
+			}
+		}
+	}
+	return
+}
+
+func evalCardinalityEstimator(impl cardinalityImpl, p, n uint64, keys [][]byte) result {
+	r := result{
+		Domain:   "cardinality",
+		Impl:     impl.name,
+		Params:   fmt.Sprintf("items=%d", n),
+		Workload: fmt.Sprintf("uniq=%d,repeats=%d", n, cardinalityRepeats),
+		Metric:   "rel_err",
+	}
+	if impl.precision {
+		r.Params = fmt.Sprintf("precision=%d", p)
+	}
+
+	h0 := liveHeap()
+	est, err := impl.new(p, n)
+	if err != nil {
+		return r.fail(err)
+	}
+	r.setWrite(measure(len(keys), func(i int) {
+		if err1 := est.Add(keys[i]); err1 != nil && err == nil {
+			err = err1
+		}
+	}))
+	if err != nil {
+		return r.fail(err)
+	}
+	r.MemBytes = heapDelta(h0)
+	r.DumpBytes = dumpSize(est)
+
+	var e uint64
+	r.ReadOps = measure(100, func(int) { e = est.Estimate() }).ops
+	r.Error = math.Abs(float64(e)-float64(n)) / float64(max(n, 1))
+	runtime.KeepAlive(est)
+	return r
+}
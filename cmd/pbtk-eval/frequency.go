@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/frequency/cmsketch"
+	"github.com/koykov/pbtk/frequency/countsketch"
+	"github.com/koykov/pbtk/frequency/cusketch"
+	"github.com/koykov/pbtk/frequency/dlcsketch"
+	"github.com/koykov/pbtk/frequency/dyadic"
+	"github.com/koykov/pbtk/frequency/tinylfu"
+	tinylfuewma "github.com/koykov/pbtk/frequency/tinylfu_ewma"
+)
+
+// frequencyEstimator adapts estimators of different types. Keys pass both as bytes and as ranks, since dyadic
+// estimator works with integer keys only.
+type frequencyEstimator struct {
+	add func(key []byte, rank uint64) error
+	est func(key []byte, rank uint64) float64
+	x   io.WriterTo
+}
+
+type frequencyImpl struct {
+	name string
+	new  func(confidence, epsilon float64) (*frequencyEstimator, error)
+}
+
+func cmsConfig(confidence, epsilon float64) *cmsketch.Config {
+	return cmsketch.NewConfig(confidence, epsilon, hasher)
+}
+
+var frequencyImpls = []frequencyImpl{
+	{name: "cmsketch", new: func(c, e float64) (*frequencyEstimator, error) {
+		est, err := cmsketch.NewEstimator[[]byte](cmsConfig(c, e))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(key []byte, _ uint64) error { return est.Add(key) },
+			est: func(key []byte, _ uint64) float64 { return float64(est.Estimate(key)) },
+			x:   est,
+		}, nil
+	}},
+	{name: "cusketch", new: func(c, e float64) (*frequencyEstimator, error) {
+		est, err := cusketch.NewEstimator[[]byte](cmsConfig(c, e))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(key []byte, _ uint64) error { return est.Add(key) },
+			est: func(key []byte, _ uint64) float64 { return float64(est.Estimate(key)) },
+			x:   est,
+		}, nil
+	}},
+	{name: "dlcsketch", new: func(c, e float64) (*frequencyEstimator, error) {
+		est, err := dlcsketch.NewEstimator[[]byte](cmsConfig(c, e))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(key []byte, _ uint64) error { return est.Add(key) },
+			est: func(key []byte, _ uint64) float64 { return float64(est.Estimate(key)) },
+			x:   est,
+		}, nil
+	}},
+	{name: "countsketch", new: func(c, e float64) (*frequencyEstimator, error) {
+		est, err := countsketch.NewEstimator[[]byte](countsketch.NewConfig(c, e, hasher))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(key []byte, _ uint64) error { return est.Add(key) },
+			est: func(key []byte, _ uint64) float64 { return float64(est.Estimate(key)) },
+			x:   est,
+		}, nil
+	}},
+	{name: "tinylfu", new: func(c, e float64) (*frequencyEstimator, error) {
+		// decay isn't configured, so estimator counts the whole stream
+		est, err := tinylfu.NewEstimator[[]byte](tinylfu.NewConfig(c, e, hasher))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(key []byte, _ uint64) error { return est.Add(key) },
+			est: func(key []byte, _ uint64) float64 { return float64(est.Estimate(key)) },
+			x:   est,
+		}, nil
+	}},
+	{name: "tinylfu_ewma", new: func(c, e float64) (*frequencyEstimator, error) {
+		// huge smoothing constant makes decay negligible during evaluation
+		est, err := tinylfuewma.NewEstimator[[]byte](tinylfuewma.NewConfig(c, e, hasher).WithEWMATau(1 << 40))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(key []byte, _ uint64) error { return est.Add(key) },
+			est: func(key []byte, _ uint64) float64 { return est.Estimate(key) },
+			x:   est,
+		}, nil
+	}},
+	{name: "dyadic", new: func(c, e float64) (*frequencyEstimator, error) {
+		est, err := dyadic.NewEstimator[uint64](dyadic.NewConfig(c, e, hasher))
+		if err != nil {
+			return nil, err
+		}
+		return &frequencyEstimator{
+			add: func(_ []byte, rank uint64) error { return est.Add(rank) },
+			est: func(_ []byte, rank uint64) float64 { return float64(est.Estimate(rank)) },
+			x:   est,
+		}, nil
+	}},
+}
+
+// zipfWorkload is a Zipfian stream materialized to measure throughput without generation overhead.
+type zipfWorkload struct {
+	name  string
+	keys  [][]byte
+	ranks []uint64
+	// True frequencies of keys by rank.
+	freqs []uint64
+}
+
+func newZipfWorkload(o *options, n uint64) *zipfWorkload {
+	s := pbtk.NewZipfStream(o.seed, o.zipf, o.universe, n)
+	wl := &zipfWorkload{
+		name:  fmt.Sprintf("zipf=%g,universe=%d,len=%d", o.zipf, o.universe, n),
+		keys:  make([][]byte, 0, n),
+		ranks: make([]uint64, 0, n),
+		freqs: make([]uint64, max(o.universe, 1)),
+	}
+	// keys of the same rank share memory
+	ukeys := make([][]byte, len(wl.freqs))
+	for {
+		rank, ok := s.NextRank()
+		if !ok {
+			return wl
+		}
+		if ukeys[rank] == nil {
+			ukeys[rank] = s.AppendKey(nil, rank)
+		}
+		wl.keys, wl.ranks = append(wl.keys, ukeys[rank]), append(wl.ranks, rank)
+		wl.freqs[rank]++
+	}
+}
+
+// evalFrequency processes Zipfian stream of length n. Error is the mean absolute error of estimation of distinct keys
+// divided by stream length, i.e. comparable with epsilon.
+func evalFrequency(o *options) (rs []result) {
+	for _, n := range o.n {
+		wl := newZipfWorkload(o, n)
+		for _, impl := range frequencyImpls {
+			for _, eps := range o.epsilon {
+				rs = append(rs, evalFrequencyEstimator(impl, o.confidence, eps, wl))
+			}
+		}
+	}
+	return
+}
+
+func evalFrequencyEstimator(impl frequencyImpl, confidence, epsilon float64, wl *zipfWorkload) result {
+	r := result{
+		Domain:   "frequency",
+		Impl:     impl.name,
+		Params:   fmt.Sprintf("confidence=%g,epsilon=%g", confidence, epsilon),
+		Workload: wl.name,
+		Metric:   "mae/len",
+	}
+
+	h0 := liveHeap()
+	est, err := impl.new(confidence, epsilon)
+	if err != nil {
+		return r.fail(err)
+	}
+	r.setWrite(measure(len(wl.keys), func(i int) {
+		if err1 := est.add(wl.keys[i], wl.ranks[i]); err1 != nil && err == nil {
+			err = err1
+		}
+	}))
+	if err != nil {
+		return r.fail(err)
+	}
+	r.MemBytes = heapDelta(h0)
+	r.DumpBytes = dumpSize(est.x)
+
+	var sum, cnt float64
+	r.ReadOps = measure(len(wl.keys), func(i int) { _ = est.est(wl.keys[i], wl.ranks[i]) }).ops
+	seen := make([]bool, len(wl.freqs))
+	for i := 0; i < len(wl.keys); i++ {
+		rank := wl.ranks[i]
+		if seen[rank] {
+			continue
+		}
+		seen[rank] = true
+		sum += math.Abs(est.est(wl.keys[i], rank) - float64(wl.freqs[rank]))
+		cnt++
+	}
+	if cnt > 0 {
+		r.Error = sum / cnt / float64(len(wl.keys))
+	}
+	runtime.KeepAlive(est)
+	return r
+}
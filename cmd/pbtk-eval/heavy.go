@@ -0,0 +1,121 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"runtime"
+	"slices"
+
+	"github.com/koykov/pbtk/heavy"
+	"github.com/koykov/pbtk/heavy/lossy"
+	"github.com/koykov/pbtk/heavy/misragries"
+	"github.com/koykov/pbtk/heavy/spacesaving"
+)
+
+// Number of the most frequent keys to check.
+const heavyTop = 10
+
+type heavyImpl struct {
+	name string
+	// Hitter is parametrized by epsilon, otherwise by number of counters.
+	epsilon bool
+	new     func(k uint64, epsilon float64) (heavy.Hitter[[]byte], error)
+}
+
+var heavyImpls = []heavyImpl{
+	{name: "lossy", epsilon: true, new: func(_ uint64, eps float64) (heavy.Hitter[[]byte], error) {
+		// support is the lowest meaningful one for given epsilon
+		return lossy.NewHitter[[]byte](lossy.NewConfig(eps, 2*eps, hasher))
+	}},
+	{name: "misragries", new: func(k uint64, _ float64) (heavy.Hitter[[]byte], error) {
+		return misragries.NewHitter[[]byte](misragries.NewConfig(k, hasher))
+	}},
+	{name: "spacesaving", new: func(k uint64, _ float64) (heavy.Hitter[[]byte], error) {
+		return spacesaving.NewHitter[[]byte](spacesaving.NewConfig(k, hasher))
+	}},
+}
+
+// evalHeavy processes Zipfian stream of length n. Error is the share of the top 10 keys missed in hits.
+func evalHeavy(o *options) (rs []result) {
+	for _, n := range o.n {
+		wl := newZipfWorkload(o, n)
+		top := wl.top(heavyTop)
+		for _, impl := range heavyImpls {
+			if impl.epsilon {
+				for _, eps := range o.epsilon {
+					rs = append(rs, evalHitter(impl, 0, eps, wl, top))
+				}
+				continue
+			}
+			for _, k := range o.counters {
+				rs = append(rs, evalHitter(impl, k, 0, wl, top))
+			}
+		}
+	}
+	return
+}
+
+func evalHitter(impl heavyImpl, k uint64, epsilon float64, wl *zipfWorkload, top []string) result {
+	r := result{
+		Domain:   "heavy",
+		Impl:     impl.name,
+		Params:   fmt.Sprintf("k=%d", k),
+		Workload: wl.name,
+		Metric:   fmt.Sprintf("miss@%d", heavyTop),
+	}
+	if impl.epsilon {
+		r.Params = fmt.Sprintf("epsilon=%g", epsilon)
+	}
+
+	h0 := liveHeap()
+	h, err := impl.new(k, epsilon)
+	if err != nil {
+		return r.fail(err)
+	}
+	r.setWrite(measure(len(wl.keys), func(i int) {
+		if err1 := h.Add(wl.keys[i]); err1 != nil && err == nil {
+			err = err1
+		}
+	}))
+	if err != nil {
+		return r.fail(err)
+	}
+	r.MemBytes = heapDelta(h0)
+
+	var hits []heavy.Hit[[]byte]
+	r.ReadOps = measure(100, func(int) { hits = h.AppendHits(hits[:0]) }).ops
+	var found int
+	for i := 0; i < len(top); i++ {
+		if slices.ContainsFunc(hits, func(hit heavy.Hit[[]byte]) bool { return string(hit.Key) == top[i] }) {
+			found++
+		}
+	}
+	if len(top) > 0 {
+		r.Error = 1 - float64(found)/float64(len(top))
+	}
+	runtime.KeepAlive(h)
+	return r
+}
+
+// top returns n the most frequent keys of the workload.
+func (wl *zipfWorkload) top(n int) []string {
+	ranks := make([]uint64, 0, len(wl.freqs))
+	keys := make(map[uint64][]byte, len(wl.freqs))
+	for i := 0; i < len(wl.keys); i++ {
+		if _, ok := keys[wl.ranks[i]]; !ok {
+			keys[wl.ranks[i]] = wl.keys[i]
+			ranks = append(ranks, wl.ranks[i])
+		}
+	}
+	slices.SortFunc(ranks, func(a, b uint64) int {
+		if c := cmp.Compare(wl.freqs[b], wl.freqs[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	r := make([]string, 0, n)
+	for i := 0; i < min(n, len(ranks)); i++ {
+		r = append(r, string(keys[ranks[i]]))
+	}
+	return r
+}
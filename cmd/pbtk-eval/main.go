@@ -0,0 +1,192 @@
+// Command pbtk-eval evaluates accuracy and performance of probabilistic structures over synthetic workloads.
+//
+// Each implementation runs across all combinations of given params. Report contains empirical error, memory footprint,
+// throughput and allocations of each run:
+//
+//	pbtk-eval -domains cardinality -precision 10,12,14 -n 1e4,1e6 -format md
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type options struct {
+	domains    listFlag
+	n          uintsFlag
+	seed       int64
+	zipf       float64
+	universe   uint64
+	pairs      int
+	words      int
+	fpp        floatsFlag
+	precision  uintsFlag
+	epsilon    floatsFlag
+	confidence float64
+	counters   uintsFlag
+	hashes     uintsFlag
+	bits       uintsFlag
+}
+
+func defaultOptions() options {
+	return options{
+		domains:    listFlag(domainsOrder),
+		n:          uintsFlag{1e5},
+		seed:       1,
+		zipf:       1.1,
+		universe:   1e4,
+		pairs:      500,
+		words:      50,
+		fpp:        floatsFlag{.01, .001},
+		precision:  uintsFlag{10, 12, 14},
+		epsilon:    floatsFlag{.01, .001},
+		confidence: .99,
+		counters:   uintsFlag{64, 256},
+		hashes:     uintsFlag{64, 256},
+		bits:       uintsFlag{1, 4, 8},
+	}
+}
+
+type evaluator func(o *options) []result
+
+var domains = map[string]evaluator{
+	"amq":         evalAMQ,
+	"cardinality": evalCardinality,
+	"frequency":   evalFrequency,
+	"heavy":       evalHeavy,
+	"similarity":  evalSimilarity,
+	"symmetric":   evalSymmetric,
+}
+
+var domainsOrder = []string{"amq", "cardinality", "frequency", "heavy", "similarity", "symmetric"}
+
+func main() {
+	o := defaultOptions()
+	var format, out string
+	flag.Var(&o.domains, "domains", "comma separated list of domains to evaluate")
+	flag.Var(&o.n, "n", "workload sizes: distinct keys for amq and cardinality, stream length for frequency and heavy")
+	flag.Int64Var(&o.seed, "seed", o.seed, "seed of synthetic workloads")
+	flag.Float64Var(&o.zipf, "zipf", o.zipf, "skew of Zipfian streams (must be > 1)")
+	flag.Uint64Var(&o.universe, "universe", o.universe, "number of distinct keys of Zipfian streams")
+	flag.IntVar(&o.pairs, "pairs", o.pairs, "number of texts pairs for similarity and symmetric")
+	flag.IntVar(&o.words, "words", o.words, "number of distinct words in each texts pair")
+	flag.Var(&o.fpp, "fpp", "false positive probabilities (amq, symmetric)")
+	flag.Var(&o.precision, "precision", "precisions of cardinality estimators")
+	flag.Var(&o.epsilon, "epsilon", "epsilons of frequency estimators and lossy counting")
+	flag.Float64Var(&o.confidence, "confidence", o.confidence, "confidence of frequency estimators")
+	flag.Var(&o.counters, "counters", "number of counters of heavy hitters")
+	flag.Var(&o.hashes, "hashes", "number of LSH hash functions (K)")
+	flag.Var(&o.bits, "bits", "bits of b-bit MinHash")
+	flag.StringVar(&format, "format", "csv", "report format: csv, json or md")
+	flag.StringVar(&out, "o", "", "output file (stdout by default)")
+	flag.Parse()
+
+	wr, ok := writers[format]
+	if !ok {
+		fatal(fmt.Errorf("unknown format %q", format))
+	}
+	rs, err := run(&o)
+	if err != nil {
+		fatal(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if len(out) > 0 {
+		f, err := os.Create(out)
+		if err != nil {
+			fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+		w = f
+	}
+	if err = wr(w, rs); err != nil {
+		fatal(err)
+	}
+}
+
+func run(o *options) (rs []result, err error) {
+	for _, d := range o.domains {
+		fn, ok := domains[d]
+		if !ok {
+			return nil, fmt.Errorf("unknown domain %q", d)
+		}
+		rs = append(rs, fn(o)...)
+	}
+	return
+}
+
+// listFlag is a comma separated list of strings.
+type listFlag []string
+
+func (f *listFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *listFlag) Set(s string) error {
+	*f = nil
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			*f = append(*f, v)
+		}
+	}
+	return nil
+}
+
+// floatsFlag is a comma separated list of floats.
+type floatsFlag []float64
+
+func (f *floatsFlag) String() string {
+	ss := make([]string, 0, len(*f))
+	for _, v := range *f {
+		ss = append(ss, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return strings.Join(ss, ",")
+}
+
+func (f *floatsFlag) Set(s string) error {
+	var l listFlag
+	_ = l.Set(s)
+	*f = nil
+	for _, v := range l {
+		x, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return err
+		}
+		*f = append(*f, x)
+	}
+	return nil
+}
+
+// uintsFlag is a comma separated list of unsigned integers, scientific notation (e.g. 1e6) is allowed.
+type uintsFlag []uint64
+
+func (f *uintsFlag) String() string {
+	ss := make([]string, 0, len(*f))
+	for _, v := range *f {
+		ss = append(ss, strconv.FormatUint(v, 10))
+	}
+	return strings.Join(ss, ",")
+}
+
+func (f *uintsFlag) Set(s string) error {
+	var fs floatsFlag
+	if err := fs.Set(s); err != nil {
+		return err
+	}
+	*f = nil
+	for _, v := range fs {
+		if v < 0 || v != math.Trunc(v) {
+			return fmt.Errorf("invalid unsigned integer %v", v)
+		}
+		*f = append(*f, uint64(v))
+	}
+	return nil
+}
+
+func fatal(err error) {
+	_, _ = fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	o := defaultOptions()
+	o.n, o.pairs, o.words = uintsFlag{1000}, 20, 20
+	o.fpp, o.precision, o.epsilon = floatsFlag{.01}, uintsFlag{10}, floatsFlag{.01}
+	o.counters, o.hashes, o.bits = uintsFlag{64}, uintsFlag{64}, uintsFlag{4}
+	rs, err := run(&o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]int)
+	for i := 0; i < len(rs); i++ {
+		r := &rs[i]
+		if len(r.Impl) == 0 || len(r.Metric) == 0 {
+			t.Errorf("incomplete result %+v", r)
+		}
+		seen[r.Domain]++
+	}
+	for _, d := range domainsOrder {
+		if seen[d] == 0 {
+			t.Errorf("no results of domain %s", d)
+		}
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeCSV(&buf, rs); err != nil {
+			t.Fatal(err)
+		}
+		recs, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recs) != len(rs)+1 {
+			t.Errorf("expected %d records, got %d", len(rs)+1, len(recs))
+		}
+	})
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, rs); err != nil {
+			t.Fatal(err)
+		}
+		var dec []result
+		if err := json.Unmarshal(buf.Bytes(), &dec); err != nil {
+			t.Fatal(err)
+		}
+		if len(dec) != len(rs) || dec[0] != rs[0] {
+			t.Error("decoded results mismatch")
+		}
+	})
+	t.Run("md", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := writeMarkdown(&buf, rs); err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(buf.String(), "\n"); lines != len(rs)+2 {
+			t.Errorf("expected %d lines, got %d", len(rs)+2, lines)
+		}
+	})
+	t.Run("unknown domain", func(t *testing.T) {
+		o := defaultOptions()
+		o.domains = listFlag{"foo"}
+		if _, err := run(&o); err == nil {
+			t.Error("expected unknown domain error")
+		}
+	})
+}
+
+func TestFlags(t *testing.T) {
+	var u uintsFlag
+	if err := u.Set("1e3, 10,"); err != nil || len(u) != 2 || u[0] != 1000 || u[1] != 10 {
+		t.Errorf("unexpected uints %v: %v", u, err)
+	}
+	if err := u.Set("1.5"); err == nil {
+		t.Error("expected invalid unsigned integer error")
+	}
+	var f floatsFlag
+	if err := f.Set("0.01,1e-3"); err != nil || f.String() != "0.01,0.001" {
+		t.Errorf("unexpected floats %v: %v", f, err)
+	}
+}
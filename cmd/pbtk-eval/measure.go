@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/koykov/hash/xxhash"
+)
+
+var hasher = xxhash.Hasher64[[]byte]{}
+
+// throughput of n calls of fn.
+type throughput struct {
+	ops, allocs, bytes float64
+}
+
+func measure(n int, fn func(i int)) (t throughput) {
+	if n <= 0 {
+		return
+	}
+	var m0, m1 runtime.MemStats
+	runtime.ReadMemStats(&m0)
+	t0 := time.Now()
+	for i := 0; i < n; i++ {
+		fn(i)
+	}
+	el := time.Since(t0)
+	runtime.ReadMemStats(&m1)
+	t.ops = float64(n) / max(el.Seconds(), 1e-9)
+	t.allocs = float64(m1.Mallocs-m0.Mallocs) / float64(n)
+	t.bytes = float64(m1.TotalAlloc-m0.TotalAlloc) / float64(n)
+	return
+}
+
+// liveHeap returns size of live heap objects.
+func liveHeap() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+// heapDelta returns growth of live heap since h0.
+func heapDelta(h0 uint64) uint64 {
+	if h1 := liveHeap(); h1 > h0 {
+		return h1 - h0
+	}
+	return 0
+}
+
+// dumpSize returns size of serialized structure, if it's serializable.
+func dumpSize(x any) uint64 {
+	if wt, ok := x.(io.WriterTo); ok {
+		n, _ := wt.WriteTo(io.Discard)
+		return uint64(n)
+	}
+	return 0
+}
+
+func (r *result) setWrite(t throughput) {
+	r.WriteOps, r.AllocsOp, r.BytesOp = t.ops, t.allocs, t.bytes
+}
+
+func (r *result) fail(err error) result {
+	r.Fail = err.Error()
+	return *r
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// result of evaluation of one implementation with one params set over one workload.
+type result struct {
+	Domain   string `json:"domain"`
+	Impl     string `json:"impl"`
+	Params   string `json:"params"`
+	Workload string `json:"workload"`
+	// Name of error metric, see readme.
+	Metric string  `json:"metric"`
+	Error  float64 `json:"error"`
+	// Live heap size of the structure after workload processing.
+	MemBytes uint64 `json:"mem_bytes"`
+	// Size of serialized structure, zero if structure can't be serialized.
+	DumpBytes uint64 `json:"dump_bytes"`
+	// Throughput of write (add/set) and read (estimate/contains) operations, zero if not applicable.
+	WriteOps float64 `json:"write_ops_per_sec"`
+	ReadOps  float64 `json:"read_ops_per_sec"`
+	// Allocations per write operation, or per read operation for domains without writes (similarity, symmetric).
+	AllocsOp float64 `json:"allocs_per_op"`
+	BytesOp  float64 `json:"bytes_per_op"`
+	// Failure of run. Other fields are undefined if structure can't be built or filled, otherwise the failure is partial
+	// (e.g. false negatives or IBLT decoding failures).
+	Fail string `json:"fail,omitempty"`
+}
+
+var columns = []string{
+	"domain", "impl", "params", "workload", "metric", "error", "mem_bytes", "dump_bytes", "write_ops_per_sec",
+	"read_ops_per_sec", "allocs_per_op", "bytes_per_op", "fail",
+}
+
+func (r *result) row() []string {
+	return []string{
+		r.Domain, r.Impl, r.Params, r.Workload, r.Metric,
+		strconv.FormatFloat(r.Error, 'g', 6, 64),
+		strconv.FormatUint(r.MemBytes, 10),
+		strconv.FormatUint(r.DumpBytes, 10),
+		strconv.FormatFloat(r.WriteOps, 'f', 0, 64),
+		strconv.FormatFloat(r.ReadOps, 'f', 0, 64),
+		strconv.FormatFloat(r.AllocsOp, 'f', 2, 64),
+		strconv.FormatFloat(r.BytesOp, 'f', 2, 64),
+		r.Fail,
+	}
+}
+
+var writers = map[string]func(w io.Writer, rs []result) error{
+	"csv":  writeCSV,
+	"json": writeJSON,
+	"md":   writeMarkdown,
+}
+
+func writeCSV(w io.Writer, rs []result) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(columns)
+	for i := 0; i < len(rs); i++ {
+		_ = cw.Write(rs[i].row())
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSON(w io.Writer, rs []result) error {
+	if rs == nil {
+		rs = []result{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rs)
+}
+
+func writeMarkdown(w io.Writer, rs []result) (err error) {
+	var buf strings.Builder
+	buf.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	buf.WriteString(strings.Repeat("| --- ", len(columns)) + "|\n")
+	for i := 0; i < len(rs); i++ {
+		row := rs[i].row()
+		for j := 0; j < len(row); j++ {
+			row[j] = strings.ReplaceAll(row[j], "|", "\\|")
+		}
+		buf.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	_, err = fmt.Fprint(w, buf.String())
+	return
+}
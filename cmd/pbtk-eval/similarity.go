@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/lsh/bbitminhash"
+	"github.com/koykov/pbtk/lsh/minhash"
+	"github.com/koykov/pbtk/lsh/simhash"
+	"github.com/koykov/pbtk/shingle"
+	"github.com/koykov/pbtk/similarity"
+	"github.com/koykov/pbtk/similarity/bbitjaccard"
+	"github.com/koykov/pbtk/similarity/cosine"
+	"github.com/koykov/pbtk/similarity/hamming"
+	"github.com/koykov/pbtk/similarity/jaccard"
+	"github.com/koykov/pbtk/simtest"
+)
+
+type similarityImpl struct {
+	name string
+	// Estimator is parametrized by number of hashes K and optionally by number of bits b.
+	k, b bool
+	new  func(k, b uint64) (similarity.Estimator[[]byte], error)
+}
+
+// words makes shingler of single words, so LSH approximates sets of words.
+func words() shingle.Shingler[[]byte] {
+	return shingle.NewWord[[]byte](1, "")
+}
+
+func newMinHash(k uint64) (lsh.Hasher[[]byte], error) {
+	return minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](hasher, k, words()))
+}
+
+var similarityImpls = []similarityImpl{
+	{name: "jaccard/minhash", k: true, new: func(k, _ uint64) (similarity.Estimator[[]byte], error) {
+		h, err := newMinHash(k)
+		if err != nil {
+			return nil, err
+		}
+		return jaccard.NewEstimator[[]byte](jaccard.NewConfig[[]byte](h))
+	}},
+	{name: "bbitjaccard/bbitminhash", k: true, b: true, new: func(k, b uint64) (similarity.Estimator[[]byte], error) {
+		h, err := bbitminhash.NewHasher[[]byte](bbitminhash.NewConfig[[]byte](hasher, k, words(), b).
+			WithMode(minhash.ModeOnePermutation))
+		if err != nil {
+			return nil, err
+		}
+		return bbitjaccard.NewEstimator[[]byte](bbitjaccard.NewConfig[[]byte](h))
+	}},
+	{name: "cosine/minhash", k: true, new: func(k, _ uint64) (similarity.Estimator[[]byte], error) {
+		h, err := newMinHash(k)
+		if err != nil {
+			return nil, err
+		}
+		return cosine.NewEstimator[[]byte](cosine.NewConfig[[]byte](h))
+	}},
+	{name: "hamming/simhash", new: func(_, _ uint64) (similarity.Estimator[[]byte], error) {
+		h, err := simhash.NewHasher[[]byte](simhash.NewConfig[[]byte](hasher, words()))
+		if err != nil {
+			return nil, err
+		}
+		return hamming.NewEstimator[[]byte](hamming.NewConfig[[]byte](h))
+	}},
+}
+
+// textPairs makes synthetic texts pairs with evenly distributed Jaccard similarity.
+func textPairs(o *options) (simtest.Dataset, error) {
+	return simtest.Synthetic{
+		Name:       fmt.Sprintf("pairs=%d,words=%d", o.pairs, o.words),
+		Pairs:      o.pairs,
+		Words:      o.words,
+		MinJaccard: 0,
+		MaxJaccard: 1,
+		Seed:       o.seed,
+	}.Load()
+}
+
+// evalSimilarity estimates similarity of synthetic texts pairs. Error is the mean absolute error of estimation against
+// the exact Jaccard similarity of words sets.
+func evalSimilarity(o *options) (rs []result) {
+	ds, err := textPairs(o)
+	if err != nil {
+		r := result{Domain: "similarity"}
+		return append(rs, r.fail(err))
+	}
+	for _, impl := range similarityImpls {
+		ks, bs := o.hashes, o.bits
+		if !impl.k {
+			ks = []uint64{0}
+		}
+		if !impl.b {
+			bs = []uint64{0}
+		}
+		for _, k := range ks {
+			for _, b := range bs {
+				rs = append(rs, evalSimilarityEstimator(impl, k, b, &ds))
+			}
+		}
+	}
+	return
+}
+
+func evalSimilarityEstimator(impl similarityImpl, k, b uint64, ds *simtest.Dataset) result {
+	r := result{Domain: "similarity", Impl: impl.name, Workload: ds.Name, Metric: "mae"}
+	switch {
+	case impl.b:
+		r.Params = fmt.Sprintf("k=%d,b=%d", k, b)
+	case impl.k:
+		r.Params = fmt.Sprintf("k=%d", k)
+	}
+
+	est, err := impl.new(k, b)
+	if err != nil {
+		return r.fail(err)
+	}
+	var sum float64
+	t := measure(len(ds.Tuples), func(i int) {
+		tp := &ds.Tuples[i]
+		est.Reset()
+		e, err1 := est.Estimate(tp.A, tp.B)
+		if err1 != nil && err == nil {
+			err = err1
+		}
+		sum += math.Abs(e - tp.Jaccard)
+	})
+	if err != nil {
+		return r.fail(err)
+	}
+	r.ReadOps, r.AllocsOp, r.BytesOp = t.ops, t.allocs, t.bytes
+	if len(ds.Tuples) > 0 {
+		r.Error = sum / float64(len(ds.Tuples))
+	}
+	return r
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/simtest"
+	"github.com/koykov/pbtk/symmetric"
+	"github.com/koykov/pbtk/symmetric/iblt"
+	"github.com/koykov/pbtk/symmetric/oddsketch"
+)
+
+type symmetricImpl struct {
+	name string
+	// Differ is parametrized by FPP.
+	fpp bool
+	new func(k uint64, fpp float64) (symmetric.Differ[[]byte], error)
+}
+
+var symmetricImpls = []symmetricImpl{
+	{name: "oddsketch/minhash", fpp: true, new: func(k uint64, fpp float64) (symmetric.Differ[[]byte], error) {
+		h, err := newMinHash(k)
+		if err != nil {
+			return nil, err
+		}
+		// symmetric difference of two signatures doesn't exceed 2K values
+		return oddsketch.NewDiffer[[]byte](oddsketch.NewConfig[[]byte](2*k, fpp, h))
+	}},
+	{name: "iblt/minhash", new: func(k uint64, _ float64) (symmetric.Differ[[]byte], error) {
+		h, err := newMinHash(k)
+		if err != nil {
+			return nil, err
+		}
+		return iblt.NewDiffer[[]byte](iblt.NewDifferConfig[[]byte](2*k, h))
+	}},
+}
+
+// evalSymmetric estimates symmetric difference of MinHash signatures of synthetic texts pairs. Error is the mean
+// absolute error of estimation against the exact size of symmetric difference of signatures values.
+func evalSymmetric(o *options) (rs []result) {
+	ds, err := textPairs(o)
+	if err != nil {
+		r := result{Domain: "symmetric"}
+		return append(rs, r.fail(err))
+	}
+	for _, k := range o.hashes {
+		exact, err := exactDiffs(k, &ds)
+		for _, impl := range symmetricImpls {
+			fpps := o.fpp
+			if !impl.fpp {
+				fpps = []float64{0}
+			}
+			for _, fpp := range fpps {
+				r := result{Domain: "symmetric", Impl: impl.name, Params: fmt.Sprintf("k=%d", k), Workload: ds.Name,
+					Metric: "mae"}
+				if impl.fpp {
+					r.Params += fmt.Sprintf(",fpp=%g", fpp)
+				}
+				if err != nil {
+					rs = append(rs, r.fail(err))
+					continue
+				}
+				rs = append(rs, evalDiffer(r, impl, k, fpp, &ds, exact))
+			}
+		}
+	}
+	return
+}
+
+func evalDiffer(r result, impl symmetricImpl, k uint64, fpp float64, ds *simtest.Dataset, exact []float64) result {
+	d, err := impl.new(k, fpp)
+	if err != nil {
+		return r.fail(err)
+	}
+	var (
+		sum   float64
+		fails int
+	)
+	t := measure(len(ds.Tuples), func(i int) {
+		tp := &ds.Tuples[i]
+		d.Reset()
+		e, err1 := d.Diff(tp.A, tp.B)
+		if err1 != nil {
+			// keep the partial result (e.g. IBLT decoding failure) but report it
+			err, fails = err1, fails+1
+		}
+		sum += math.Abs(e - exact[i])
+	})
+	r.ReadOps, r.AllocsOp, r.BytesOp = t.ops, t.allocs, t.bytes
+	if len(ds.Tuples) > 0 {
+		r.Error = sum / float64(len(ds.Tuples))
+	}
+	if err != nil {
+		r.Fail = fmt.Sprintf("%d of %d pairs failed: %s", fails, len(ds.Tuples), err.Error())
+	}
+	return r
+}
+
+// exactDiffs returns exact sizes of symmetric difference of sets of MinHash signatures values.
+func exactDiffs(k uint64, ds *simtest.Dataset) ([]float64, error) {
+	h, err := newMinHash(k)
+	if err != nil {
+		return nil, err
+	}
+	set := func(text []byte) map[uint64]struct{} {
+		h.Reset()
+		_ = h.Add(text)
+		s := make(map[uint64]struct{})
+		for _, v := range lsh.NewSignature[[]byte](h).Values {
+			s[v] = struct{}{}
+		}
+		return s
+	}
+	r := make([]float64, 0, len(ds.Tuples))
+	for i := 0; i < len(ds.Tuples); i++ {
+		a, b := set(ds.Tuples[i].A), set(ds.Tuples[i].B)
+		var n int
+		for v := range a {
+			if _, ok := b[v]; !ok {
+				n++
+			}
+		}
+		for v := range b {
+			if _, ok := a[v]; !ok {
+				n++
+			}
+		}
+		r = append(r, float64(n))
+	}
+	return r, nil
+}
@@ -0,0 +1,9 @@
+package pbtk
+
+import "math"
+
+// ZScore returns two-sided standard normal quantile for given confidence level, e.g. 1.96 for 0.95.
+// Confidence clamps to range [0..0.999999].
+func ZScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(min(max(confidence, 0), .999999))
+}
@@ -0,0 +1,23 @@
+package pbtk
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZScore(t *testing.T) {
+	stages := []struct {
+		confidence, z float64
+	}{
+		{0, 0},
+		{.6827, 1},
+		{.95, 1.96},
+		{.99, 2.5758},
+		{-1, 0},
+	}
+	for _, st := range stages {
+		if z := ZScore(st.confidence); math.Abs(z-st.z) > 1e-3 {
+			t.Errorf("confidence %f: expected z %f, got %f", st.confidence, st.z, z)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package pbtk
+
+// HashStrategy describes how to derive k hash sums of the single key.
+type HashStrategy uint8
+
+const (
+	// HashStrategySalt calculates k independent hash sums of key with appended salt (index in decimal form).
+	// Default strategy, keeps compatibility with previously written dumps.
+	HashStrategySalt HashStrategy = iota
+	// HashStrategyDouble derives k hash sums from one 64-bit hash sum using enhanced double hashing.
+	HashStrategyDouble
+	// HashStrategyDouble128 derives k hash sums from one 128-bit hash sum using enhanced double hashing.
+	// Requires Hasher128.
+	HashStrategyDouble128
+)
+
+func (s HashStrategy) String() string {
+	switch s {
+	case HashStrategySalt:
+		return "salt"
+	case HashStrategyDouble:
+		return "double"
+	case HashStrategyDouble128:
+		return "double128"
+	default:
+		return "unknown"
+	}
+}
+
+// Check checks if required hasher of the strategy is provided.
+func (s HashStrategy) Check(hasher Hasher, hasher128 Hasher128) error {
+	switch s {
+	case HashStrategySalt, HashStrategyDouble:
+		if hasher == nil {
+			return ErrNoHasher
+		}
+	case HashStrategyDouble128:
+		if hasher128 == nil {
+			return ErrNoHasher
+		}
+	default:
+		return ErrUnknownHashStrategy
+	}
+	return nil
+}
+
+// DoubleHash generates sequence of hash sums using enhanced double hashing (Kirsch–Mitzenmacher scheme with
+// Dillinger–Manolios cubic correction):
+//
+//	g(i) = h1 + i*h2 + (i^3-i)/6
+//
+// Only one hash calculation of the key requires instead of k.
+type DoubleHash struct {
+	x, y, i uint64
+}
+
+// NewDoubleHash makes generator from 64-bit hash sum. Second hash derives from the first one using 64-bit finalizer.
+func NewDoubleHash(hsum uint64) DoubleHash {
+	return DoubleHash{x: hsum, y: Fmix64(hsum)}
+}
+
+// NewDoubleHash128 makes generator from 128-bit hash sum.
+func NewDoubleHash128(hsum [2]uint64) DoubleHash {
+	return DoubleHash{x: hsum[0], y: hsum[1]}
+}
+
+// Next returns next hash sum of the sequence.
+func (d *DoubleHash) Next() uint64 {
+	r := d.x
+	d.i++
+	d.x += d.y
+	d.y += d.i
+	return r
+}
+
+// DoubleHash calculates hash sum of data using given hasher and makes double hashing generator.
+func (b Base[T]) DoubleHash(hasher Hasher, data T) (DoubleHash, error) {
+	h, err := b.Hash(hasher, data)
+	if err != nil {
+		return DoubleHash{}, err
+	}
+	return NewDoubleHash(h), nil
+}
+
+// DoubleHash128 calculates 128-bit hash sum of data using given hasher and makes double hashing generator.
+func (b Base[T]) DoubleHash128(hasher Hasher128, data T) (DoubleHash, error) {
+	h, err := b.Hash128(hasher, data)
+	if err != nil {
+		return DoubleHash{}, err
+	}
+	return NewDoubleHash128(h), nil
+}
+
+// HashStrategyK returns i-th of k hash sums of data according given strategy.
+// dh keeps state of double hashing between calls and must be passed with i in order 0, 1, ..., k-1.
+func (b Base[T]) HashStrategyK(s HashStrategy, hasher Hasher, hasher128 Hasher128, data T, i uint64, dh *DoubleHash) (uint64, error) {
+	var err error
+	switch s {
+	case HashStrategySalt:
+		return b.HashSalt(hasher, data, i)
+	case HashStrategyDouble:
+		if i == 0 {
+			if *dh, err = b.DoubleHash(hasher, data); err != nil {
+				return 0, err
+			}
+		}
+	case HashStrategyDouble128:
+		if i == 0 {
+			if *dh, err = b.DoubleHash128(hasher128, data); err != nil {
+				return 0, err
+			}
+		}
+	default:
+		return 0, ErrUnknownHashStrategy
+	}
+	return dh.Next(), nil
+}
+
+// Fmix64 is a 64-bit finalizer of MurmurHash3. Mixes all bits of h, so it uses to derive independent values from
+// hash sums.
+func Fmix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,24 @@
+package pbtk
+
+import "testing"
+
+func TestDoubleHash(t *testing.T) {
+	t.Run("closed form", func(t *testing.T) {
+		h := [2]uint64{0x9e3779b97f4a7c15, 0xc2b2ae3d27d4eb4f}
+		dh := NewDoubleHash128(h)
+		for i := uint64(0); i < 64; i++ {
+			expect := h[0] + i*h[1] + (i*i*i-i)/6
+			if g := dh.Next(); g != expect {
+				t.Errorf("g(%d): expected %d, got %d", i, expect, g)
+			}
+		}
+	})
+	t.Run("check", func(t *testing.T) {
+		if err := HashStrategyDouble128.Check(nil, nil); err != ErrNoHasher {
+			t.Errorf("expected ErrNoHasher, got %v", err)
+		}
+		if err := HashStrategy(100).Check(nil, nil); err != ErrUnknownHashStrategy {
+			t.Errorf("expected ErrUnknownHashStrategy, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package pbtk
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// Encoding describes how keys encode to bytes before hashing.
+// Encoding is a set of flags, so EncodingBinary and EncodingTypeTag may be combined. Structures take it from Encoding
+// param of config; if the param omitted, EncodingText uses.
+type Encoding uint8
+
+const (
+	// EncodingText formats numeric keys as decimal text before hashing.
+	// Default encoding, keeps compatibility with previously written dumps.
+	// Caution! Keys like int64(1) and "1" gives the same hash sum.
+	EncodingText Encoding = 0
+	// EncodingBinary encodes numeric keys as fixed-width little-endian bytes (salt encodes as 8 bytes as well).
+	// Works much faster than EncodingText.
+	EncodingBinary Encoding = 1 << 0
+	// EncodingTypeTag prepends type tag byte to the encoded key, thus keys of different types never collide.
+	// Byte sequences ([]byte, string and []rune) share the same tag since they encode to the same bytes.
+	EncodingTypeTag Encoding = 1 << 1
+)
+
+const (
+	tagInt uint8 = iota + 1
+	tagInt8
+	tagInt16
+	tagInt32
+	tagInt64
+	tagUint
+	tagUint8
+	tagUint16
+	tagUint32
+	tagUint64
+	tagUintptr
+	tagFloat32
+	tagFloat64
+	tagBytes
+)
+
+func appendTypeTag[T Hashable](dst []byte, data T) ([]byte, error) {
+	var tag uint8
+	switch any(data).(type) {
+	case int:
+		tag = tagInt
+	case int8:
+		tag = tagInt8
+	case int16:
+		tag = tagInt16
+	case int32:
+		tag = tagInt32
+	case int64:
+		tag = tagInt64
+	case uint:
+		tag = tagUint
+	case uint8:
+		tag = tagUint8
+	case uint16:
+		tag = tagUint16
+	case uint32:
+		tag = tagUint32
+	case uint64:
+		tag = tagUint64
+	case uintptr:
+		tag = tagUintptr
+	case float32:
+		tag = tagFloat32
+	case float64:
+		tag = tagFloat64
+	case []byte, string, []rune:
+		tag = tagBytes
+	default:
+		return dst, ErrEncoding
+	}
+	return append(dst, tag), nil
+}
+
+func appendNumText[T Hashable](dst []byte, data T) ([]byte, error) {
+	switch x := any(data).(type) {
+	// int
+	case int:
+		dst = strconv.AppendInt(dst, int64(x), 10)
+	case int8:
+		dst = strconv.AppendInt(dst, int64(x), 10)
+	case int16:
+		dst = strconv.AppendInt(dst, int64(x), 10)
+	case int32:
+		dst = strconv.AppendInt(dst, int64(x), 10)
+	case int64:
+		dst = strconv.AppendInt(dst, x, 10)
+	// uint
+	case uint:
+		dst = strconv.AppendUint(dst, uint64(x), 10)
+	case uint8:
+		dst = strconv.AppendUint(dst, uint64(x), 10)
+	case uint16:
+		dst = strconv.AppendUint(dst, uint64(x), 10)
+	case uint32:
+		dst = strconv.AppendUint(dst, uint64(x), 10)
+	case uint64:
+		dst = strconv.AppendUint(dst, x, 10)
+	case uintptr:
+		dst = strconv.AppendUint(dst, uint64(x), 10)
+	// float
+	case float32:
+		dst = strconv.AppendFloat(dst, float64(x), 'f', -1, 32)
+	case float64:
+		dst = strconv.AppendFloat(dst, x, 'f', -1, 64)
+	default:
+		return dst, ErrEncoding
+	}
+	return dst, nil
+}
+
+func appendNumBinary[T Hashable](dst []byte, data T) ([]byte, error) {
+	le := binary.LittleEndian
+	switch x := any(data).(type) {
+	// int
+	case int:
+		dst = le.AppendUint64(dst, uint64(x))
+	case int8:
+		dst = append(dst, uint8(x))
+	case int16:
+		dst = le.AppendUint16(dst, uint16(x))
+	case int32:
+		dst = le.AppendUint32(dst, uint32(x))
+	case int64:
+		dst = le.AppendUint64(dst, uint64(x))
+	// uint
+	case uint:
+		dst = le.AppendUint64(dst, uint64(x))
+	case uint8:
+		dst = append(dst, x)
+	case uint16:
+		dst = le.AppendUint16(dst, x)
+	case uint32:
+		dst = le.AppendUint32(dst, x)
+	case uint64:
+		dst = le.AppendUint64(dst, x)
+	case uintptr:
+		dst = le.AppendUint64(dst, uint64(x))
+	// float
+	case float32:
+		dst = le.AppendUint32(dst, math.Float32bits(x))
+	case float64:
+		dst = le.AppendUint64(dst, math.Float64bits(x))
+	default:
+		return dst, ErrEncoding
+	}
+	return dst, nil
+}
@@ -11,4 +11,6 @@ var (
 	ErrInvalidSignature = errors.New("invalid signature")
 	ErrVersionMismatch  = errors.New("version mismatch")
 	ErrClosed           = errors.New("closed")
+
+	ErrUnknownHashStrategy = errors.New("unknown hash strategy")
 )
@@ -20,6 +20,8 @@ type Config struct {
 	// Hasher to calculate hash sum of the items.
 	// Mandatory param.
 	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
 	// Enable compact mode.
 	// By default, uses 64 bit per counter. This param allows to use 32 bit per counter.
 	Compact bool
@@ -73,6 +75,11 @@ func (c *Config) WithFlag(flag int, value bool) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
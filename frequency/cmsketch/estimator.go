@@ -3,6 +3,7 @@ package cmsketch
 import (
 	"context"
 	"io"
+	"math"
 	"sync"
 
 	"github.com/koykov/pbtk"
@@ -74,6 +75,38 @@ func (e *estimator[T]) HEstimate(hkey uint64) uint64 {
 	return e.mw().Estimate(e.vec.estimate(hkey))
 }
 
+// EstimateWithBounds returns frequency estimation of key and its bounds for given confidence level.
+// Count-Min sketch never underestimates, thus upper bound is the estimation itself.
+func (e *estimator[T]) EstimateWithBounds(key T, confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	hkey, err := e.Hash(e.conf.Hasher, key)
+	if err != nil {
+		return
+	}
+	return e.hbounds(hkey, confidence)
+}
+
+// HEstimateWithBounds returns frequency estimation of precalculated hash key and its bounds.
+func (e *estimator[T]) HEstimateWithBounds(hkey uint64, confidence float64) (est, lo, hi uint64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	return e.hbounds(hkey, confidence)
+}
+
+func (e *estimator[T]) hbounds(hkey uint64, confidence float64) (est, lo, hi uint64) {
+	est = e.mw().Estimate(e.vec.estimate(hkey))
+	// Expected overestimation of each row is N/w, so by Markov inequality row overestimates by more than N/(w*q) with
+	// probability q. Minimum of d independent rows exceeds it with probability q^d, that must be equal 1-confidence.
+	// For configured confidence it gives the classic bound ε*N.
+	q := math.Pow(1-min(max(confidence, 0), .999999), 1/float64(e.d))
+	d := float64(e.vec.total()) / (float64(e.w) * q)
+	lo, hi = uint64(max(float64(est)-d, 0)), est
+	return
+}
+
 func (e *estimator[T]) Reset() {
 	if e.once.Do(e.init); e.err != nil {
 		return
@@ -107,6 +140,7 @@ func (e *estimator[T]) mw() frequency.MetricsWriter {
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Hasher == nil {
 		e.err = pbtk.ErrNoHasher
 		return
@@ -1,6 +1,7 @@
 package cmsketch
 
 import (
+	"math/rand"
 	"os"
 	"testing"
 
@@ -31,6 +32,77 @@ func TestEstimator(t *testing.T) {
 		}
 		frequency.TestMeConcurrently(t, frequency.NewTestAdapter(est))
 	})
+	t.Run("bounds", func(t *testing.T) {
+		testBounds := func(t *testing.T, conf *Config) {
+			est, err := NewEstimator[[]byte](conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			best := est.(frequency.BoundsEstimator[[]byte])
+			const keys = 2000
+			rng := rand.New(rand.NewSource(1))
+			hkeys, freq := make([]uint64, keys), make([]uint64, keys)
+			for i := 0; i < keys; i++ {
+				hkeys[i], freq[i] = rng.Uint64(), uint64(1+rng.Intn(100))
+				_ = est.HAddN(hkeys[i], freq[i])
+			}
+			var covered int
+			for i := 0; i < keys; i++ {
+				e, lo, hi := best.HEstimateWithBounds(hkeys[i], .95)
+				if lo > e || e != hi {
+					t.Fatalf("estimation %d out of bounds [%d..%d]", e, lo, hi)
+				}
+				if lo <= freq[i] && freq[i] <= hi {
+					covered++
+				}
+			}
+			if ratio := float64(covered) / keys; ratio < .95 {
+				t.Errorf("bounds coverage too low: need %f, got %f", .95, ratio)
+			}
+		}
+		t.Run("classic", func(t *testing.T) {
+			testBounds(t, NewConfig(.99, .005, testh))
+		})
+		t.Run("conservative", func(t *testing.T) {
+			testBounds(t, NewConfig(.99, .005, testh).WithFlag(flagConservativeUpdate, true))
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			testBounds(t, NewConfig(.99, .005, testh).WithCompact().WithConcurrency())
+		})
+	})
+	t.Run("conservative update", func(t *testing.T) {
+		// weighted conservative update must never underestimate frequency
+		testCU := func(t *testing.T, conf *Config) {
+			est, err := NewEstimator[[]byte](conf.WithFlag(flagConservativeUpdate, true))
+			if err != nil {
+				t.Fatal(err)
+			}
+			const keys = 2000
+			rng := rand.New(rand.NewSource(1))
+			hkeys, freq := make([]uint64, keys), make([]uint64, keys)
+			for i := 0; i < keys; i++ {
+				hkeys[i], freq[i] = rng.Uint64(), uint64(1+rng.Intn(100))
+				_ = est.HAddN(hkeys[i], freq[i])
+			}
+			for i := 0; i < keys; i++ {
+				if e := est.HEstimate(hkeys[i]); e < freq[i] {
+					t.Fatalf("frequency underestimated: expected at least %d, got %d", freq[i], e)
+				}
+			}
+		}
+		t.Run("sync32", func(t *testing.T) {
+			testCU(t, NewConfig(.99, .005, testh).WithCompact())
+		})
+		t.Run("sync64", func(t *testing.T) {
+			testCU(t, NewConfig(.99, .005, testh))
+		})
+		t.Run("concurrent32", func(t *testing.T) {
+			testCU(t, NewConfig(.99, .005, testh).WithCompact().WithConcurrency())
+		})
+		t.Run("concurrent64", func(t *testing.T) {
+			testCU(t, NewConfig(.99, .005, testh).WithConcurrency())
+		})
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, est frequency.Estimator[string], path string, expect int64) {
 			_ = est.Add("foobar")
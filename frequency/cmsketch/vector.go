@@ -16,6 +16,8 @@ const (
 type vector interface {
 	add(hkey, delta uint64) error
 	estimate(hkey uint64) uint64
+	// total returns approximate total count of added keys.
+	total() uint64
 	decay(ctx context.Context, factor float64) error
 	reset()
 	readFrom(r io.Reader) (int64, error)
@@ -27,6 +29,30 @@ type basevec struct {
 	flags bitset.Bitset64
 }
 
+type rowsummer interface {
+	rowsum(row uint64) uint64
+}
+
+// Calculates total count of added keys depending on update strategy:
+// * classic update increments each row, thus sum of any row is total count;
+// * conservative update increments only minimal counters, thus max row sum is lower bound of total count;
+// * DLC increments only one counter, thus total count is sum of all counters.
+func (vec *basevec) total(rs rowsummer) (n uint64) {
+	switch {
+	case vec.flags.CheckBit(flagConservativeUpdate):
+		for i := uint64(0); i < vec.d; i++ {
+			n = max(n, rs.rowsum(i))
+		}
+	case vec.flags.CheckBit(flagDLC):
+		for i := uint64(0); i < vec.d; i++ {
+			n += rs.rowsum(i)
+		}
+	default:
+		n = rs.rowsum(0)
+	}
+	return
+}
+
 func vecpos(lo, hi uint32, w, i uint64) uint64 {
 	return i*w + uint64(lo+hi*uint32(i))%w
 }
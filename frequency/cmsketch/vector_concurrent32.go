@@ -64,20 +64,20 @@ func (vec *cnvector32) addCU(lo, hi uint32, delta uint64) error {
 	}
 	for i := uint64(0); i < vec.d; i++ {
 		pos := vecpos(lo, hi, vec.w, i)
-		if atomic.LoadUint32(&vec.buf[pos]) == mn {
-			var ok bool
-			var j uint64
-			for j = 0; j < vec.lim+1; j++ {
-				o := atomic.LoadUint32(&vec.buf[pos])
-				n := o + uint32(delta)
-				if ok = atomic.CompareAndSwapUint32(&vec.buf[pos], o, n); ok {
-					break
-				}
+		var ok bool
+		var j uint64
+		for j = 0; j < vec.lim+1; j++ {
+			o := atomic.LoadUint32(&vec.buf[pos])
+			if ok = o >= mn+uint32(delta); ok {
+				break
 			}
-			if !ok {
-				return pbtk.ErrWriteLimitExceed
+			if ok = atomic.CompareAndSwapUint32(&vec.buf[pos], o, mn+uint32(delta)); ok {
+				break
 			}
 		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
 	}
 	return nil
 }
@@ -120,6 +120,18 @@ func (vec *cnvector32) estimate(hkey uint64) (r uint64) {
 	return
 }
 
+func (vec *cnvector32) total() uint64 {
+	return vec.basevec.total(vec)
+}
+
+func (vec *cnvector32) rowsum(row uint64) (n uint64) {
+	off := row * vec.w
+	for i := uint64(0); i < vec.w; i++ {
+		n += uint64(atomic.LoadUint32(&vec.buf[off+i]))
+	}
+	return
+}
+
 func (vec *cnvector32) decay(ctx context.Context, factor float64) error {
 	for i := 0; i < len(vec.buf); i++ {
 		var ok bool
@@ -63,20 +63,20 @@ func (vec *cnvector64) addCU(lo, hi uint32, delta uint64) error {
 	}
 	for i := uint64(0); i < vec.d; i++ {
 		pos := vecpos(lo, hi, vec.w, i)
-		if atomic.LoadUint64(&vec.buf[pos]) == mn {
-			var ok bool
-			var j uint64
-			for j = 0; j < vec.lim+1; j++ {
-				o := atomic.LoadUint64(&vec.buf[pos])
-				n := o + delta
-				if ok = atomic.CompareAndSwapUint64(&vec.buf[pos], o, n); ok {
-					break
-				}
+		var ok bool
+		var j uint64
+		for j = 0; j < vec.lim+1; j++ {
+			o := atomic.LoadUint64(&vec.buf[pos])
+			if ok = o >= mn+delta; ok {
+				break
 			}
-			if !ok {
-				return pbtk.ErrWriteLimitExceed
+			if ok = atomic.CompareAndSwapUint64(&vec.buf[pos], o, mn+delta); ok {
+				break
 			}
 		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
 	}
 	return nil
 }
@@ -119,6 +119,18 @@ func (vec *cnvector64) estimate(hkey uint64) (r uint64) {
 	return
 }
 
+func (vec *cnvector64) total() uint64 {
+	return vec.basevec.total(vec)
+}
+
+func (vec *cnvector64) rowsum(row uint64) (n uint64) {
+	off := row * vec.w
+	for i := uint64(0); i < vec.w; i++ {
+		n += atomic.LoadUint64(&vec.buf[off+i])
+	}
+	return
+}
+
 func (vec *cnvector64) decay(ctx context.Context, factor float64) error {
 	for i := 0; i < len(vec.buf); i++ {
 		var ok bool
@@ -54,9 +54,7 @@ func (vec *syncvec32) addCU(lo, hi uint32, delta uint64) error {
 	}
 	for i := uint64(0); i < vec.d; i++ {
 		pos := vecpos(lo, hi, vec.w, i)
-		if vec.buf[pos] == mn {
-			vec.buf[pos] += uint32(delta)
-		}
+		vec.buf[pos] = max(vec.buf[pos], mn+uint32(delta))
 	}
 	return nil
 }
@@ -92,6 +90,18 @@ func (vec *syncvec32) estimate(hkey uint64) (r uint64) {
 	return
 }
 
+func (vec *syncvec32) total() uint64 {
+	return vec.basevec.total(vec)
+}
+
+func (vec *syncvec32) rowsum(row uint64) (n uint64) {
+	off := row * vec.w
+	for i := uint64(0); i < vec.w; i++ {
+		n += uint64(vec.buf[off+i])
+	}
+	return
+}
+
 func (vec *syncvec32) decay(ctx context.Context, factor float64) error {
 	for i := 0; i < len(vec.buf); i++ {
 		select {
@@ -51,9 +51,7 @@ func (vec *syncvec64) addCU(lo, hi uint32, delta uint64) error {
 	}
 	for i := uint64(0); i < vec.d; i++ {
 		pos := vecpos(lo, hi, vec.w, i)
-		if vec.buf[pos] == mn {
-			vec.buf[pos] += delta
-		}
+		vec.buf[pos] = max(vec.buf[pos], mn+delta)
 	}
 	return nil
 }
@@ -89,6 +87,18 @@ func (vec *syncvec64) estimate(hkey uint64) (r uint64) {
 	return
 }
 
+func (vec *syncvec64) total() uint64 {
+	return vec.basevec.total(vec)
+}
+
+func (vec *syncvec64) rowsum(row uint64) (n uint64) {
+	off := row * vec.w
+	for i := uint64(0); i < vec.w; i++ {
+		n += vec.buf[off+i]
+	}
+	return
+}
+
 func (vec *syncvec64) decay(ctx context.Context, factor float64) error {
 	for i := 0; i < len(vec.buf); i++ {
 		select {
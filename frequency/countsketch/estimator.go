@@ -1,7 +1,9 @@
 package countsketch
 
 import (
+	"context"
 	"io"
+	"math"
 	"slices"
 	"sync"
 
@@ -98,6 +100,10 @@ func (e *estimator[T]) HEstimate(hkey uint64) int64 {
 }
 
 func (e *estimator[T]) hestimate(hkey uint64) int64 {
+	return e.mw().Estimate(e.median(hkey))
+}
+
+func (e *estimator[T]) median(hkey uint64) int64 {
 	var a [16]int64
 	buf := a[:0]
 	for i := uint64(0); i < e.d; i++ {
@@ -107,8 +113,44 @@ func (e *estimator[T]) hestimate(hkey uint64) int64 {
 		buf = append(buf, e.vec.estimate(i*e.w+pos)*sign)
 	}
 	slices.Sort(buf)
-	median := buf[len(buf)/2]
-	return e.mw().Estimate(median)
+	return buf[len(buf)/2]
+}
+
+// EstimateWithBounds returns signed frequency estimation of key and its bounds for given confidence level.
+func (e *estimator[T]) EstimateWithBounds(key T, confidence float64) (est, lo, hi int64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	hkey, err := e.Hash(e.conf.Hasher, key)
+	if err != nil {
+		return
+	}
+	return e.hbounds(hkey, confidence)
+}
+
+// HEstimateWithBounds returns signed frequency estimation of precalculated hash key and its bounds.
+func (e *estimator[T]) HEstimateWithBounds(hkey uint64, confidence float64) (est, lo, hi int64) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	return e.hbounds(hkey, confidence)
+}
+
+func (e *estimator[T]) hbounds(hkey uint64, confidence float64) (est, lo, hi int64) {
+	est = e.hestimate(hkey)
+	// Estimation of each row is unbiased with variance F2/w, where F2 is the second frequency moment of the stream.
+	// Sum of squared counters of any row is unbiased estimation of F2, so take median of rows.
+	// Median of rows is at least as accurate as single row, thus normal approximation of single row is conservative.
+	var a [16]float64
+	buf := a[:0]
+	for i := uint64(0); i < e.d; i++ {
+		buf = append(buf, e.vec.sumsq(i*e.w, e.w))
+	}
+	slices.Sort(buf)
+	f2 := buf[len(buf)/2]
+	d := int64(math.Ceil(pbtk.ZScore(confidence) * math.Sqrt(f2/float64(e.w))))
+	lo, hi = est-d, est+d
+	return
 }
 
 func (e *estimator[T]) Reset() {
@@ -132,11 +174,19 @@ func (e *estimator[T]) WriteTo(w io.Writer) (int64, error) {
 	return e.vec.writeTo(w)
 }
 
+func (e *estimator[T]) Decay(ctx context.Context, factor float64) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.err
+	}
+	return e.vec.decay(ctx, factor)
+}
+
 func (e *estimator[T]) mw() frequency.SignedMetricsWriter {
 	return e.conf.MetricsWriter
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Hasher == nil {
 		e.err = pbtk.ErrNoHasher
 		return
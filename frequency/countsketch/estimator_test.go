@@ -1,6 +1,8 @@
 package countsketch
 
 import (
+	"context"
+	"math/rand"
 	"os"
 	"testing"
 
@@ -31,6 +33,41 @@ func TestEstimator(t *testing.T) {
 		}
 		frequency.TestMeConcurrently(t, frequency.NewTestSignedAdapter(est))
 	})
+	t.Run("bounds", func(t *testing.T) {
+		testBounds := func(t *testing.T, conf *Config) {
+			est, err := NewEstimator[[]byte](conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			best := est.(frequency.SignedBoundsEstimator[[]byte])
+			const keys = 2000
+			rng := rand.New(rand.NewSource(1))
+			hkeys, freq := make([]uint64, keys), make([]int64, keys)
+			for i := 0; i < keys; i++ {
+				hkeys[i], freq[i] = rng.Uint64(), int64(1+rng.Intn(100))
+				_ = est.HAddN(hkeys[i], uint64(freq[i]))
+			}
+			var covered int
+			for i := 0; i < keys; i++ {
+				e, lo, hi := best.HEstimateWithBounds(hkeys[i], .95)
+				if lo > e || e > hi {
+					t.Fatalf("estimation %d out of bounds [%d..%d]", e, lo, hi)
+				}
+				if lo <= freq[i] && freq[i] <= hi {
+					covered++
+				}
+			}
+			if ratio := float64(covered) / keys; ratio < .95 {
+				t.Errorf("bounds coverage too low: need %f, got %f", .95, ratio)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			testBounds(t, NewConfig(.99, .05, testh))
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			testBounds(t, NewConfig(.99, .05, testh).WithCompact().WithConcurrency())
+		})
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, est frequency.SignedEstimator[string], path string, expect int64) {
 			_ = est.Add("foobar")
@@ -122,6 +159,43 @@ func TestEstimator(t *testing.T) {
 			})
 		})
 	})
+	t.Run("decay", func(t *testing.T) {
+		testDecay := func(t *testing.T, est frequency.SignedEstimator[string]) {
+			for i := 0; i < 10; i++ {
+				_ = est.Add("foobar")
+			}
+			if err := any(est).(frequency.Decayer).Decay(context.Background(), .5); err != nil {
+				t.Fatal(err)
+			}
+			if e := est.Estimate("foobar"); e != 5 {
+				t.Errorf("expected %d estimate, got %d", 5, e)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			t.Run("32", func(t *testing.T) {
+				e, _ := NewEstimator[string](NewConfig(.99, .01, testh).
+					WithCompact())
+				testDecay(t, e)
+			})
+			t.Run("64", func(t *testing.T) {
+				e, _ := NewEstimator[string](NewConfig(.99, .01, testh))
+				testDecay(t, e)
+			})
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			t.Run("32", func(t *testing.T) {
+				e, _ := NewEstimator[string](NewConfig(.99, .01, testh).
+					WithConcurrency().
+					WithCompact())
+				testDecay(t, e)
+			})
+			t.Run("64", func(t *testing.T) {
+				e, _ := NewEstimator[string](NewConfig(.99, .01, testh).
+					WithConcurrency())
+				testDecay(t, e)
+			})
+		})
+	})
 }
 
 func BenchmarkEstimator(b *testing.B) {
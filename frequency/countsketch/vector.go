@@ -1,10 +1,16 @@
 package countsketch
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 type vector interface {
 	add(pos uint64, delta int64) error
 	estimate(pos uint64) int64
+	// sumsq returns sum of squares of n counters starting from off.
+	sumsq(off, n uint64) float64
+	decay(ctx context.Context, factor float64) error
 	reset()
 	readFrom(r io.Reader) (int64, error)
 	writeTo(w io.Writer) (int64, error)
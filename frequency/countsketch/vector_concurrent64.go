@@ -1,6 +1,7 @@
 package countsketch
 
 import (
+	"context"
 	"encoding/binary"
 	"io"
 	"sync/atomic"
@@ -34,6 +35,35 @@ func (vec *cnvector64) estimate(pos uint64) int64 {
 	return atomic.LoadInt64(&vec.buf[pos])
 }
 
+func (vec *cnvector64) sumsq(off, n uint64) (r float64) {
+	for i := off; i < off+n; i++ {
+		c := float64(atomic.LoadInt64(&vec.buf[i]))
+		r += c * c
+	}
+	return
+}
+
+// Signed counters decays with rounding toward zero, thus positive and negative counters decays symmetrically.
+func (vec *cnvector64) decay(ctx context.Context, factor float64) error {
+	for i := 0; i < len(vec.buf); i++ {
+		var ok bool
+		for j := uint64(0); j < vec.lim+1; j++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			o := atomic.LoadInt64(&vec.buf[i])
+			n := int64(float64(o) * factor)
+			if ok = atomic.CompareAndSwapInt64(&vec.buf[i], o, n); ok {
+				break
+			}
+		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
+	}
+	return nil
+}
+
 func (vec *cnvector64) reset() {
 	for i := uint64(0); i < uint64(len(vec.buf)); i++ {
 		atomic.StoreInt64(&vec.buf[i], 0)
@@ -4,6 +4,7 @@ package countsketch
 // to use atomics (in concurrent vector) together with generics.
 
 import (
+	"context"
 	"encoding/binary"
 	"io"
 	"unsafe"
@@ -31,6 +32,27 @@ func (vec *syncvec32) estimate(pos uint64) int64 {
 	return int64(vec.buf[pos])
 }
 
+func (vec *syncvec32) sumsq(off, n uint64) (r float64) {
+	for i := off; i < off+n; i++ {
+		c := float64(vec.buf[i])
+		r += c * c
+	}
+	return
+}
+
+// Signed counters decays with rounding toward zero, thus positive and negative counters decays symmetrically.
+func (vec *syncvec32) decay(ctx context.Context, factor float64) error {
+	for i := 0; i < len(vec.buf); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			vec.buf[i] = int32(float64(vec.buf[i]) * factor)
+		}
+	}
+	return nil
+}
+
 func (vec *syncvec32) reset() {
 	memclr64.ClearUnsafe(unsafe.Pointer(&vec.buf[0]), len(vec.buf)*4)
 }
@@ -1,6 +1,7 @@
 package countsketch
 
 import (
+	"context"
 	"encoding/binary"
 	"io"
 	"unsafe"
@@ -28,6 +29,27 @@ func (vec *syncvec64) estimate(pos uint64) int64 {
 	return vec.buf[pos]
 }
 
+func (vec *syncvec64) sumsq(off, n uint64) (r float64) {
+	for i := off; i < off+n; i++ {
+		c := float64(vec.buf[i])
+		r += c * c
+	}
+	return
+}
+
+// Signed counters decays with rounding toward zero, thus positive and negative counters decays symmetrically.
+func (vec *syncvec64) decay(ctx context.Context, factor float64) error {
+	for i := 0; i < len(vec.buf); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			vec.buf[i] = int64(float64(vec.buf[i]) * factor)
+		}
+	}
+	return nil
+}
+
 func (vec *syncvec64) reset() {
 	memclr64.ClearUnsafe(unsafe.Pointer(&vec.buf[0]), len(vec.buf)*8)
 }
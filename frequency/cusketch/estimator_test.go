@@ -23,6 +23,13 @@ func TestEstimator(t *testing.T) {
 		}
 		frequency.TestMe(t, frequency.NewTestAdapter(est))
 	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](cmsketch.NewConfig(.99, .005, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		frequency.TestMeBounds(t, est, .95)
+	})
 	t.Run("concurrent", func(t *testing.T) {
 		est, err := NewEstimator[[]byte](cmsketch.NewConfig(testConfidence, testEpsilon, testh).
 			WithConcurrency())
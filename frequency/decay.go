@@ -0,0 +1,166 @@
+package frequency
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/koykov/pbtk"
+)
+
+const (
+	defaultDecayFactor     = .5
+	defaultSoftDecayFactor = .75
+)
+
+// DecayNotifier describes external notifier to force decay start.
+type DecayNotifier interface {
+	Notify() <-chan struct{}
+}
+
+// DecayConfig configures decay scheduler.
+type DecayConfig struct {
+	// Count of added items to start decay.
+	Limit uint64
+	// Time interval to start decay.
+	Interval time.Duration
+	// External decay notifier to force decay start.
+	Notifier DecayNotifier
+	// Default factor to decay counters.
+	// Must be in range (0..1).
+	// If this param omitted, defaultDecayFactor (0.5) will use instead.
+	Factor float64
+	// Soft factor to decay counters. Uses for too often decay operations.
+	// Must be in range (0..1).
+	// If this param omitted, defaultSoftDecayFactor (0.75) will use instead.
+	SoftFactor float64
+}
+
+// DecayScheduler runs decay of wrapped Decayer.
+// Decay triggers by any of the following events:
+// * count of added items reached the Limit (see Observe method);
+// * Interval time passed since the last decay;
+// * Notifier sent a signal.
+// Time and notifier triggered decays run in background. Counter triggered decay runs by the writer reached the Limit,
+// so the decay is completed when its write returns. If decay is already running, new one skips.
+// If decay triggers too often (earlier than a half of Limit/Interval), soft factor applies instead of default one.
+type DecayScheduler struct {
+	conf   DecayConfig
+	dec    Decayer
+	ctx    context.Context    // main context
+	cancel context.CancelFunc // main stop func
+	tc     decayTimer         // time reached notifier
+	c      uint64             // counter of added items
+	svc    uint32             // decay running flag
+	lt     int64              // last decay timestamp
+	cls    uint32             // closed flag
+}
+
+// NewDecayScheduler makes new scheduler and starts watching for decay events.
+// Scheduler must be closed after use.
+func NewDecayScheduler(dec Decayer, conf *DecayConfig) (*DecayScheduler, error) {
+	if dec == nil || conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	s := &DecayScheduler{conf: *conf, dec: dec}
+	if s.conf.Factor == 0 {
+		s.conf.Factor = defaultDecayFactor
+	}
+	if s.conf.Factor < 0 || s.conf.Factor >= 1 {
+		return nil, ErrDecayRange
+	}
+	if s.conf.SoftFactor == 0 {
+		s.conf.SoftFactor = defaultSoftDecayFactor
+	}
+	if s.conf.SoftFactor < 0 || s.conf.SoftFactor >= 1 {
+		return nil, ErrDecayRange
+	}
+	if s.conf.Notifier == nil {
+		s.conf.Notifier = dummyDecayNotifier{}
+	}
+	// counter
+	s.c = math.MaxUint64
+	// timer
+	s.tc = &stuckTimer{}
+	if s.conf.Interval > 0 {
+		s.tc = newNativeTimer(s.conf.Interval)
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	go s.watch(s.ctx)
+	return s, nil
+}
+
+// Observe registers result of write operation and triggers decay if count of added items reached the limit.
+// Returns err as is to allow chaining.
+func (s *DecayScheduler) Observe(err error) error {
+	if err != nil || s.conf.Limit == 0 {
+		return err
+	}
+	if atomic.AddUint64(&s.c, 1) == s.conf.Limit && atomic.LoadUint32(&s.cls) == 0 {
+		s.decay(s.ctx)
+	}
+	return nil
+}
+
+// Err returns pbtk.ErrClosed if scheduler is closed.
+func (s *DecayScheduler) Err() error {
+	if atomic.LoadUint32(&s.cls) == 1 {
+		return pbtk.ErrClosed
+	}
+	return nil
+}
+
+// Close stops the scheduler.
+func (s *DecayScheduler) Close() error {
+	atomic.StoreUint32(&s.cls, 1)
+	s.cancel()
+	return nil
+}
+
+func (s *DecayScheduler) watch(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.tc.Stop()
+			return
+		case <-s.conf.Notifier.Notify():
+			s.decay(ctx)
+		case <-s.tc.C():
+			s.decay(ctx)
+		}
+	}
+}
+
+func (s *DecayScheduler) decay(ctx context.Context) {
+	if !atomic.CompareAndSwapUint32(&s.svc, 0, 1) {
+		return
+	}
+	defer atomic.StoreUint32(&s.svc, 0)
+
+	factor := s.conf.Factor
+	{
+		// try soft decay
+		var interval, counter bool
+		if lt := atomic.LoadInt64(&s.lt); lt > 0 {
+			left := time.Now().Sub(time.Unix(0, lt))
+			interval = left > 0 && left < s.conf.Interval/2
+		}
+		c := atomic.LoadUint64(&s.c)
+		counter = c > 0 && c < s.conf.Limit/2
+		if interval || counter {
+			factor = s.conf.SoftFactor
+		}
+	}
+
+	s.tc.Reset(s.conf.Interval)
+	atomic.StoreUint64(&s.c, 0)
+	atomic.StoreInt64(&s.lt, time.Now().UnixNano())
+	_ = s.dec.Decay(ctx, factor)
+}
+
+type dummyDecayNotifier struct{}
+
+func (dummyDecayNotifier) Notify() <-chan struct{} {
+	return nil
+}
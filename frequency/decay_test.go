@@ -0,0 +1,94 @@
+package frequency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/koykov/pbtk"
+)
+
+type testDecayer struct {
+	run, done chan struct{}
+}
+
+func (d *testDecayer) Decay(_ context.Context, _ float64) error {
+	d.run <- struct{}{}
+	<-d.done
+	return nil
+}
+
+type testDecayNotifier struct {
+	c chan struct{}
+}
+
+func (n testDecayNotifier) Notify() <-chan struct{} {
+	return n.c
+}
+
+func TestDecayScheduler(t *testing.T) {
+	t.Run("counter", func(t *testing.T) {
+		dec := &testDecayer{run: make(chan struct{}, 1), done: make(chan struct{})}
+		close(dec.done)
+		s, err := NewDecayScheduler(dec, &DecayConfig{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = s.Close() }()
+		// counter starts from overflow, so Limit+1 writes required
+		for i := 0; i < 11; i++ {
+			_ = s.Observe(nil)
+		}
+		// decay must be completed when the write reached the limit returns
+		select {
+		case <-dec.run:
+		default:
+			t.Error("decay didn't run")
+		}
+	})
+	t.Run("observe", func(t *testing.T) {
+		dec := &testDecayer{run: make(chan struct{}, 1), done: make(chan struct{})}
+		n := testDecayNotifier{c: make(chan struct{})}
+		s, err := NewDecayScheduler(dec, &DecayConfig{Limit: 10, Notifier: n})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = s.Close() }()
+		n.c <- struct{}{}
+		<-dec.run
+		// writers must not stall while decay is running
+		ok := make(chan struct{})
+		go func() {
+			for i := 0; i < 100; i++ {
+				_ = s.Observe(nil)
+			}
+			close(ok)
+		}()
+		select {
+		case <-ok:
+		case <-time.After(time.Second):
+			t.Error("observe blocked during decay")
+		}
+		close(dec.done)
+	})
+	t.Run("close", func(t *testing.T) {
+		dec := &testDecayer{run: make(chan struct{}, 1), done: make(chan struct{})}
+		close(dec.done)
+		s, err := NewDecayScheduler(dec, &DecayConfig{Limit: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = s.Err(); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		_ = s.Close()
+		if err = s.Err(); !errors.Is(err, pbtk.ErrClosed) {
+			t.Errorf("expected closed error, got %v", err)
+		}
+		// observe after close must not panic
+		for i := 0; i < 10; i++ {
+			_ = s.Observe(nil)
+		}
+	})
+}
@@ -1,8 +1,8 @@
-package tinylfu
+package frequency
 
 import "time"
 
-type timer interface {
+type decayTimer interface {
 	C() <-chan time.Time
 	Stop() bool
 	Reset(duration time.Duration) bool
@@ -12,7 +12,7 @@ type nativeTimer struct {
 	t *time.Timer
 }
 
-func newNativeTimer(d time.Duration) timer {
+func newNativeTimer(d time.Duration) decayTimer {
 	return &nativeTimer{t: time.NewTimer(d)}
 }
 
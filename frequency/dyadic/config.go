@@ -0,0 +1,59 @@
+package dyadic
+
+import (
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/frequency"
+	"github.com/koykov/pbtk/frequency/cmsketch"
+)
+
+const defaultBits = 32
+
+type Config struct {
+	cmsketch.Config
+	// Number of bits of keys universe. Each bit adds a level of Count-Min Sketch.
+	// Unsigned keys must be in range [0..2^Bits), signed keys must be in range [-2^(Bits-1)..2^(Bits-1)).
+	// Must be in range [1..64].
+	// If this param omitted, defaultBits (32) will use instead.
+	Bits uint64
+}
+
+func NewConfig(confidence, epsilon float64, hasher pbtk.Hasher) *Config {
+	return &Config{Config: cmsketch.Config{
+		Confidence: confidence,
+		Epsilon:    epsilon,
+		Hasher:     hasher,
+	}}
+}
+
+func (c *Config) WithBits(bits uint64) *Config {
+	c.Bits = bits
+	return c
+}
+
+func (c *Config) WithConcurrency() *Config {
+	c.Concurrent = &cmsketch.ConcurrentConfig{}
+	return c
+}
+
+func (c *Config) WithWriteAttemptsLimit(limit uint64) *Config {
+	if c.Concurrent == nil {
+		c.Concurrent = &cmsketch.ConcurrentConfig{}
+	}
+	c.Concurrent.WriteAttemptsLimit = limit
+	return c
+}
+
+func (c *Config) WithCompact() *Config {
+	c.Compact = true
+	return c
+}
+
+func WithMetricsWriter(conf *Config, mw frequency.MetricsWriter) *Config {
+	conf.MetricsWriter = mw
+	return conf
+}
+
+func (c *Config) copy() *Config {
+	cpy := *c
+	return &cpy
+}
@@ -0,0 +1,9 @@
+package dyadic
+
+import "errors"
+
+var (
+	ErrInvalidBits  = errors.New("bits must be in range [1..64]")
+	ErrOutOfRange   = errors.New("key is out of universe range")
+	ErrBitsMismatch = errors.New("bits of dump and estimator mismatch")
+)
@@ -0,0 +1,368 @@
+package dyadic
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/frequency"
+	"github.com/koykov/pbtk/frequency/cmsketch"
+	"github.com/koykov/pbtk/heavy"
+)
+
+const (
+	dumpSignature = 0x7c1d5ab0e2f39a64
+	dumpVersion   = 1.0
+)
+
+// Dyadic (hierarchical) Count-Min Sketch implementation.
+// Level i counts frequencies of key prefixes key>>i, so any range of keys may be decomposed to at most 2*Bits
+// dyadic intervals. The root level isn't stored - total count is used instead.
+type estimator[T pbtk.Integer] struct {
+	conf   *Config
+	once   sync.Once
+	bits   uint64
+	signed bool
+	n      uint64
+	levels []frequency.Estimator[uint64]
+
+	err error
+}
+
+func NewEstimator[T pbtk.Integer](conf *Config) (frequency.RangeEstimator[T], error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	e := &estimator[T]{conf: conf.copy()}
+	if e.once.Do(e.init); e.err != nil {
+		return nil, e.err
+	}
+	return e, nil
+}
+
+func (e *estimator[T]) Add(key T) error {
+	return e.AddN(key, 1)
+}
+
+func (e *estimator[T]) AddN(key T, n uint64) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Add(e.err)
+	}
+	pos, err := e.encode(key)
+	if err != nil {
+		return e.mw().Add(err)
+	}
+	return e.mw().Add(e.hadd(pos, n))
+}
+
+// HAdd adds already encoded key to the counter.
+// Hash key must be a position of the key in the universe, i.e. must be in range [0..2^Bits).
+func (e *estimator[T]) HAdd(hkey uint64) error {
+	return e.HAddN(hkey, 1)
+}
+
+func (e *estimator[T]) HAddN(hkey uint64, n uint64) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Add(e.err)
+	}
+	if e.bits < 64 && hkey>>e.bits != 0 {
+		return e.mw().Add(ErrOutOfRange)
+	}
+	return e.mw().Add(e.hadd(hkey, n))
+}
+
+func (e *estimator[T]) hadd(pos, n uint64) error {
+	for i := uint64(0); i < e.bits; i++ {
+		if err := e.levels[i].HAddN(e.hnode(i, pos>>i), n); err != nil {
+			return err
+		}
+	}
+	atomic.AddUint64(&e.n, n)
+	return nil
+}
+
+func (e *estimator[T]) Estimate(key T) uint64 {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Estimate(0)
+	}
+	pos, err := e.encode(key)
+	if err != nil {
+		return e.mw().Estimate(0)
+	}
+	return e.mw().Estimate(e.hest(0, pos))
+}
+
+func (e *estimator[T]) HEstimate(hkey uint64) uint64 {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Estimate(0)
+	}
+	if e.bits < 64 && hkey>>e.bits != 0 {
+		return e.mw().Estimate(0)
+	}
+	return e.mw().Estimate(e.hest(0, hkey))
+}
+
+// EstimateRange returns total frequency estimation of keys in range [lo..hi].
+func (e *estimator[T]) EstimateRange(lo, hi T) uint64 {
+	if e.once.Do(e.init); e.err != nil {
+		return e.mw().Estimate(0)
+	}
+	plo, err := e.encode(lo)
+	if err != nil {
+		return e.mw().Estimate(0)
+	}
+	phi, err := e.encode(hi)
+	if err != nil || plo > phi {
+		return e.mw().Estimate(0)
+	}
+	return e.mw().Estimate(e.hrange(plo, phi))
+}
+
+func (e *estimator[T]) hrange(lo, hi uint64) (r uint64) {
+	for i := uint64(0); i < e.bits; i++ {
+		if lo&1 == 1 {
+			r += e.hest(i, lo)
+			if lo++; lo == 0 {
+				// overflow, range is exhausted
+				return
+			}
+		}
+		if hi&1 == 0 {
+			r += e.hest(i, hi)
+			if hi == 0 {
+				return
+			}
+			hi--
+		}
+		if lo > hi {
+			return
+		}
+		lo, hi = lo>>1, hi>>1
+	}
+	// the whole universe covered
+	r += atomic.LoadUint64(&e.n)
+	return
+}
+
+// Quantile returns approximate key of q-quantile, i.e. the minimal key which rank is greater or equal to q*N.
+func (e *estimator[T]) Quantile(q float64) (r T) {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	n := atomic.LoadUint64(&e.n)
+	if n == 0 || q < 0 || q > 1 {
+		return
+	}
+	target := max(uint64(math.Ceil(q*float64(n))), 1)
+	var node, acc uint64
+	for i := e.bits; i > 0; i-- {
+		left := node << 1
+		if c := e.hest(i-1, left); acc+c >= target {
+			node = left
+		} else {
+			acc += c
+			node = left | 1
+		}
+	}
+	return e.decode(node)
+}
+
+// AppendHeavyHitters appends to dst keys which frequency exceeds phi fraction of total count.
+// Search descends the levels and expands only the prefixes which estimation exceeds the threshold.
+func (e *estimator[T]) AppendHeavyHitters(dst []heavy.Hit[T], phi float64) []heavy.Hit[T] {
+	if e.once.Do(e.init); e.err != nil {
+		return dst
+	}
+	n := atomic.LoadUint64(&e.n)
+	if n == 0 || phi <= 0 || phi > 1 {
+		return dst
+	}
+	threshold := max(uint64(math.Ceil(phi*float64(n))), 1)
+	off := len(dst)
+	curr, next := []uint64{0, 1}, make([]uint64, 0, 2)
+	for i := e.bits; i > 0; i-- {
+		next = next[:0]
+		for _, node := range curr {
+			c := e.hest(i-1, node)
+			if c < threshold {
+				continue
+			}
+			if i == 1 {
+				dst = append(dst, heavy.Hit[T]{Key: e.decode(node), Rate: float64(c)})
+				continue
+			}
+			next = append(next, node<<1, node<<1|1)
+		}
+		curr, next = next, curr
+	}
+	slices.SortFunc(dst[off:], func(a, b heavy.Hit[T]) int {
+		// reverse order
+		switch {
+		case a.Rate > b.Rate:
+			return -1
+		case a.Rate < b.Rate:
+			return 1
+		}
+		return 0
+	})
+	return dst
+}
+
+func (e *estimator[T]) Reset() {
+	if e.once.Do(e.init); e.err != nil {
+		return
+	}
+	for i := 0; i < len(e.levels); i++ {
+		e.levels[i].Reset()
+	}
+	atomic.StoreUint64(&e.n, 0)
+}
+
+func (e *estimator[T]) ReadFrom(r io.Reader) (n int64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		return 0, e.err
+	}
+	var (
+		buf [32]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	sign, ver, bits, total := binary.LittleEndian.Uint64(buf[0:8]), binary.LittleEndian.Uint64(buf[8:16]),
+		binary.LittleEndian.Uint64(buf[16:24]), binary.LittleEndian.Uint64(buf[24:32])
+	if sign != dumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if ver != math.Float64bits(dumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	if bits != e.bits {
+		return n, ErrBitsMismatch
+	}
+
+	// decode to temporary levels to keep the estimator unchanged on partial read
+	levels, err := e.newLevels()
+	if err != nil {
+		return
+	}
+	for i := 0; i < len(levels); i++ {
+		var m64 int64
+		m64, err = levels[i].ReadFrom(r)
+		n += m64
+		if err != nil {
+			return
+		}
+	}
+	copy(e.levels, levels)
+	atomic.StoreUint64(&e.n, total)
+	return
+}
+
+func (e *estimator[T]) WriteTo(w io.Writer) (n int64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		return 0, e.err
+	}
+	var (
+		buf [32]byte
+		m   int
+	)
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], e.bits)
+	binary.LittleEndian.PutUint64(buf[24:32], atomic.LoadUint64(&e.n))
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+
+	for i := 0; i < len(e.levels); i++ {
+		var m64 int64
+		m64, err = e.levels[i].WriteTo(w)
+		n += m64
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Map key to position in universe [0..2^bits). Signed keys shifts by 2^(bits-1) to keep the order.
+func (e *estimator[T]) encode(key T) (pos uint64, err error) {
+	if e.signed {
+		pos = uint64(int64(key)) + 1<<(e.bits-1)
+	} else {
+		pos = uint64(key)
+	}
+	if e.bits < 64 && pos>>e.bits != 0 {
+		err = ErrOutOfRange
+	}
+	return
+}
+
+func (e *estimator[T]) decode(pos uint64) T {
+	if e.signed {
+		return T(int64(pos - 1<<(e.bits-1)))
+	}
+	return T(pos)
+}
+
+// Estimate frequency of node on given level.
+func (e *estimator[T]) hest(level, node uint64) uint64 {
+	return e.levels[level].HEstimate(e.hnode(level, node))
+}
+
+// Calculate hash of node on given level. Level mixes to the hash to make levels independent.
+func (e *estimator[T]) hnode(level, node uint64) uint64 {
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[0:8], node)
+	binary.LittleEndian.PutUint64(buf[8:16], level)
+	return e.conf.Hasher.Sum64(buf[:])
+}
+
+func (e *estimator[T]) mw() frequency.MetricsWriter {
+	return e.conf.MetricsWriter
+}
+
+func (e *estimator[T]) init() {
+	if e.conf.Hasher == nil {
+		e.err = pbtk.ErrNoHasher
+		return
+	}
+	if e.conf.Bits == 0 {
+		e.conf.Bits = defaultBits
+	}
+	if e.conf.Bits > 64 {
+		e.err = ErrInvalidBits
+		return
+	}
+	if e.conf.MetricsWriter == nil {
+		e.conf.MetricsWriter = frequency.DummyMetricsWriter{}
+	}
+	e.bits = e.conf.Bits
+	e.signed = ^T(0) < 0
+
+	e.levels, e.err = e.newLevels()
+}
+
+func (e *estimator[T]) newLevels() ([]frequency.Estimator[uint64], error) {
+	// Levels shouldn't write metrics, since the estimator does it itself.
+	lconf := e.conf.Config
+	lconf.MetricsWriter = frequency.DummyMetricsWriter{}
+	levels := make([]frequency.Estimator[uint64], e.bits)
+	for i := uint64(0); i < e.bits; i++ {
+		var err error
+		if levels[i], err = cmsketch.NewEstimator[uint64](&lconf); err != nil {
+			return nil, err
+		}
+	}
+	return levels, nil
+}
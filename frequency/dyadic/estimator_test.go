@@ -0,0 +1,199 @@
+package dyadic
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/frequency"
+)
+
+const (
+	testConfidence = 0.999
+	testEpsilon    = 0.0001
+	testBits       = 16
+)
+
+var testh = xxhash.Hasher64[[]byte]{}
+
+func TestEstimator(t *testing.T) {
+	fill := func(est frequency.RangeEstimator[int64]) {
+		// keys in range [-500..500), each key k occurs |k|%10+1 times
+		for k := int64(-500); k < 500; k++ {
+			_ = est.AddN(k, uint64(abs(k)%10+1))
+		}
+		// heavy hitter
+		_ = est.AddN(42, 10000)
+	}
+	t.Run("estimate", func(t *testing.T) {
+		est, err := NewEstimator[int64](NewConfig(testConfidence, testEpsilon, testh).WithBits(testBits))
+		if err != nil {
+			t.Fatal(err)
+		}
+		fill(est)
+		if e := est.Estimate(-7); e < 8 {
+			t.Errorf("expected estimate >= 8, got %d", e)
+		}
+		if e := est.Estimate(42); e < 10003 {
+			t.Errorf("expected estimate >= 10003, got %d", e)
+		}
+		if err = est.Add(1 << testBits); err != ErrOutOfRange {
+			t.Errorf("expected out of range error, got %v", err)
+		}
+	})
+	t.Run("range", func(t *testing.T) {
+		est, _ := NewEstimator[int64](NewConfig(testConfidence, testEpsilon, testh).WithBits(testBits))
+		fill(est)
+		stages := []struct{ lo, hi int64 }{
+			{-500, 499},
+			{-1 << (testBits - 1), 1<<(testBits-1) - 1},
+			{0, 0},
+			{-13, 77},
+			{100, 200},
+			{42, 42},
+		}
+		for _, st := range stages {
+			var must uint64
+			for k := max(st.lo, -500); k <= min(st.hi, 499); k++ {
+				must += uint64(abs(k)%10 + 1)
+				if k == 42 {
+					must += 10000
+				}
+			}
+			e := est.EstimateRange(st.lo, st.hi)
+			if e < must || float64(e-must) > 2*testBits*testEpsilon*float64(must+10000) {
+				t.Errorf("range [%d..%d]: expected ~%d, got %d", st.lo, st.hi, must, e)
+			}
+		}
+		if e := est.EstimateRange(10, -10); e != 0 {
+			t.Errorf("expected zero estimate of inverted range, got %d", e)
+		}
+	})
+	t.Run("quantile", func(t *testing.T) {
+		est, _ := NewEstimator[uint32](NewConfig(testConfidence, testEpsilon, testh).WithBits(testBits))
+		for k := uint32(1); k <= 1000; k++ {
+			_ = est.Add(k)
+		}
+		for _, q := range []float64{.1, .25, .5, .75, .99} {
+			must := q * 1000
+			if e := float64(est.Quantile(q)); e < must-10 || e > must+10 {
+				t.Errorf("quantile %f: expected ~%f, got %f", q, must, e)
+			}
+		}
+	})
+	t.Run("heavy hitters", func(t *testing.T) {
+		est, _ := NewEstimator[int64](NewConfig(testConfidence, testEpsilon, testh).WithBits(testBits))
+		fill(est)
+		hits := est.AppendHeavyHitters(nil, .1)
+		if len(hits) != 1 || hits[0].Key != 42 {
+			t.Errorf("expected single hitter 42, got %+v", hits)
+		}
+	})
+	t.Run("serialize", func(t *testing.T) {
+		testRW := func(t *testing.T, conf *Config) {
+			est, _ := NewEstimator[int64](conf)
+			fill(est)
+			var buf bytes.Buffer
+			n, err := est.WriteTo(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			est1, _ := NewEstimator[int64](conf)
+			m, err := est1.ReadFrom(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != m {
+				t.Fatalf("expected %d bytes, got %d", n, m)
+			}
+			if e0, e1 := est.EstimateRange(-100, 100), est1.EstimateRange(-100, 100); e0 != e1 {
+				t.Errorf("expected %d estimate, got %d", e0, e1)
+			}
+			// partial read keeps the estimator unchanged: dump of empty estimator is cut after a half of levels
+			empty, _ := NewEstimator[int64](conf)
+			_, _ = empty.WriteTo(&buf)
+			if _, err = est1.ReadFrom(io.LimitReader(&buf, 32+(n-32)/testBits*testBits/2)); err != io.EOF {
+				t.Errorf("expected EOF error, got %v", err)
+			}
+			if e0, e1 := est.EstimateRange(-100, 100), est1.EstimateRange(-100, 100); e0 != e1 {
+				t.Errorf("estimator changed by partial read: expected %d estimate, got %d", e0, e1)
+			}
+			if _, err = est1.ReadFrom(bytes.NewReader(make([]byte, 16))); err != io.ErrUnexpectedEOF {
+				t.Errorf("expected unexpected EOF error of short header, got %v", err)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			testRW(t, NewConfig(.99, .01, testh).WithBits(testBits))
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			testRW(t, NewConfig(.99, .01, testh).WithBits(testBits).WithConcurrency())
+		})
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		est, _ := NewEstimator[uint64](NewConfig(.99, .01, testh).WithBits(testBits).WithConcurrency())
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := uint64(0); ; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+					_ = est.Add(i % (1 << testBits))
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			tick := time.NewTicker(time.Millisecond * 5)
+			defer tick.Stop()
+			for i := uint64(0); ; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				case <-tick.C:
+					est.EstimateRange(i, i*2)
+					est.Quantile(.5)
+				}
+			}
+		}()
+		wg.Wait()
+	})
+}
+
+func BenchmarkEstimator(b *testing.B) {
+	est, _ := NewEstimator[uint64](NewConfig(testConfidence, testEpsilon, testh).WithBits(testBits))
+	b.Run("add", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = est.Add(uint64(i) % (1 << testBits))
+		}
+	})
+	b.Run("range", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			lo := uint64(i) % (1 << (testBits - 1))
+			est.EstimateRange(lo, lo*2)
+		}
+	})
+	b.Run("quantile", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			est.Quantile(.5)
+		}
+	})
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -5,4 +5,5 @@ import "errors"
 var (
 	ErrInvalidConfidence = errors.New("confidence must be in range (0..1)")
 	ErrInvalidEpsilon    = errors.New("epsilon must be in range (0..1)")
+	ErrDecayRange        = errors.New("decay factor or soft factor must be in range (0..1)")
 )
@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/heavy"
 )
 
 type base[T pbtk.Hashable] interface {
@@ -46,6 +47,37 @@ type PreciseEstimator[T pbtk.Hashable] interface {
 	HEstimate(hkey uint64) float64
 }
 
+// BoundsEstimator describes frequency estimator that reports error bounds of estimation.
+// Implemented by Count-Min Sketch, Conservative Update Sketch and TinyLFU. TinyLFU-EWMA and dyadic Count-Min Sketch
+// don't implement it since their error models aren't covered.
+type BoundsEstimator[T pbtk.Hashable] interface {
+	// EstimateWithBounds returns frequency estimation of key and its lower and upper bounds for given confidence
+	// level, e.g. 0.95.
+	EstimateWithBounds(key T, confidence float64) (est, lo, hi uint64)
+	// HEstimateWithBounds returns frequency estimation of precalculated hash key and its bounds.
+	HEstimateWithBounds(hkey uint64, confidence float64) (est, lo, hi uint64)
+}
+
+// SignedBoundsEstimator describes signed frequency estimator that reports error bounds of estimation.
+type SignedBoundsEstimator[T pbtk.Hashable] interface {
+	// EstimateWithBounds returns signed frequency estimation of key and its lower and upper bounds for given
+	// confidence level, e.g. 0.95.
+	EstimateWithBounds(key T, confidence float64) (est, lo, hi int64)
+	// HEstimateWithBounds returns signed frequency estimation of precalculated hash key and its bounds.
+	HEstimateWithBounds(hkey uint64, confidence float64) (est, lo, hi int64)
+}
+
+// RangeEstimator describes frequency estimator over ordered integer keys.
+type RangeEstimator[T pbtk.Integer] interface {
+	Estimator[T]
+	// EstimateRange returns total frequency estimation of keys in range [lo..hi].
+	EstimateRange(lo, hi T) uint64
+	// Quantile returns approximate key of q-quantile (q must be in range [0..1]).
+	Quantile(q float64) T
+	// AppendHeavyHitters appends to dst keys which frequency exceeds phi fraction of total count.
+	AppendHeavyHitters(dst []heavy.Hit[T], phi float64) []heavy.Hit[T]
+}
+
 type Decayer interface {
 	// Decay applies factor to all counters inside.
 	Decay(ctx context.Context, factor float64) error
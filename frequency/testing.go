@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -55,6 +56,18 @@ func (t *TestAdapter[T]) HAdd(hkey uint64) error {
 	return fmt.Errorf("no estimator found")
 }
 
+func (t *TestAdapter[T]) estimate(key T) float64 {
+	switch {
+	case t.unsigned != nil:
+		return float64(t.unsigned.Estimate(key))
+	case t.signed != nil:
+		return float64(t.signed.Estimate(key))
+	case t.precise != nil:
+		return t.precise.Estimate(key)
+	}
+	return 0
+}
+
 func (t *TestAdapter[T]) Reset() {
 	switch {
 	case t.unsigned != nil:
@@ -126,6 +139,35 @@ func TestMe[T []byte](t *testing.T, a *TestAdapter[T]) {
 	})
 }
 
+// TestMeBounds checks that estimation bounds cover true frequencies with given confidence.
+// Estimator must implement BoundsEstimator interface.
+func TestMeBounds[T []byte](t *testing.T, est Estimator[T], confidence float64) {
+	best, ok := est.(BoundsEstimator[T])
+	if !ok {
+		t.Fatal("estimator doesn't implement BoundsEstimator")
+	}
+	const keys = 2000
+	rng := rand.New(rand.NewSource(1))
+	hkeys, freq := make([]uint64, keys), make([]uint64, keys)
+	for i := 0; i < keys; i++ {
+		hkeys[i], freq[i] = rng.Uint64(), uint64(1+rng.Intn(100))
+		_ = est.HAddN(hkeys[i], freq[i])
+	}
+	var covered int
+	for i := 0; i < keys; i++ {
+		e, lo, hi := best.HEstimateWithBounds(hkeys[i], confidence)
+		if lo > e || e > hi {
+			t.Fatalf("estimation %d out of bounds [%d..%d]", e, lo, hi)
+		}
+		if lo <= freq[i] && freq[i] <= hi {
+			covered++
+		}
+	}
+	if ratio := float64(covered) / keys; ratio < confidence {
+		t.Errorf("bounds coverage too low: need %f, got %f", confidence, ratio)
+	}
+}
+
 func TestMeConcurrently[T []byte](t *testing.T, a *TestAdapter[T]) {
 	pbtk.EachTestingDataset(func(_ int, ds *pbtk.TestingDataset[[]byte]) {
 		t.Run(ds.Name, func(t *testing.T) {
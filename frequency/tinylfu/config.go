@@ -7,11 +7,6 @@ import (
 	"github.com/koykov/pbtk/frequency/cmsketch"
 )
 
-const (
-	defaultDecayFactor     = .5
-	defaultSoftDecayFactor = .75
-)
-
 type Config struct {
 	cmsketch.Config
 	// Count of added items to start decay.
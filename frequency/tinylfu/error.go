@@ -1,5 +1,6 @@
 package tinylfu
 
-import "errors"
+import "github.com/koykov/pbtk/frequency"
 
-var ErrDecayRange = errors.New("decay factor or soft factor must be in range (0..1)")
+// ErrDecayRange is an alias of frequency.ErrDecayRange kept for compatibility.
+var ErrDecayRange = frequency.ErrDecayRange
@@ -2,10 +2,7 @@ package tinylfu
 
 import (
 	"context"
-	"math"
 	"sync"
-	"sync/atomic"
-	"time"
 
 	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/frequency"
@@ -15,16 +12,11 @@ import (
 const flagLFU = 1
 
 type estimator[T pbtk.Hashable] struct {
-	conf   *Config
-	est    frequency.Estimator[T]
-	dec    frequency.Decayer
-	once   sync.Once
-	cancel context.CancelFunc // main stop func
-	tc     timer              // time reached notifier
-	c      uint64             // counter of added items
-	cc     chan struct{}      // counter reached notifier
-	svc    uint32             // decay running flag
-	lt     int64              // last decay timestamp
+	conf *Config
+	est  frequency.Estimator[T]
+	dec  frequency.Decayer
+	once sync.Once
+	sch  *frequency.DecayScheduler
 
 	err error
 }
@@ -50,108 +42,41 @@ func NewEstimator[T pbtk.Hashable](conf *Config) (frequency.Estimator[T], error)
 	return e, nil
 }
 
-func (e *estimator[T]) Add(key T) error                   { return e.checkCntr(e.est.Add(key)) }
-func (e *estimator[T]) AddN(key T, n uint64) error        { return e.checkCntr(e.est.AddN(key, n)) }
-func (e *estimator[T]) HAdd(hkey uint64) error            { return e.checkCntr(e.est.HAdd(hkey)) }
-func (e *estimator[T]) HAddN(hkey uint64, n uint64) error { return e.checkCntr(e.est.HAddN(hkey, n)) }
+func (e *estimator[T]) Add(key T) error                   { return e.sch.Observe(e.est.Add(key)) }
+func (e *estimator[T]) AddN(key T, n uint64) error        { return e.sch.Observe(e.est.AddN(key, n)) }
+func (e *estimator[T]) HAdd(hkey uint64) error            { return e.sch.Observe(e.est.HAdd(hkey)) }
+func (e *estimator[T]) HAddN(hkey uint64, n uint64) error { return e.sch.Observe(e.est.HAddN(hkey, n)) }
 
-func (e *estimator[T]) checkCntr(err error) error {
-	if err != nil || e.conf.DecayLimit == 0 {
-		return err
-	}
-	if atomic.AddUint64(&e.c, 1) == e.conf.DecayLimit {
-		e.cc <- struct{}{}
-	}
-	return nil
+// EstimateWithBounds returns frequency estimation of key and its bounds using error model of underlying Count-Min
+// Sketch.
+func (e *estimator[T]) EstimateWithBounds(key T, confidence float64) (est, lo, hi uint64) {
+	return e.est.(frequency.BoundsEstimator[T]).EstimateWithBounds(key, confidence)
+}
+
+// HEstimateWithBounds returns frequency estimation of precalculated hash key and its bounds.
+func (e *estimator[T]) HEstimateWithBounds(hkey uint64, confidence float64) (est, lo, hi uint64) {
+	return e.est.(frequency.BoundsEstimator[T]).HEstimateWithBounds(hkey, confidence)
+}
+
+// Decay applies decay factor to counters immediately, regardless of scheduler.
+func (e *estimator[T]) Decay(ctx context.Context, factor float64) error {
+	return e.dec.Decay(ctx, factor)
 }
 
 func (e *estimator[T]) Close() error {
-	e.cancel()
-	return nil
+	return e.sch.Close()
 }
 
 func (e *estimator[T]) init() {
-	if e.conf.DecayFactor == 0 {
-		e.conf.DecayFactor = defaultDecayFactor
-	}
-	if e.conf.DecayFactor < 0 || e.conf.DecayFactor >= 1 {
-		e.err = ErrDecayRange
-		return
-	}
-	if e.conf.SoftDecayFactor == 0 {
-		e.conf.SoftDecayFactor = defaultSoftDecayFactor
-	}
-	if e.conf.SoftDecayFactor < 0 || e.conf.SoftDecayFactor >= 1 {
-		e.err = ErrDecayRange
-		return
-	}
-	if e.conf.ForceDecayNotifier == nil {
-		e.conf.ForceDecayNotifier = dummyForceDecayNotifier{}
-	}
 	if e.conf.Concurrent == nil {
 		// only concurrent CMS allowed due to async decay
 		e.conf.Concurrent = &cmsketch.ConcurrentConfig{}
 	}
-
-	// counter
-	e.c = math.MaxUint64
-	if e.conf.DecayLimit > 0 {
-		e.cc = make(chan struct{})
-	}
-	// timer
-	e.tc = &stuckTimer{}
-	if e.conf.DecayInterval > 0 {
-		e.tc = newNativeTimer(e.conf.DecayInterval)
-	}
-
-	ctx, cancel := context.WithCancel(context.Background())
-	e.cancel = cancel
-	go e.watch(ctx)
-}
-
-func (e *estimator[T]) watch(ctx context.Context) {
-	for {
-		select {
-		case <-ctx.Done():
-			e.err = pbtk.ErrClosed
-			e.tc.Stop()
-			if e.cc != nil {
-				close(e.cc)
-			}
-			return
-		case <-e.conf.ForceDecayNotifier.Notify():
-			e.decay(ctx)
-		case <-e.tc.C():
-			e.decay(ctx)
-		case <-e.cc:
-			e.decay(ctx)
-		}
-	}
-}
-
-func (e *estimator[T]) decay(ctx context.Context) {
-	if !atomic.CompareAndSwapUint32(&e.svc, 0, 1) {
-		return
-	}
-	defer atomic.StoreUint32(&e.svc, 0)
-
-	factor := e.conf.DecayFactor
-	{
-		// try soft decay
-		var interval, counter bool
-		if lt := atomic.LoadInt64(&e.lt); lt > 0 {
-			left := time.Now().Sub(time.Unix(0, lt))
-			interval = left > 0 && left < e.conf.DecayInterval/2
-		}
-		c := atomic.LoadUint64(&e.c)
-		counter = c > 0 && c < e.conf.DecayLimit/2
-		if interval || counter {
-			factor = e.conf.SoftDecayFactor
-		}
-	}
-
-	e.tc.Reset(e.conf.DecayInterval)
-	atomic.StoreUint64(&e.c, 0)
-	atomic.StoreInt64(&e.lt, time.Now().UnixNano())
-	_ = e.dec.Decay(ctx, factor)
+	e.sch, e.err = frequency.NewDecayScheduler(e.dec, &frequency.DecayConfig{
+		Limit:      e.conf.DecayLimit,
+		Interval:   e.conf.DecayInterval,
+		Notifier:   e.conf.ForceDecayNotifier,
+		Factor:     e.conf.DecayFactor,
+		SoftFactor: e.conf.SoftDecayFactor,
+	})
 }
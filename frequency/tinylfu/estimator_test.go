@@ -24,6 +24,14 @@ func TestEstimator(t *testing.T) {
 		}
 		frequency.TestMe(t, frequency.NewTestAdapter(est))
 	})
+	t.Run("bounds", func(t *testing.T) {
+		est, err := NewEstimator[[]byte](NewConfig(.99, .005, testh))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = est.(io.Closer).Close() }()
+		frequency.TestMeBounds(t, est, .95)
+	})
 	t.Run("decay", func(t *testing.T) {
 		tryclose := func(est frequency.Estimator[string]) error {
 			if c, ok := any(est).(io.Closer); ok {
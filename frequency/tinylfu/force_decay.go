@@ -1,11 +1,6 @@
 package tinylfu
 
-type ForceDecayNotifier interface {
-	Notify() <-chan struct{}
-}
+import "github.com/koykov/pbtk/frequency"
 
-type dummyForceDecayNotifier struct{}
-
-func (dummyForceDecayNotifier) Notify() <-chan struct{} {
-	return nil
-}
+// ForceDecayNotifier is an alias of frequency.DecayNotifier kept for compatibility.
+type ForceDecayNotifier = frequency.DecayNotifier
@@ -28,6 +28,8 @@ type Config struct {
 	// Hasher to calculate hash sum of the items.
 	// Mandatory param.
 	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
 	// EWMA settings.
 	EWMA EWMA
 	// Clock to measure time deltas. Testing stuff.
@@ -109,6 +111,11 @@ func WithMetricsWriter(conf *Config, mw frequency.PreciseMetricsWriter) *Config
 	return conf
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
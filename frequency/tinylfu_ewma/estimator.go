@@ -1,6 +1,7 @@
 package tinylfu
 
 import (
+	"context"
 	"io"
 	"math"
 	"sync"
@@ -116,11 +117,20 @@ func (e *estimator[T]) WriteTo(w io.Writer) (int64, error) {
 	return e.vec.writeTo(w)
 }
 
+// Decay applies factor to all counters. Time of last update of each counter keeps as is, so EWMA continues to work.
+func (e *estimator[T]) Decay(ctx context.Context, factor float64) error {
+	if e.once.Do(e.init); e.err != nil {
+		return e.err
+	}
+	return e.vec.decay(ctx, factor)
+}
+
 func (e *estimator[T]) mw() frequency.PreciseMetricsWriter {
 	return e.conf.MetricsWriter
 }
 
 func (e *estimator[T]) init() {
+	e.SetEncoding(e.conf.Encoding)
 	if e.conf.Hasher == nil {
 		e.err = pbtk.ErrNoHasher
 		return
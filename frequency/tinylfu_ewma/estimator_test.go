@@ -1,6 +1,7 @@
 package tinylfu
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"os"
@@ -153,6 +154,28 @@ func TestEstimator(t *testing.T) {
 			})
 		})
 	})
+	t.Run("decay", func(t *testing.T) {
+		testDecay := func(t *testing.T, est frequency.PreciseEstimator[string]) {
+			_ = est.AddN("foobar", 10)
+			if err := any(est).(frequency.Decayer).Decay(context.Background(), .5); err != nil {
+				t.Fatal(err)
+			}
+			if e := est.Estimate("foobar"); math.Abs(e-5) > 1e-6 {
+				t.Errorf("expected %f estimate, got %f", 5., e)
+			}
+		}
+		t.Run("sync", func(t *testing.T) {
+			e, _ := NewEstimator[string](NewConfig(.99, .01, testh).
+				WithClock(newTestClock(time.Now())))
+			testDecay(t, e)
+		})
+		t.Run("concurrent", func(t *testing.T) {
+			e, _ := NewEstimator[string](NewConfig(.99, .01, testh).
+				WithClock(newTestClock(time.Now())).
+				WithConcurrency())
+			testDecay(t, e)
+		})
+	})
 	t.Run("writer", func(t *testing.T) {
 		testWrite := func(t *testing.T, est frequency.PreciseEstimator[string], path string, expect int64) {
 			_ = est.Add("foobar")
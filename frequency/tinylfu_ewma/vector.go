@@ -1,6 +1,7 @@
 package tinylfu
 
 import (
+	"context"
 	"io"
 	"math"
 )
@@ -8,6 +9,7 @@ import (
 type vector interface {
 	set(pos, n uint64, dtime uint32) error
 	get(pos uint64, stime, now uint32) float64
+	decay(ctx context.Context, factor float64) error
 	reset()
 	readFrom(r io.Reader) (int64, error)
 	writeTo(w io.Writer) (int64, error)
@@ -64,6 +66,12 @@ func (vec *basevec) estimate(val uint64, stime, now uint32) float64 {
 	return float64(valOld) * decay
 }
 
+// Apply factor to the counter, keeping time of last update as is.
+func (vec *basevec) decayval(val uint64, factor float64) uint64 {
+	dtime, n := vec.decode(val)
+	return vec.encode(dtime, uint32(float64(n)*factor))
+}
+
 func (vec *basevec) exp(dtime uint32) float64 {
 	if uint64(dtime) >= vec.exptabsz {
 		return math.Exp(-float64(dtime) / float64(vec.tau))
@@ -1,6 +1,7 @@
 package tinylfu
 
 import (
+	"context"
 	"encoding/binary"
 	"io"
 	"math"
@@ -35,6 +36,25 @@ func (vec *cnvec) get(pos uint64, stime, now uint32) float64 {
 	return vec.estimate(val, stime, now)
 }
 
+func (vec *cnvec) decay(ctx context.Context, factor float64) error {
+	for i := 0; i < len(vec.buf); i++ {
+		var ok bool
+		for j := uint64(0); j < vec.lim+1; j++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			val := atomic.LoadUint64(&vec.buf[i])
+			if ok = atomic.CompareAndSwapUint64(&vec.buf[i], val, vec.decayval(val, factor)); ok {
+				break
+			}
+		}
+		if !ok {
+			return pbtk.ErrWriteLimitExceed
+		}
+	}
+	return nil
+}
+
 func (vec *cnvec) reset() {
 	for i := 0; i < len(vec.buf); i++ {
 		atomic.StoreUint64(&vec.buf[i], 0)
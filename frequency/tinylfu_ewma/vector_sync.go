@@ -1,6 +1,7 @@
 package tinylfu
 
 import (
+	"context"
 	"encoding/binary"
 	"io"
 	"math"
@@ -31,6 +32,18 @@ func (vec *syncvec) get(pos uint64, stime, now uint32) float64 {
 	return vec.estimate(val, stime, now)
 }
 
+func (vec *syncvec) decay(ctx context.Context, factor float64) error {
+	for i := 0; i < len(vec.buf); i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			vec.buf[i] = vec.decayval(vec.buf[i], factor)
+		}
+	}
+	return nil
+}
+
 func (vec *syncvec) reset() {
 	memclr64.ClearUnsafe(unsafe.Pointer(&vec.buf[0]), len(vec.buf)*8)
 }
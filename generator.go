@@ -0,0 +1,157 @@
+package pbtk
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	consonants = "bcdfghjklmnprstv"
+	vowels     = "aeio"
+)
+
+// Words is a deterministic generator of pseudo-words. Each id maps to the unique word made of syllables, thus set of
+// n ids gives exactly n distinct words. Generators with the same seed make the same words.
+type Words struct {
+	syl [64][2]byte
+}
+
+func NewWords(seed int64) *Words {
+	w := &Words{}
+	rng := rand.New(rand.NewSource(seed))
+	for i, j := range rng.Perm(len(w.syl)) {
+		w.syl[i] = [2]byte{consonants[j>>2], vowels[j&3]}
+	}
+	return w
+}
+
+// AppendWord appends word of id to dst.
+func (w *Words) AppendWord(dst []byte, id uint64) []byte {
+	// base-64 digits of id, each digit is a syllable
+	for {
+		s := &w.syl[id&63]
+		dst = append(dst, s[0], s[1])
+		if id >>= 6; id == 0 {
+			return dst
+		}
+	}
+}
+
+// Stream is a deterministic stream of testing keys: streams made with the same params yield the same keys.
+type Stream interface {
+	// AppendNext appends next key to dst. Returns false if stream is over.
+	AppendNext(dst []byte) ([]byte, bool)
+	// Reset rewinds the stream to the beginning.
+	Reset()
+}
+
+// CardinalityStream yields exactly Cardinality distinct keys, each key repeats the same number of times in
+// pseudo-random order.
+type CardinalityStream struct {
+	w          *Words
+	uniq, n, a uint64
+	i          uint64
+}
+
+// NewCardinalityStream makes stream of uniq distinct keys repeated repeats times each.
+func NewCardinalityStream(seed int64, uniq, repeats uint64) *CardinalityStream {
+	s := &CardinalityStream{
+		w:    NewWords(seed),
+		uniq: uniq,
+		n:    uniq * max(repeats, 1),
+	}
+	// multiplier coprime with the length makes permutation of stream positions
+	s.a = uint64(rand.New(rand.NewSource(seed)).Int63())%max(s.n, 1) | 1
+	for gcd(s.a, s.n) != 1 {
+		s.a += 2
+	}
+	return s
+}
+
+func (s *CardinalityStream) AppendNext(dst []byte) ([]byte, bool) {
+	if s.i >= s.n {
+		return dst, false
+	}
+	hi, lo := bits.Mul64(s.a, s.i)
+	_, pos := bits.Div64(hi, lo, s.n)
+	s.i++
+	return s.w.AppendWord(dst, pos%s.uniq), true
+}
+
+// Cardinality returns number of distinct keys in the stream.
+func (s *CardinalityStream) Cardinality() uint64 {
+	return s.uniq
+}
+
+// Len returns total number of keys in the stream.
+func (s *CardinalityStream) Len() uint64 {
+	return s.n
+}
+
+func (s *CardinalityStream) Reset() {
+	s.i = 0
+}
+
+// ZipfStream yields keys with Zipfian distribution: key of rank k (starting from 0) occurs with probability
+// proportional to 1/(k+1)^s.
+type ZipfStream struct {
+	w       *Words
+	seed    int64
+	s       float64
+	imax, n uint64
+	i       uint64
+	zipf    *rand.Zipf
+}
+
+// NewZipfStream makes stream of length keys from universe of distinct keys with skew s > 1.
+func NewZipfStream(seed int64, s float64, universe, length uint64) *ZipfStream {
+	z := &ZipfStream{
+		w:    NewWords(seed),
+		seed: seed,
+		s:    math.Max(s, math.Nextafter(1, 2)),
+		imax: max(universe, 1) - 1,
+		n:    length,
+	}
+	z.Reset()
+	return z
+}
+
+func (z *ZipfStream) AppendNext(dst []byte) ([]byte, bool) {
+	rank, ok := z.NextRank()
+	if !ok {
+		return dst, false
+	}
+	return z.w.AppendWord(dst, rank), true
+}
+
+// NextRank returns rank of the next key instead of the key itself. Key may be obtained using AppendKey.
+func (z *ZipfStream) NextRank() (uint64, bool) {
+	if z.i >= z.n {
+		return 0, false
+	}
+	z.i++
+	return z.zipf.Uint64(), true
+}
+
+// AppendKey appends key of given rank to dst. May be used to check estimations of the most frequent keys.
+func (z *ZipfStream) AppendKey(dst []byte, rank uint64) []byte {
+	return z.w.AppendWord(dst, rank)
+}
+
+// Len returns total number of keys in the stream.
+func (z *ZipfStream) Len() uint64 {
+	return z.n
+}
+
+func (z *ZipfStream) Reset() {
+	z.i = 0
+	z.zipf = rand.NewZipf(rand.New(rand.NewSource(z.seed)), z.s, 1, z.imax)
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
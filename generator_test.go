@@ -0,0 +1,80 @@
+package pbtk
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWords(t *testing.T) {
+	w0, w1 := NewWords(1), NewWords(1)
+	uniq := make(map[string]struct{})
+	for i := uint64(0); i < 1e5; i++ {
+		a, b := w0.AppendWord(nil, i), w1.AppendWord(nil, i)
+		if !bytes.Equal(a, b) {
+			t.Fatalf("words of the same seed mismatch: %s vs %s", a, b)
+		}
+		uniq[string(a)] = struct{}{}
+	}
+	if len(uniq) != 1e5 {
+		t.Errorf("expected %d distinct words, got %d", int(1e5), len(uniq))
+	}
+	if bytes.Equal(NewWords(2).AppendWord(nil, 100), w0.AppendWord(nil, 100)) {
+		t.Error("words of different seeds must differ")
+	}
+}
+
+func TestCardinalityStream(t *testing.T) {
+	for _, c := range []struct{ uniq, repeats uint64 }{{1, 1}, {1000, 1}, {1000, 7}, {4096, 4}} {
+		s := NewCardinalityStream(1, c.uniq, c.repeats)
+		counts := make(map[string]uint64)
+		var buf []byte
+		for ok := true; ; {
+			if buf, ok = s.AppendNext(buf[:0]); !ok {
+				break
+			}
+			counts[string(buf)]++
+		}
+		if uint64(len(counts)) != s.Cardinality() {
+			t.Errorf("expected cardinality %d, got %d", s.Cardinality(), len(counts))
+		}
+		for k, n := range counts {
+			if n != c.repeats {
+				t.Fatalf("key %s repeats %d times, expected %d", k, n, c.repeats)
+			}
+		}
+		s.Reset()
+		first, _ := s.AppendNext(nil)
+		s.Reset()
+		if again, _ := s.AppendNext(nil); !bytes.Equal(first, again) {
+			t.Error("stream must be reproducible after reset")
+		}
+	}
+}
+
+func TestZipfStream(t *testing.T) {
+	const n = 1e5
+	s := NewZipfStream(1, 1.2, 1000, n)
+	counts := make(map[string]int)
+	var (
+		buf   []byte
+		total int
+	)
+	for ok := true; ; {
+		if buf, ok = s.AppendNext(buf[:0]); !ok {
+			break
+		}
+		counts[string(buf)]++
+		total++
+	}
+	if total != n {
+		t.Fatalf("expected %d keys, got %d", int(n), total)
+	}
+	top, second := counts[string(s.AppendKey(nil, 0))], counts[string(s.AppendKey(nil, 1))]
+	if top <= second {
+		t.Errorf("key of rank 0 must be the most frequent: %d vs %d", top, second)
+	}
+	s.Reset()
+	if buf, _ = s.AppendNext(buf[:0]); counts[string(buf)] == 0 {
+		t.Error("stream must be reproducible after reset")
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/koykov/byteseq v1.0.1
 	github.com/koykov/hash v1.0.1-0.20250520162830-f30a465d00b2
 	github.com/koykov/simd v0.0.0-20250519211341-8f25bd733fd3
+	golang.org/x/text v0.22.0
 )
 
 require golang.org/x/sys v0.30.0 // indirect
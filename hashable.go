@@ -15,6 +15,12 @@ type Hashable interface {
 		~string | ~[]byte | ~[]rune
 }
 
+// Integer is a constraint that permits any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
 func cmph[T Hashable](a, b T) int {
 	switch x := any(a).(type) {
 	case int:
@@ -11,6 +11,7 @@ type Config struct {
 	Epsilon       float64
 	Support       float64
 	Hasher        pbtk.Hasher
+	Encoding      pbtk.Encoding // keys encoding before hashing (see pbtk.Encoding)
 	Buckets       uint64
 	MetricsWriter heavy.MetricsWriter
 }
@@ -34,6 +35,11 @@ func (c *Config) WithMetricsWriter(mw heavy.MetricsWriter) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
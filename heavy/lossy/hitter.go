@@ -96,6 +96,7 @@ func (h *hitter[T]) Reset() {
 }
 
 func (h *hitter[T]) init() {
+	h.SetEncoding(h.conf.Encoding)
 	if h.conf.Hasher == nil {
 		h.err = pbtk.ErrNoHasher
 		return
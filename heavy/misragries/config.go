@@ -10,6 +10,7 @@ const defaultBuckets = 4
 type Config struct {
 	K             uint64
 	Hasher        pbtk.Hasher
+	Encoding      pbtk.Encoding // keys encoding before hashing (see pbtk.Encoding)
 	Buckets       uint64
 	MetricsWriter heavy.MetricsWriter
 }
@@ -32,6 +33,11 @@ func (c *Config) WithMetricsWriter(mw heavy.MetricsWriter) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
@@ -14,6 +14,8 @@ type Config struct {
 	// Keys hasher.
 	// Mandatory param.
 	Hasher pbtk.Hasher
+	// Keys encoding before hashing (see pbtk.Encoding).
+	Encoding pbtk.Encoding
 	// Number of buckets.
 	// Many buckets reduces contention, but eats more memory.
 	// If this param omit, defaultBuckets (4) will use instead.
@@ -52,6 +54,11 @@ func (c *Config) WithMetricsWriter(mw heavy.MetricsWriter) *Config {
 	return c
 }
 
+func (c *Config) WithEncoding(enc pbtk.Encoding) *Config {
+	c.Encoding = enc
+	return c
+}
+
 func (c *Config) copy() *Config {
 	cpy := *c
 	return &cpy
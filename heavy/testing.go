@@ -15,12 +15,13 @@ func TestMe[T []byte](t *testing.T, h Hitter[T], repeatRange int) {
 				return
 			}
 			repeat := len(ds.All) / repeatRange
+			rng := rand.New(rand.NewSource(1))
 			for i := 0; i < len(ds.All); i++ {
 				if err := h.Add(ds.All[i]); err != nil {
 					t.Fatal(err)
 				}
 				if i%repeat == 0 {
-					j := rand.Intn(6)
+					j := rng.Intn(6)
 					_ = h.Add(ds.All[j])
 				}
 			}
@@ -26,6 +26,16 @@ func NewConfig[T byteseq.Q](algo pbtk.Hasher, k uint64, shingler shingle.Shingle
 	}
 }
 
+func (c *Config[T]) WithMode(mode minhash.Mode) *Config[T] {
+	c.Mode = mode
+	return c
+}
+
+func (c *Config[T]) WithStream() *Config[T] {
+	c.Stream = true
+	return c
+}
+
 func (c *Config[T]) copy() *Config[T] {
 	cpy := *c
 	return &cpy
@@ -5,6 +5,7 @@ import (
 
 	"github.com/koykov/hash/xxhash"
 	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/lsh/minhash"
 	"github.com/koykov/pbtk/shingle"
 )
 
@@ -27,6 +28,44 @@ func TestHash(t *testing.T) {
 		_ = err
 		lsh.TestMe(t, h, lsh.TestDistJaccard, testk, 1.0)
 	})
+	t.Run("stream", func(t *testing.T) {
+		// solid and stream hashers need own shinglers and vectors
+		solid, _ := NewHasher[[]byte](NewConfig(testh, testk, shingle.NewChar[[]byte](3, ""), testb))
+		stream, err := NewHasher[[]byte](NewConfig(testh, testk, shingle.NewChar[[]byte](3, ""), testb).WithStream())
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestStreamMe(t, solid, stream)
+
+		solid, _ = NewHasher[[]byte](NewConfig(testh, 256, shingle.NewChar[[]byte](3, ""), testb).
+			WithMode(minhash.ModeOnePermutation))
+		stream, _ = NewHasher[[]byte](NewConfig(testh, 256, shingle.NewChar[[]byte](3, ""), testb).
+			WithMode(minhash.ModeOnePermutation).WithStream())
+		lsh.TestStreamMe(t, solid, stream)
+	})
+	t.Run("one permutation", func(t *testing.T) {
+		// lower bits of full minimums of bins
+		full, _ := minhash.NewHasher[[]byte](minhash.NewConfig(testh, 256, shingle.NewChar[[]byte](3, "")).
+			WithMode(minhash.ModeOnePermutation))
+		h, _ := NewHasher[[]byte](NewConfig(testh, 256, shingle.NewChar[[]byte](3, ""), testb).
+			WithMode(minhash.ModeOnePermutation))
+		doc := []byte("The young boys are playing outdoors and the man is smiling nearby")
+		_ = full.Add(doc)
+		_ = h.Add(doc)
+		expect, r := full.Hash(), h.Hash()
+		if len(r) != len(expect) {
+			t.Fatalf("expected %d values, got %d", len(expect), len(r))
+		}
+		for i := 0; i < len(r); i++ {
+			if r[i] != expect[i]&(1<<testb-1) {
+				t.Fatalf("value %d: expected %d, got %d", i, expect[i]&(1<<testb-1), r[i])
+			}
+		}
+	})
+	t.Run("signature", func(t *testing.T) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshc, testb))
+		lsh.TestSignatureMe(t, h, lsh.Params{Kind: lsh.KindBBitMinHash, K: testk, B: testb})
+	})
 }
 
 func BenchmarkHash(b *testing.B) {
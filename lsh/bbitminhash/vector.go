@@ -157,6 +157,10 @@ func (v *vector) AppendAll(dst []uint64) []uint64 {
 	return dst
 }
 
+func (v *vector) Bits() uint64 {
+	return v.b
+}
+
 func (v *vector) Len() uint64 {
 	return v.c
 }
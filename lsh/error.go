@@ -3,8 +3,14 @@ package lsh
 import "errors"
 
 var (
-	ErrNoShingler = errors.New("no shingler provided")
-	ErrZeroK      = errors.New("zero K provided")
-	ErrZeroB      = errors.New("zero B provided")
-	ErrBigB       = errors.New("too big B provided, must be less than 64")
+	ErrNoShingler    = errors.New("no shingler provided")
+	ErrZeroK         = errors.New("zero K provided")
+	ErrZeroB         = errors.New("zero B provided")
+	ErrBigB          = errors.New("too big B provided, must be less than 64")
+	ErrUnknownMode   = errors.New("unknown mode")
+	ErrInvalidWidth  = errors.New("invalid width provided, must be 64, 128 or 256")
+	ErrInvalidWeight = errors.New("weight must be non-negative finite number")
+
+	ErrStreamUnsupported = errors.New("shingler doesn't support streaming")
+	ErrSignatureMismatch = errors.New("signatures produced by hashers with different params")
 )
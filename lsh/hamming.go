@@ -0,0 +1,18 @@
+package lsh
+
+import "math/bits"
+
+// Hamming returns Hamming distance between multi-word fingerprints a and b.
+// Missing words of shorter fingerprint considered as zeros.
+func Hamming(a, b []uint64) (d uint64) {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	for i := 0; i < len(b); i++ {
+		d += uint64(bits.OnesCount64(a[i] ^ b[i]))
+	}
+	for i := len(b); i < len(a); i++ {
+		d += uint64(bits.OnesCount64(a[i]))
+	}
+	return
+}
@@ -0,0 +1,48 @@
+package index
+
+type Config struct {
+	// Length of signature: number of hash values for banding index (MinHash) or number of bits for bit sampling
+	// index (SimHash).
+	// Mandatory param.
+	K uint64
+	// Similarity threshold of the documents to select as candidates.
+	// Bands and rows are tuned so that documents with similarity above the threshold become candidates with high
+	// probability and documents below - with low probability.
+	// Ignored if Bands and Rows are set explicitly.
+	Threshold float64
+	// Number of bands (hash tables).
+	Bands uint64
+	// Number of rows (signature values or bits) per band.
+	Rows uint64
+	// Seed of random bits positions selection (bit sampling index only).
+	Seed int64
+	// Hasher (lsh.Hasher of any type) of indexed signatures.
+	// If this param set, index checks it: banding index requires positional signatures (e.g. MinHash in one
+	// permutation mode, see lsh.Positional), signature length must be equal to K if hasher implements lsh.Parametric.
+	Hasher any
+}
+
+func NewConfig(k uint64, threshold float64) *Config {
+	return &Config{K: k, Threshold: threshold}
+}
+
+// WithBands sets bands and rows explicitly instead of tuning by threshold.
+func (c *Config) WithBands(bands, rows uint64) *Config {
+	c.Bands, c.Rows = bands, rows
+	return c
+}
+
+func (c *Config) WithSeed(seed int64) *Config {
+	c.Seed = seed
+	return c
+}
+
+func (c *Config) WithHasher(hasher any) *Config {
+	c.Hasher = hasher
+	return c
+}
+
+func (c *Config) copy() *Config {
+	cpy := *c
+	return &cpy
+}
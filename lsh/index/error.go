@@ -0,0 +1,12 @@
+package index
+
+import "errors"
+
+var (
+	ErrInvalidThreshold = errors.New("threshold must be in range (0..1)")
+	ErrBandsOverflow    = errors.New("bands*rows must be less or equal K")
+	ErrRowsOverflow     = errors.New("rows must be less or equal K")
+	ErrSignatureLength  = errors.New("signature length mismatch")
+	ErrConfigMismatch   = errors.New("dump config mismatch")
+	ErrNotPositional    = errors.New("banding index requires positional signatures")
+)
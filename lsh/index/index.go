@@ -0,0 +1,297 @@
+package index
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+	"sync"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/lsh"
+)
+
+const (
+	dumpSignature = 0x6c5e0f2a93d1b847
+	dumpVersion   = 1.0
+)
+
+// Index implementation.
+// Caution! Index isn't lock-free structure, all operations are protected by RW mutex.
+type index struct {
+	once   sync.Once
+	conf   *Config
+	kind   uint64
+	b, r   uint64
+	scheme *scheme
+	mux    sync.RWMutex
+	bands  []map[uint64][]uint64 // band key -> documents ids
+	docs   map[uint64][]uint64   // document id -> band keys
+
+	err error
+}
+
+// NewBanding creates new banding index of MinHash signatures.
+// Signature of K hash values splits to b bands of r values, documents with equal band become candidates. Signatures
+// must be positional (MinHash in one permutation mode or weighted MinHash), see Config.Hasher.
+func NewBanding(conf *Config) (Index, error) {
+	return newIndex(conf, schemeBanding)
+}
+
+// NewBitSampling creates new bit sampling index of SimHash signatures.
+// Each of b bands consists of r random bits of K-bit signature, documents with equal band become candidates.
+func NewBitSampling(conf *Config) (Index, error) {
+	return newIndex(conf, schemeBitSampling)
+}
+
+func newIndex(conf *Config, kind uint64) (Index, error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	idx := &index{conf: conf.copy(), kind: kind}
+	if idx.once.Do(idx.init); idx.err != nil {
+		return nil, idx.err
+	}
+	return idx, nil
+}
+
+func (idx *index) Insert(id uint64, signature []uint64) error {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return idx.err
+	}
+	keys, err := idx.scheme.appendKeys(make([]uint64, 0, idx.b), signature)
+	if err != nil {
+		return err
+	}
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	idx.remove(id)
+	idx.insert(id, keys)
+	return nil
+}
+
+func (idx *index) insert(id uint64, keys []uint64) {
+	for i := 0; i < len(keys); i++ {
+		idx.bands[i][keys[i]] = append(idx.bands[i][keys[i]], id)
+	}
+	idx.docs[id] = keys
+}
+
+func (idx *index) Remove(id uint64) bool {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return false
+	}
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	return idx.remove(id)
+}
+
+func (idx *index) remove(id uint64) bool {
+	keys, ok := idx.docs[id]
+	if !ok {
+		return false
+	}
+	for i := 0; i < len(keys); i++ {
+		bucket := idx.bands[i][keys[i]]
+		if j := slices.Index(bucket, id); j >= 0 {
+			bucket[j] = bucket[len(bucket)-1]
+			bucket = bucket[:len(bucket)-1]
+		}
+		if len(bucket) == 0 {
+			delete(idx.bands[i], keys[i])
+		} else {
+			idx.bands[i][keys[i]] = bucket
+		}
+	}
+	delete(idx.docs, id)
+	return true
+}
+
+func (idx *index) Query(signature []uint64) []uint64 {
+	return idx.AppendQuery(nil, signature)
+}
+
+func (idx *index) AppendQuery(dst []uint64, signature []uint64) []uint64 {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return dst
+	}
+	var a [64]uint64
+	keys, err := idx.scheme.appendKeys(a[:0], signature)
+	if err != nil {
+		return dst
+	}
+	off := len(dst)
+	idx.mux.RLock()
+	for i := 0; i < len(keys); i++ {
+		dst = append(dst, idx.bands[i][keys[i]]...)
+	}
+	idx.mux.RUnlock()
+	// deduplicate candidates found in several bands
+	slices.Sort(dst[off:])
+	return dst[:off+len(slices.Compact(dst[off:]))]
+}
+
+func (idx *index) Bands() (bands, rows uint64) {
+	return idx.b, idx.r
+}
+
+func (idx *index) Len() uint64 {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return 0
+	}
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+	return uint64(len(idx.docs))
+}
+
+func (idx *index) Reset() {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return
+	}
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	for i := 0; i < len(idx.bands); i++ {
+		clear(idx.bands[i])
+	}
+	clear(idx.docs)
+}
+
+// WriteTo writes index to w. Dump contains header and list of documents ids with their band keys.
+func (idx *index) WriteTo(w io.Writer) (n int64, err error) {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return 0, idx.err
+	}
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+
+	const blocksz = 4096
+	buf := make([]byte, 0, blocksz)
+	buf = binary.LittleEndian.AppendUint64(buf, dumpSignature)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(dumpVersion))
+	buf = binary.LittleEndian.AppendUint64(buf, idx.kind)
+	buf = binary.LittleEndian.AppendUint64(buf, idx.conf.K)
+	buf = binary.LittleEndian.AppendUint64(buf, idx.b)
+	buf = binary.LittleEndian.AppendUint64(buf, idx.r)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(idx.conf.Seed))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(idx.docs)))
+	var m int
+	for id, keys := range idx.docs {
+		buf = binary.LittleEndian.AppendUint64(buf, id)
+		for i := 0; i < len(keys); i++ {
+			buf = binary.LittleEndian.AppendUint64(buf, keys[i])
+		}
+		if len(buf) >= blocksz {
+			m, err = w.Write(buf)
+			n += int64(m)
+			if err != nil {
+				return
+			}
+			buf = buf[:0]
+		}
+	}
+	m, err = w.Write(buf)
+	n += int64(m)
+	return
+}
+
+// ReadFrom reads index from r. Dump must be written by index with the same config.
+func (idx *index) ReadFrom(r io.Reader) (n int64, err error) {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return 0, idx.err
+	}
+	var (
+		buf [64]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint64(buf[0:8]) != dumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint64(buf[8:16]) != math.Float64bits(dumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	kind, k, b, r_, seed := binary.LittleEndian.Uint64(buf[16:24]), binary.LittleEndian.Uint64(buf[24:32]),
+		binary.LittleEndian.Uint64(buf[32:40]), binary.LittleEndian.Uint64(buf[40:48]),
+		int64(binary.LittleEndian.Uint64(buf[48:56]))
+	if kind != idx.kind || k != idx.conf.K || b != idx.b || r_ != idx.r || (kind == schemeBitSampling && seed != idx.conf.Seed) {
+		return n, ErrConfigMismatch
+	}
+	count := binary.LittleEndian.Uint64(buf[56:64])
+
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	for i := 0; i < len(idx.bands); i++ {
+		clear(idx.bands[i])
+	}
+	clear(idx.docs)
+	rec := make([]byte, (b+1)*8)
+	for i := uint64(0); i < count; i++ {
+		m, err = io.ReadFull(r, rec)
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		id := binary.LittleEndian.Uint64(rec)
+		keys := make([]uint64, b)
+		for j := uint64(0); j < b; j++ {
+			keys[j] = binary.LittleEndian.Uint64(rec[(j+1)*8:])
+		}
+		idx.insert(id, keys)
+	}
+	return
+}
+
+func (idx *index) init() {
+	c := idx.conf
+	if c.K == 0 {
+		idx.err = lsh.ErrZeroK
+		return
+	}
+	if c.Hasher != nil {
+		if p, ok := c.Hasher.(lsh.Parametric); ok && p.Params().K != c.K {
+			idx.err = ErrSignatureLength
+			return
+		}
+		if p, ok := c.Hasher.(lsh.Positional); idx.kind == schemeBanding && (!ok || !p.Positional()) {
+			idx.err = ErrNotPositional
+			return
+		}
+	}
+	maxB, maxR, maxBR, p := c.K, c.K, c.K, bandProb(bandingProb)
+	if idx.kind == schemeBitSampling {
+		// bands samples bits independently, so total number of bits isn't limited
+		maxB, maxR, maxBR, p = 64, min(c.K, 64), math.MaxUint64, bitSamplingProb(c.K)
+	}
+	if c.Bands == 0 || c.Rows == 0 {
+		if c.Threshold <= 0 || c.Threshold >= 1 {
+			idx.err = ErrInvalidThreshold
+			return
+		}
+		c.Bands, c.Rows = optimalBR(c.Threshold, maxB, maxR, maxBR, p)
+	}
+	if c.Rows > c.K {
+		idx.err = ErrRowsOverflow
+		return
+	}
+	if idx.kind == schemeBanding && c.Bands*c.Rows > c.K {
+		idx.err = ErrBandsOverflow
+		return
+	}
+	idx.b, idx.r = c.Bands, c.Rows
+
+	switch idx.kind {
+	case schemeBitSampling:
+		idx.scheme = newBitSampling(c.K, idx.b, idx.r, c.Seed)
+	default:
+		idx.scheme = newBanding(c.K, idx.b, idx.r)
+	}
+	idx.bands = make([]map[uint64][]uint64, idx.b)
+	for i := uint64(0); i < idx.b; i++ {
+		idx.bands[i] = make(map[uint64][]uint64)
+	}
+	idx.docs = make(map[uint64][]uint64)
+}
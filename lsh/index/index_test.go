@@ -0,0 +1,282 @@
+package index
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh/minhash"
+	"github.com/koykov/pbtk/shingle"
+)
+
+// Generates MinHash-like signature of k values sharing fraction s of values with base signature.
+func testSimilarMinhash(rng *rand.Rand, base []uint64, s float64) []uint64 {
+	sig := make([]uint64, len(base))
+	for i := 0; i < len(base); i++ {
+		if rng.Float64() < s {
+			sig[i] = base[i]
+		} else {
+			sig[i] = rng.Uint64()
+		}
+	}
+	return sig
+}
+
+// Generates SimHash-like 64-bit signature sharing fraction s of bits with base signature.
+func testSimilarSimhash(rng *rand.Rand, base uint64, s float64) []uint64 {
+	sig := base
+	for _, i := range rng.Perm(64)[:int(math.Round((1-s)*64))] {
+		sig ^= 1 << i
+	}
+	return []uint64{sig}
+}
+
+// Generates text of n random words.
+func testText(rng *rand.Rand, n int) []string {
+	words := make([]string, n)
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+		for j := 0; j < 4+rng.Intn(5); j++ {
+			b.WriteByte(byte('a' + rng.Intn(26)))
+		}
+		words[i] = b.String()
+	}
+	return words
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("optimal", func(t *testing.T) {
+		for _, th := range []float64{.5, .7, .9} {
+			b, r := optimalBR(th, 128, 128, 128, bandingProb)
+			if p := candidateProb(bandingProb, th, b, r); p < .3 || p > .7 {
+				t.Errorf("threshold %f: bands %d rows %d gives candidate probability %f at threshold", th, b, r, p)
+			}
+			if b*r > 128 {
+				t.Errorf("threshold %f: bands %d * rows %d overflows K", th, b, r)
+			}
+			b, r = optimalBR(th, 64, 64, math.MaxUint64, bitSamplingProb(64))
+			if p := candidateProb(bitSamplingProb(64), th, b, r); p < .2 || p > .8 {
+				t.Errorf("threshold %f: tables %d bits %d gives candidate probability %f at threshold", th, b, r, p)
+			}
+		}
+	})
+	t.Run("banding", func(t *testing.T) {
+		const k, docs = 128, 1000
+		idx, err := NewBanding(NewConfig(k, .7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testIndex(t, idx, docs, func(rng *rand.Rand) []uint64 {
+			base := make([]uint64, k)
+			for i := 0; i < k; i++ {
+				base[i] = rng.Uint64()
+			}
+			return base
+		}, testSimilarMinhash)
+	})
+	t.Run("bit sampling", func(t *testing.T) {
+		const docs = 1000
+		idx, err := NewBitSampling(NewConfig(64, .9))
+		if err != nil {
+			t.Fatal(err)
+		}
+		testIndex(t, idx, docs, func(rng *rand.Rand) []uint64 {
+			return []uint64{rng.Uint64()}
+		}, func(rng *rand.Rand, base []uint64, s float64) []uint64 {
+			return testSimilarSimhash(rng, base[0], s)
+		})
+	})
+	t.Run("signature length", func(t *testing.T) {
+		idx, _ := NewBanding(NewConfig(128, .7))
+		if err := idx.Insert(1, make([]uint64, 64)); err != ErrSignatureLength {
+			t.Errorf("expected %v, got %v", ErrSignatureLength, err)
+		}
+	})
+	t.Run("hasher", func(t *testing.T) {
+		conf := minhash.NewConfig[string](xxhash.Hasher64[[]byte]{}, 128, shingle.NewChar[string](3, ""))
+		h, _ := minhash.NewHasher[string](conf)
+		if _, err := NewBanding(NewConfig(128, .7).WithHasher(h)); err != ErrNotPositional {
+			t.Errorf("expected %v, got %v", ErrNotPositional, err)
+		}
+		h, _ = minhash.NewHasher[string](conf.WithMode(minhash.ModeOnePermutation))
+		if _, err := NewBanding(NewConfig(64, .7).WithHasher(h)); err != ErrSignatureLength {
+			t.Errorf("expected %v, got %v", ErrSignatureLength, err)
+		}
+		if _, err := NewBanding(NewConfig(128, .7).WithHasher(h)); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("minhash", func(t *testing.T) {
+		const k, docs = 128, 500
+		h, _ := minhash.NewHasher[string](minhash.NewConfig[string](xxhash.Hasher64[[]byte]{}, k,
+			shingle.NewChar[string](3, "")).WithMode(minhash.ModeOnePermutation))
+		idx, err := NewBanding(NewConfig(k, .7).WithHasher(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash := func(words []string) []uint64 {
+			h.Reset()
+			_ = h.Add(strings.Join(words, " "))
+			return h.Hash()
+		}
+		rng := rand.New(rand.NewSource(1))
+		texts := make([][]string, docs)
+		for i := 0; i < docs; i++ {
+			texts[i] = testText(rng, 100)
+			if err = idx.Insert(uint64(i), hash(texts[i])); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var found, falsePositive int
+		var buf []uint64
+		for i := 0; i < docs; i++ {
+			// near duplicate: two words replaced
+			dup := slices.Clone(texts[i])
+			copy(dup[rng.Intn(50):], testText(rng, 1))
+			copy(dup[50+rng.Intn(50):], testText(rng, 1))
+			if buf = idx.AppendQuery(buf[:0], hash(dup)); slices.Contains(buf, uint64(i)) {
+				found++
+			}
+			if buf = idx.AppendQuery(buf[:0], hash(testText(rng, 100))); len(buf) > 0 {
+				falsePositive++
+			}
+		}
+		if ratio := float64(found) / docs; ratio < .95 {
+			t.Errorf("too low recall of near duplicates: %f", ratio)
+		}
+		if ratio := float64(falsePositive) / docs; ratio > .05 {
+			t.Errorf("too many false positive candidates: %f", ratio)
+		}
+	})
+	t.Run("config", func(t *testing.T) {
+		if _, err := NewBanding(NewConfig(128, 0)); err != ErrInvalidThreshold {
+			t.Errorf("expected %v, got %v", ErrInvalidThreshold, err)
+		}
+		if _, err := NewBanding(NewConfig(128, 0).WithBands(20, 10)); err != ErrBandsOverflow {
+			t.Errorf("expected %v, got %v", ErrBandsOverflow, err)
+		}
+		idx, err := NewBanding(NewConfig(128, 0).WithBands(16, 8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, r := idx.Bands(); b != 16 || r != 8 {
+			t.Errorf("expected bands 16x8, got %dx%d", b, r)
+		}
+	})
+}
+
+func testIndex(t *testing.T, idx Index, docs int, gen func(*rand.Rand) []uint64,
+	similar func(*rand.Rand, []uint64, float64) []uint64) {
+	rng := rand.New(rand.NewSource(1))
+	base := make([][]uint64, docs)
+	for i := 0; i < docs; i++ {
+		base[i] = gen(rng)
+		if err := idx.Insert(uint64(i), base[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if idx.Len() != uint64(docs) {
+		t.Fatalf("expected %d documents, got %d", docs, idx.Len())
+	}
+
+	var found, falsePositive int
+	var buf []uint64
+	for i := 0; i < docs; i++ {
+		buf = idx.AppendQuery(buf[:0], similar(rng, base[i], .95))
+		if slices.Contains(buf, uint64(i)) {
+			found++
+		}
+		buf = idx.AppendQuery(buf[:0], similar(rng, base[i], .3))
+		if slices.Contains(buf, uint64(i)) {
+			falsePositive++
+		}
+	}
+	if ratio := float64(found) / float64(docs); ratio < .95 {
+		t.Errorf("too low recall of near duplicates: %f", ratio)
+	}
+	if ratio := float64(falsePositive) / float64(docs); ratio > .05 {
+		t.Errorf("too many false positive candidates: %f", ratio)
+	}
+
+	t.Run("query", func(t *testing.T) {
+		c := idx.Query(base[0])
+		if !slices.Contains(c, 0) {
+			t.Error("exact signature not found")
+		}
+		if !slices.IsSorted(c) || len(slices.Compact(slices.Clone(c))) != len(c) {
+			t.Error("candidates must be sorted and unique")
+		}
+	})
+	t.Run("dump", func(t *testing.T) {
+		var buf bytes.Buffer
+		n, err := idx.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := idx.Bands()
+		if expect := int64(64 + uint64(docs)*(b+1)*8); n != expect {
+			t.Errorf("expected %d bytes, got %d", expect, n)
+		}
+		expect := idx.Query(base[1])
+		idx.Reset()
+		if idx.Len() != 0 || len(idx.Query(base[1])) != 0 {
+			t.Fatal("index must be empty after reset")
+		}
+		if _, err = idx.ReadFrom(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if idx.Len() != uint64(docs) {
+			t.Errorf("expected %d documents, got %d", docs, idx.Len())
+		}
+		if actual := idx.Query(base[1]); !slices.Equal(expect, actual) {
+			t.Errorf("query mismatch after read: expected %v, got %v", expect, actual)
+		}
+	})
+	t.Run("remove", func(t *testing.T) {
+		if !idx.Remove(0) {
+			t.Fatal("document not removed")
+		}
+		if idx.Remove(0) {
+			t.Fatal("document removed twice")
+		}
+		if slices.Contains(idx.Query(base[0]), 0) {
+			t.Error("removed document found")
+		}
+		if idx.Len() != uint64(docs-1) {
+			t.Errorf("expected %d documents, got %d", docs-1, idx.Len())
+		}
+		// reinsert replaces the document
+		_ = idx.Insert(1, base[2])
+		if slices.Contains(idx.Query(base[1]), 1) {
+			t.Error("replaced document found by old signature")
+		}
+		if !slices.Contains(idx.Query(base[2]), 1) {
+			t.Error("replaced document not found by new signature")
+		}
+	})
+}
+
+func BenchmarkIndex(b *testing.B) {
+	const k, docs = 128, 1e5
+	idx, _ := NewBanding(NewConfig(k, .7))
+	rng := rand.New(rand.NewSource(1))
+	sig := make([]uint64, k)
+	for i := 0; i < docs; i++ {
+		for j := 0; j < k; j++ {
+			sig[j] = rng.Uint64()
+		}
+		_ = idx.Insert(uint64(i), sig)
+	}
+	b.Run("query", func(b *testing.B) {
+		var buf []uint64
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf = idx.AppendQuery(buf[:0], sig)
+		}
+	})
+}
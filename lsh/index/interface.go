@@ -0,0 +1,23 @@
+package index
+
+import "io"
+
+// Index describes LSH index to search candidates of similar documents by their signatures.
+type Index interface {
+	io.ReaderFrom
+	io.WriterTo
+	// Insert adds document signature to the index. Existing document with the same id replaces.
+	Insert(id uint64, signature []uint64) error
+	// Remove removes document from the index.
+	Remove(id uint64) bool
+	// Query returns ids of candidate documents similar to given signature.
+	Query(signature []uint64) []uint64
+	// AppendQuery appends ids of candidate documents similar to given signature to dst.
+	AppendQuery(dst []uint64, signature []uint64) []uint64
+	// Bands returns number of bands and rows per band.
+	Bands() (bands, rows uint64)
+	// Len returns number of documents in the index.
+	Len() uint64
+	// Reset flushes the index.
+	Reset()
+}
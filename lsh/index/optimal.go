@@ -0,0 +1,67 @@
+package index
+
+import "math"
+
+// Calculates optimal bands (b) and rows (r) for given similarity threshold (t).
+// Probability of document with similarity s to become a candidate is 1-(1-p(s))^b, where p is probability of band match.
+// Optimal pair minimizes sum of false positive (area under the curve below t) and false negative (area above the
+// curve after t) probabilities.
+// Parameters are limited by maxB, maxR and product of b*r by maxBR.
+func optimalBR(t float64, maxB, maxR, maxBR uint64, p bandProb) (b, r uint64) {
+	mnerr := math.Inf(1)
+	for b_ := uint64(1); b_ <= maxB; b_++ {
+		for r_ := uint64(1); r_ <= maxR && b_*r_ <= maxBR; r_++ {
+			fp := integrate(func(s float64) float64 { return candidateProb(p, s, b_, r_) }, 0, t)
+			fn := integrate(func(s float64) float64 { return 1 - candidateProb(p, s, b_, r_) }, t, 1)
+			if err := fp + fn; err < mnerr {
+				mnerr, b, r = err, b_, r_
+			}
+		}
+	}
+	return
+}
+
+// Probability of band of r rows match for documents with similarity s.
+type bandProb func(s float64, r uint64) float64
+
+// Probability of document with similarity s to become a candidate.
+func candidateProb(p bandProb, s float64, b, r uint64) float64 {
+	return 1 - math.Pow(1-p(s, r), float64(b))
+}
+
+// Band of banding index matches if all r independent hash values match.
+func bandingProb(s float64, r uint64) float64 {
+	return math.Pow(s, float64(r))
+}
+
+// Band of bit sampling index matches if all r distinct random bits of k-bit signature match. For signatures with
+// similarity s (Hamming distance d=(1-s)k) probability is C(k-d,r)/C(k,r).
+// Unlike banding index, bands share bits of the signature, but they are independent given the distance d.
+func bitSamplingProb(k uint64) bandProb {
+	return func(s float64, r uint64) float64 {
+		k_, r_ := float64(k), float64(r)
+		e := k_ * s // number of equal bits
+		if e < r_ {
+			return 0
+		}
+		a, _ := math.Lgamma(e + 1)
+		b, _ := math.Lgamma(e - r_ + 1)
+		c, _ := math.Lgamma(k_ + 1)
+		d, _ := math.Lgamma(k_ - r_ + 1)
+		return math.Exp(a - b - c + d)
+	}
+}
+
+// Simple trapezoidal integration.
+func integrate(fn func(float64) float64, lo, hi float64) (r float64) {
+	const steps = 64
+	if hi <= lo {
+		return
+	}
+	step := (hi - lo) / steps
+	r = (fn(lo) + fn(hi)) / 2
+	for i := 1; i < steps; i++ {
+		r += fn(lo + float64(i)*step)
+	}
+	return r * step
+}
@@ -0,0 +1,84 @@
+package index
+
+import (
+	"math/rand"
+
+	"github.com/koykov/pbtk"
+)
+
+const (
+	schemeBanding = iota + 1
+	schemeBitSampling
+)
+
+// Scheme converts signature to the keys of the bands.
+// Implemented as concrete type (not interface) to keep keys buffer on the stack.
+type scheme struct {
+	kind    uint64
+	k, b, r uint64
+	pos     []uint64 // b*r bit positions of bit sampling scheme
+}
+
+func (s *scheme) appendKeys(dst []uint64, signature []uint64) ([]uint64, error) {
+	if s.kind == schemeBitSampling {
+		return s.appendBitSamplingKeys(dst, signature)
+	}
+	return s.appendBandingKeys(dst, signature)
+}
+
+// Banding scheme splits signature of K hash values to b bands of r values.
+func (s *scheme) appendBandingKeys(dst []uint64, signature []uint64) ([]uint64, error) {
+	if uint64(len(signature)) != s.k {
+		return dst, ErrSignatureLength
+	}
+	for i := uint64(0); i < s.b; i++ {
+		var key uint64
+		for _, v := range signature[i*s.r : (i+1)*s.r] {
+			key = pbtk.Fmix64(key ^ v)
+		}
+		dst = append(dst, key)
+	}
+	return dst, nil
+}
+
+func newBanding(k, b, r uint64) *scheme {
+	return &scheme{kind: schemeBanding, k: k, b: b, r: r}
+}
+
+func newBitSampling(k, b, r uint64, seed int64) *scheme {
+	s := &scheme{kind: schemeBitSampling, k: k, b: b, r: r, pos: make([]uint64, 0, b*r)}
+	rng := rand.New(rand.NewSource(seed))
+	perm := make([]uint64, k)
+	for i := uint64(0); i < b; i++ {
+		for j := uint64(0); j < k; j++ {
+			perm[j] = j
+		}
+		// partial Fisher-Yates shuffle to select r distinct positions
+		for j := uint64(0); j < r; j++ {
+			x := j + uint64(rng.Int63n(int64(k-j)))
+			perm[j], perm[x] = perm[x], perm[j]
+		}
+		s.pos = append(s.pos, perm[:r]...)
+	}
+	return s
+}
+
+// Bit sampling scheme builds b keys of r random bits of K-bit signature.
+func (s *scheme) appendBitSamplingKeys(dst []uint64, signature []uint64) ([]uint64, error) {
+	if uint64(len(signature))*64 < s.k {
+		return dst, ErrSignatureLength
+	}
+	for i := uint64(0); i < s.b; i++ {
+		var key uint64
+		for j, p := range s.pos[i*s.r : (i+1)*s.r] {
+			bit := signature[p/64] >> (p % 64) & 1
+			if s.r <= 64 {
+				key |= bit << j
+			} else {
+				key = pbtk.Fmix64(key ^ bit<<(j%64))
+			}
+		}
+		dst = append(dst, key)
+	}
+	return dst, nil
+}
@@ -2,9 +2,28 @@ package lsh
 
 import "github.com/koykov/pbtk"
 
+// Positional describes hasher which signature values are positional, i.e. i-th values of two signatures are comparable.
+// Similarity of positional signatures estimates as fraction of equal values at the same positions.
+type Positional interface {
+	Positional() bool
+}
+
+// Bitwise describes hasher which signature is a binary fingerprint (possibly multi-word), i.e. similarity of two
+// signatures depends on Hamming distance between them.
+type Bitwise interface {
+	Bitwise() bool
+}
+
 type Hasher[T pbtk.Hashable] interface {
 	Add(value T) error
 	Hash() []uint64
 	AppendHash([]uint64) []uint64
 	Reset()
 }
+
+// WeightedHasher describes hasher that takes into account weights of shingles.
+type WeightedHasher[T pbtk.Hashable] interface {
+	Hasher[T]
+	// AddWeighted adds value with given weight.
+	AddWeighted(value T, weight float64) error
+}
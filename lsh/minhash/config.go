@@ -6,16 +6,41 @@ import (
 	"github.com/koykov/pbtk/shingle"
 )
 
+// Mode of signature calculation.
+type Mode uint8
+
+const (
+	// ModeKHashes calculates K hash sums of each shingle.
+	ModeKHashes Mode = iota
+	// ModeOnePermutation calculates single hash sum of each shingle, splits hash space to K bins and keeps minimal
+	// hash sum of each bin. Empty bins fill using optimal densification. Signature contains exactly K values.
+	ModeOnePermutation
+)
+
 type Config[T byteseq.Q] struct {
 	// Hash algorithm to use.
-	// Mandatory param.
+	// Mandatory param (except of pbtk.HashStrategyDouble128 strategy).
 	Algo pbtk.Hasher
+	// 128-bit hash algorithm to use.
+	// Mandatory param for pbtk.HashStrategyDouble128 strategy.
+	Algo128 pbtk.Hasher128
+	// Strategy of K hash sums calculation.
+	// If this param omitted, pbtk.HashStrategySalt will use (K salted hash sums of each token).
+	HashStrategy pbtk.HashStrategy
 	// Number of hash functions.
 	// Mandatory param.
 	K uint64
 	// Shingler to vector input data.
+	// If shingler implements shingle.HashShingler, its hash sums use instead of hashing of each shingle by Algo.
 	// Mandatory param.
 	Shingler shingle.Shingler[T]
+	// Signature calculation mode.
+	// If this param omitted, ModeKHashes will use.
+	Mode Mode
+	// Streaming mode: Add takes chunks of the single document (see lsh.AddReader), shingles spanning chunks
+	// boundaries handle as if the document was solid. Signature accumulates until Hash/AppendHash call, that finishes
+	// the document, so the next Add starts a new one. Shingler must implement shingle.StreamShingler.
+	Stream bool
 	// Values storage.
 	// If this param omitted, the instance of DefaultVector will be used.
 	Vector Vector
@@ -29,6 +54,26 @@ func NewConfig[T byteseq.Q](algo pbtk.Hasher, k uint64, shingler shingle.Shingle
 	}
 }
 
+func (c *Config[T]) WithAlgo128(algo pbtk.Hasher128) *Config[T] {
+	c.Algo128 = algo
+	return c
+}
+
+func (c *Config[T]) WithHashStrategy(strategy pbtk.HashStrategy) *Config[T] {
+	c.HashStrategy = strategy
+	return c
+}
+
+func (c *Config[T]) WithMode(mode Mode) *Config[T] {
+	c.Mode = mode
+	return c
+}
+
+func (c *Config[T]) WithStream() *Config[T] {
+	c.Stream = true
+	return c
+}
+
 func (c *Config[T]) copy() *Config[T] {
 	cpy := *c
 	return &cpy
@@ -2,20 +2,28 @@ package minhash
 
 import (
 	"math"
+	"math/bits"
 	"strconv"
 	"sync"
 
 	"github.com/koykov/byteseq"
 	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/shingle"
 )
 
 type hash[T byteseq.Q] struct {
-	b     pbtk.Base[T]
-	conf  *Config[T]
-	token []T
-	buf   []byte
-	once  sync.Once
+	conf   *Config[T]
+	token  []T
+	hsum   []uint64 // hash sums of shingles (hash shingler)
+	hs     shingle.HashShingler[T]
+	ss     shingle.StreamShingler[T]
+	open   bool // document is streaming now
+	filled bool // at least one shingle of streaming document was added
+	buf    []byte
+	bins   []uint64       // bitset of filled bins (one permutation mode)
+	full   *DefaultVector // full minimums of bins, packs to BitsVector on hash (one permutation mode)
+	once   sync.Once
 
 	err error
 }
@@ -32,20 +40,164 @@ func (h *hash[T]) Add(value T) error {
 	if h.once.Do(h.init); h.err != nil {
 		return h.err
 	}
-	h.token = h.conf.Shingler.AppendShingle(h.token, value)
+	if h.conf.Stream {
+		h.addChunk(value)
+		return nil
+	}
+	if h.hs != nil {
+		h.hsum = h.hs.AppendHashes(h.hsum, value)
+	} else {
+		h.token = h.conf.Shingler.AppendShingle(h.token, value)
+	}
+	if h.conf.Mode == ModeOnePermutation {
+		h.addOPH()
+		return nil
+	}
+	if h.hs != nil {
+		n := uint64(len(h.hsum))
+		h.vec().Grow(n)
+		h.vec().Memset(math.MaxUint64)
+		for i := uint64(0); i < n; i++ {
+			h.setMinsHashed(i, h.hsum[i])
+		}
+		return nil
+	}
 	n := uint64(len(h.token))
 
 	h.vec().Grow(n)
 	h.vec().Memset(math.MaxUint64)
 	for i := uint64(0); i < n; i++ {
+		h.setMins(i, h.token[i])
+	}
+	return nil
+}
+
+// setMins sets minimal of K hash sums of the shingle at given position.
+func (h *hash[T]) setMins(pos uint64, shingle T) {
+	if h.conf.HashStrategy != pbtk.HashStrategySalt {
+		h.buf = append(h.buf[:0], shingle...)
+		dh := h.dh(h.buf)
 		for j := uint64(0); j < h.conf.K; j++ {
-			h.buf = append(h.buf[:0], h.token[i]...)
-			h.buf = strconv.AppendUint(h.buf, j, 10)
-			hsum := h.conf.Algo.Sum64(h.buf)
-			h.vec().SetMin(i, hsum)
+			h.vec().SetMin(pos, dh.Next())
 		}
+		return
 	}
-	return nil
+	for j := uint64(0); j < h.conf.K; j++ {
+		h.buf = append(h.buf[:0], shingle...)
+		h.buf = strconv.AppendUint(h.buf, j, 10)
+		hsum := h.conf.Algo.Sum64(h.buf)
+		h.vec().SetMin(pos, hsum)
+	}
+}
+
+// Same as above, but K hash sums derive from hash sum of hash shingler: salt mixes into the hash sum instead of
+// rehashing of shingle.
+func (h *hash[T]) setMinsHashed(pos, hsum uint64) {
+	if h.conf.HashStrategy != pbtk.HashStrategySalt {
+		dh := pbtk.NewDoubleHash(hsum)
+		for j := uint64(0); j < h.conf.K; j++ {
+			h.vec().SetMin(pos, dh.Next())
+		}
+		return
+	}
+	for j := uint64(0); j < h.conf.K; j++ {
+		h.vec().SetMin(pos, pbtk.Fmix64(hsum+j*0x9e3779b97f4a7c15))
+	}
+}
+
+// One permutation hashing: single hash sum of each shingle, K bins and optimal densification of empty bins.
+// See https://proceedings.mlr.press/v70/shrivastava17a.html for details.
+func (h *hash[T]) addOPH() {
+	h.vec().Grow(h.conf.K)
+	h.vec().Memset(math.MaxUint64)
+	h.bins = growBins(h.bins, h.conf.K)
+	n := len(h.token)
+	if h.hs != nil {
+		n = len(h.hsum)
+	}
+	for i := 0; i < n; i++ {
+		if h.hs != nil {
+			h.fillBin(h.hsum[i])
+		} else {
+			h.fillBin(h.shingleSum(h.token[i]))
+		}
+	}
+	if n > 0 {
+		h.densify()
+	}
+}
+
+// fillBin puts hash sum to its bin.
+func (h *hash[T]) fillBin(hsum uint64) {
+	bin, _ := bits.Mul64(hsum, h.conf.K) // fast range reduction to [0..K)
+	h.vec().SetMin(bin, hsum)
+	h.bins[bin/64] |= 1 << (bin % 64)
+}
+
+// Each empty bin borrows value of the first filled bin in its own pseudorandom probe sequence. Sequences depend only
+// on bin index and attempt number, so similar documents borrow values from the same bins.
+func (h *hash[T]) densify() {
+	k := h.conf.K
+	for i := uint64(0); i < k; i++ {
+		if h.bins[i/64]&(1<<(i%64)) != 0 {
+			continue
+		}
+		for attempt := uint64(1); ; attempt++ {
+			j, _ := bits.Mul64(pbtk.Fmix64(i<<32|attempt), k)
+			if h.bins[j/64]&(1<<(j%64)) != 0 {
+				h.vec().SetMin(i, h.vec().Get(j))
+				break
+			}
+		}
+	}
+}
+
+// shingleSum returns single hash sum of the shingle.
+func (h *hash[T]) shingleSum(shingle T) uint64 {
+	if h.hs != nil {
+		return h.hs.Sum64(shingle)
+	}
+	h.buf = append(h.buf[:0], shingle...)
+	return h.sum(h.buf)
+}
+
+func (h *hash[T]) sum(p []byte) uint64 {
+	if h.conf.HashStrategy == pbtk.HashStrategyDouble128 {
+		return h.conf.Algo128.Sum128(p)[0]
+	}
+	return h.conf.Algo.Sum64(p)
+}
+
+func growBins(bins []uint64, k uint64) []uint64 {
+	n := int(k+63) / 64
+	if cap(bins) < n {
+		return make([]uint64, n)
+	}
+	bins = bins[:n]
+	clear(bins)
+	return bins
+}
+
+// Positional returns true if signature values are positional, i.e. values of two signatures should be compared
+// pairwise by index.
+func (h *hash[T]) Positional() bool {
+	return h.conf.Mode == ModeOnePermutation
+}
+
+// Params returns params of signatures. Kind is lsh.KindBBitMinHash if vector implements BitsVector.
+func (h *hash[T]) Params() lsh.Params {
+	p := lsh.Params{Kind: lsh.KindMinHash, K: h.conf.K, B: 64, Positional: h.Positional()}
+	if bv, ok := h.conf.Vector.(BitsVector); ok {
+		p.Kind, p.B = lsh.KindBBitMinHash, bv.Bits()
+	}
+	return p
+}
+
+func (h *hash[T]) dh(p []byte) pbtk.DoubleHash {
+	if h.conf.HashStrategy == pbtk.HashStrategyDouble128 {
+		return pbtk.NewDoubleHash128(h.conf.Algo128.Sum128(p))
+	}
+	return pbtk.NewDoubleHash(h.conf.Algo.Sum64(p))
 }
 
 func (h *hash[T]) Hash() []uint64 {
@@ -54,22 +206,41 @@ func (h *hash[T]) Hash() []uint64 {
 }
 
 func (h *hash[T]) AppendHash(dst []uint64) []uint64 {
-	return h.vec().AppendAll(dst)
+	if h.conf.Stream {
+		h.flush()
+	}
+	if h.full != nil {
+		h.pack()
+	}
+	return h.conf.Vector.AppendAll(dst)
+}
+
+// pack puts lower bits of full minimums to BitsVector. Bins must keep full minimums until the end of the document,
+// since minimum of lower bits isn't lower bits of minimum.
+func (h *hash[T]) pack() {
+	h.conf.Vector.Reset()
+	for i := 0; i < len(*h.full); i++ {
+		h.conf.Vector.Add((*h.full)[i])
+	}
 }
 
 func (h *hash[T]) Reset() {
 	h.conf.Shingler.Reset()
+	h.open = false
 	if h.vec().Len() == 0 {
 		return
 	}
 	h.token = h.token[:0]
+	h.hsum = h.hsum[:0]
 	h.buf = h.buf[:0]
 	h.vec().Reset()
+	if h.full != nil {
+		h.conf.Vector.Reset()
+	}
 }
 
 func (h *hash[T]) init() {
-	if h.conf.Algo == nil {
-		h.err = pbtk.ErrNoHasher
+	if h.err = h.conf.HashStrategy.Check(h.conf.Algo, h.conf.Algo128); h.err != nil {
 		return
 	}
 	if h.conf.K == 0 {
@@ -80,11 +251,29 @@ func (h *hash[T]) init() {
 		h.err = lsh.ErrNoShingler
 		return
 	}
+	h.hs, _ = h.conf.Shingler.(shingle.HashShingler[T])
+	if h.conf.Stream {
+		var ok bool
+		if h.ss, ok = h.conf.Shingler.(shingle.StreamShingler[T]); !ok {
+			h.err = lsh.ErrStreamUnsupported
+			return
+		}
+	}
+	if h.conf.Mode > ModeOnePermutation {
+		h.err = lsh.ErrUnknownMode
+		return
+	}
 	if h.conf.Vector == nil {
 		h.conf.Vector = &DefaultVector{}
 	}
+	if _, ok := h.conf.Vector.(BitsVector); ok && h.conf.Mode == ModeOnePermutation {
+		h.full = &DefaultVector{}
+	}
 }
 
 func (h *hash[T]) vec() Vector {
+	if h.full != nil {
+		return h.full
+	}
 	return h.conf.Vector
 }
@@ -1,17 +1,21 @@
 package minhash
 
 import (
+	"math"
+	"strconv"
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/shingle"
 )
 
 var (
 	testh   = xxhash.Hasher64[[]byte]{}
-	testshc = shingle.NewChar[[]byte](3, "") // 3-gram
-	testshw = shingle.NewWord[[]byte](2, "") // 2-word shingle
+	testshc = shingle.NewChar[[]byte](3, "")        // 3-gram
+	testshw = shingle.NewWord[[]byte](2, "")        // 2-word shingle
+	testshr = shingle.NewRollingChar[[]byte](3, "") // 3-gram with rolling hash sums
 	testk   = uint64(50)
 )
 
@@ -26,6 +30,109 @@ func TestHash(t *testing.T) {
 		_ = err
 		lsh.TestMe(t, h, lsh.TestDistJaccard, testk, 1.0)
 	})
+	t.Run("double", func(t *testing.T) {
+		h, err := NewHasher[[]byte](NewConfig(testh, testk, testshc).
+			WithHashStrategy(pbtk.HashStrategyDouble))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestMe(t, h, lsh.TestDistJaccard, testk, 1.0)
+	})
+	t.Run("double128", func(t *testing.T) {
+		h, err := NewHasher[[]byte](NewConfig(nil, testk, testshc).
+			WithAlgo128(xxhash.Hasher128[[]byte]{}).
+			WithHashStrategy(pbtk.HashStrategyDouble128))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestMe(t, h, lsh.TestDistJaccard, testk, 1.0)
+	})
+	t.Run("one permutation", func(t *testing.T) {
+		h, err := NewHasher[[]byte](NewConfig(testh, 256, testshw).WithMode(ModeOnePermutation))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestMe(t, h, lsh.TestDistJaccard, 256, 1.0)
+
+		// 100 common words of 125 total, so J(a,b) = 0.8 for 1-word shingles
+		var a, b []byte
+		for i := 0; i < 125; i++ {
+			if i < 100 || i%2 == 0 {
+				a = strconv.AppendInt(append(a, 'w'), int64(i), 10)
+				a = append(a, ' ')
+			}
+			if i < 100 || i%2 == 1 {
+				b = strconv.AppendInt(append(b, 'w'), int64(i), 10)
+				b = append(b, ' ')
+			}
+		}
+		h, _ = NewHasher[[]byte](NewConfig(testh, 256, shingle.NewWord[[]byte](1, "")).WithMode(ModeOnePermutation))
+		_ = h.Add(a)
+		ha := h.AppendHash(nil)
+		h.Reset()
+		_ = h.Add(b)
+		hb := h.AppendHash(nil)
+		if len(ha) != 256 || len(hb) != 256 {
+			t.Fatalf("expected signatures of length %d, got %d and %d", 256, len(ha), len(hb))
+		}
+		if j := lsh.TestDistJaccard(ha, hb, 256); math.Abs(j-100./125) > .1 {
+			t.Errorf("jaccard estimation too inaccurate: expected %f, got %f", 100./125, j)
+		}
+		if p, ok := h.(lsh.Positional); !ok || !p.Positional() {
+			t.Error("expected positional hasher")
+		}
+	})
+	t.Run("rolling", func(t *testing.T) {
+		for _, c := range []*Config[[]byte]{
+			NewConfig(testh, testk, testshr),
+			NewConfig(testh, testk, testshr).WithHashStrategy(pbtk.HashStrategyDouble),
+			NewConfig(testh, 256, testshr).WithMode(ModeOnePermutation),
+		} {
+			h, err := NewHasher[[]byte](c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lsh.TestMe(t, h, lsh.TestDistJaccard, c.K, 1.0)
+		}
+	})
+	t.Run("stream", func(t *testing.T) {
+		// solid and stream hashers need own shinglers
+		char := func() shingle.Shingler[[]byte] { return shingle.NewChar[[]byte](3, "") }
+		word := func() shingle.Shingler[[]byte] { return shingle.NewWord[[]byte](2, "") }
+		rolling := func() shingle.Shingler[[]byte] { return shingle.NewRollingChar[[]byte](3, "") }
+		for _, fn := range []func(shingle.Shingler[[]byte]) *Config[[]byte]{
+			func(sh shingle.Shingler[[]byte]) *Config[[]byte] { return NewConfig(testh, testk, sh) },
+			func(sh shingle.Shingler[[]byte]) *Config[[]byte] {
+				return NewConfig(testh, 256, sh).WithMode(ModeOnePermutation)
+			},
+			func(sh shingle.Shingler[[]byte]) *Config[[]byte] {
+				return NewConfig(testh, testk, sh).WithHashStrategy(pbtk.HashStrategyDouble)
+			},
+		} {
+			for _, newsh := range []func() shingle.Shingler[[]byte]{char, word, rolling} {
+				solid, _ := NewHasher[[]byte](fn(newsh()))
+				stream, err := NewHasher[[]byte](fn(newsh()).WithStream())
+				if err != nil {
+					t.Fatal(err)
+				}
+				lsh.TestStreamMe(t, solid, stream)
+			}
+		}
+		if _, err := NewHasher[[]byte](NewConfig(testh, testk, shingle.NewNOP[[]byte]()).WithStream()); err != lsh.ErrStreamUnsupported {
+			t.Errorf("expected stream unsupported error, got %v", err)
+		}
+	})
+	t.Run("signature", func(t *testing.T) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshc))
+		lsh.TestSignatureMe(t, h, lsh.Params{Kind: lsh.KindMinHash, K: testk, B: 64})
+		h, _ = NewHasher[[]byte](NewConfig(testh, 256, testshw).WithMode(ModeOnePermutation))
+		lsh.TestSignatureMe(t, h, lsh.Params{Kind: lsh.KindMinHash, K: 256, B: 64, Positional: true})
+	})
+	t.Run("unknown mode", func(t *testing.T) {
+		if _, err := NewHasher[[]byte](NewConfig(testh, testk, testshc).WithMode(2)); err != lsh.ErrUnknownMode {
+			t.Errorf("expected unknown mode error, got %v", err)
+		}
+	})
 }
 
 func BenchmarkHash(b *testing.B) {
@@ -39,4 +146,17 @@ func BenchmarkHash(b *testing.B) {
 		_ = err
 		lsh.BenchMe(b, h)
 	})
+	b.Run("double", func(b *testing.B) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshc).
+			WithHashStrategy(pbtk.HashStrategyDouble))
+		lsh.BenchMe(b, h)
+	})
+	b.Run("one permutation", func(b *testing.B) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshc).WithMode(ModeOnePermutation))
+		lsh.BenchMe(b, h)
+	})
+	b.Run("rolling", func(b *testing.B) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshr).WithHashStrategy(pbtk.HashStrategyDouble))
+		lsh.BenchMe(b, h)
+	})
 }
@@ -0,0 +1,57 @@
+package minhash
+
+import "math"
+
+// addChunk adds shingles completed by the next chunk of the document (streaming mode).
+func (h *hash[T]) addChunk(value T) {
+	if !h.open {
+		h.startStream()
+	}
+	h.token = h.ss.AppendChunk(h.token[:0], value)
+	h.addTokens()
+}
+
+// flush adds the rest of shingles and finishes the document (streaming mode).
+func (h *hash[T]) flush() {
+	if !h.open {
+		return
+	}
+	h.token = h.ss.Flush(h.token[:0])
+	h.addTokens()
+	h.open = false
+	if h.conf.Mode == ModeOnePermutation && h.filled {
+		h.densify()
+	}
+}
+
+func (h *hash[T]) startStream() {
+	h.open, h.filled = true, false
+	if h.conf.Mode == ModeOnePermutation {
+		h.vec().Grow(h.conf.K)
+		h.vec().Memset(math.MaxUint64)
+		h.bins = growBins(h.bins, h.conf.K)
+		return
+	}
+	// vector grows by one value per shingle
+	if h.vec().Len() > 0 {
+		h.vec().Reset()
+	}
+	h.vec().Grow(0)
+}
+
+func (h *hash[T]) addTokens() {
+	h.filled = h.filled || len(h.token) > 0
+	for i := 0; i < len(h.token); i++ {
+		if h.conf.Mode == ModeOnePermutation {
+			h.fillBin(h.shingleSum(h.token[i]))
+			continue
+		}
+		pos := h.vec().Len()
+		h.vec().Add(math.MaxUint64)
+		if h.hs != nil {
+			h.setMinsHashed(pos, h.hs.Sum64(h.token[i]))
+		} else {
+			h.setMins(pos, h.token[i])
+		}
+	}
+}
@@ -26,6 +26,13 @@ type Vector interface {
 	Reset()
 }
 
+// BitsVector describes vector that stores only lower bits of values (see bbitminhash).
+type BitsVector interface {
+	Vector
+	// Bits returns number of stored lower bits of each value.
+	Bits() uint64
+}
+
 // DefaultVector represents list of uint64 values.
 type DefaultVector []uint64
 
@@ -0,0 +1,197 @@
+package lsh
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/koykov/pbtk"
+)
+
+const (
+	signatureDumpSignature = 0x6e0d3a5f92c4b71e
+	signatureDumpVersion   = 1.0
+	signatureHeaderSize    = 56
+)
+
+// Kind of hasher produced the signature.
+type Kind uint8
+
+const (
+	KindUnknown Kind = iota
+	KindMinHash
+	KindBBitMinHash
+	KindSimHash
+	KindWeightedMinHash
+)
+
+var kindNames = [...]string{"unknown", "minhash", "bbitminhash", "simhash", "wminhash"}
+
+func (k Kind) String() string {
+	if k >= Kind(len(kindNames)) {
+		return kindNames[KindUnknown]
+	}
+	return kindNames[k]
+}
+
+// Params describes signature produced by hasher. Signatures are comparable only if their params are equal.
+type Params struct {
+	// Kind of hasher.
+	Kind Kind
+	// Number of hash functions (MinHash family) or fingerprint width in bits (SimHash).
+	K uint64
+	// Number of stored lower bits of each value (b-bit MinHash), 64 for the rest of hashers.
+	B uint64
+	// Signature values are positional (see Positional).
+	Positional bool
+}
+
+// Parametric describes hasher that reports params of its signatures.
+type Parametric interface {
+	Params() Params
+}
+
+// Signature is a serializable result of hasher with params of the hasher. Signatures may be stored and compared later
+// using EstimateSignatures/DiffSignatures methods of estimators and differs without rehashing of the source texts.
+type Signature struct {
+	Params
+	Values []uint64
+}
+
+// NewSignature makes signature of the current document of hasher h. Params of the signature takes from h, if it
+// implements Parametric interface.
+func NewSignature[T pbtk.Hashable](h Hasher[T]) *Signature {
+	return AppendSignature(&Signature{}, h)
+}
+
+// AppendSignature overwrites signature s with the current document of hasher h reusing its values buffer.
+func AppendSignature[T pbtk.Hashable](s *Signature, h Hasher[T]) *Signature {
+	s.Params = Params{}
+	if p, ok := h.(Parametric); ok {
+		s.Params = p.Params()
+	}
+	s.Values = h.AppendHash(s.Values[:0])
+	return s
+}
+
+// Compatible checks if signatures s and o are produced by hashers with the same params.
+func (s *Signature) Compatible(o *Signature) error {
+	if s.Params != o.Params {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// CompatibleHasher checks if signatures a and b are produced by hashers with the same params as hasher h. Params of h
+// take into account only if it implements Parametric interface.
+func CompatibleHasher[T pbtk.Hashable](h Hasher[T], a, b *Signature) error {
+	if a == nil || b == nil {
+		return ErrSignatureMismatch
+	}
+	if err := a.Compatible(b); err != nil {
+		return err
+	}
+	if p, ok := h.(Parametric); ok && p.Params() != a.Params {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// Equal checks if signatures s and o have the same params and values.
+func (s *Signature) Equal(o *Signature) bool {
+	return s.Params == o.Params && slices.Equal(s.Values, o.Values)
+}
+
+// MarshalBinary encodes signature to binary form.
+func (s *Signature) MarshalBinary() ([]byte, error) {
+	return s.AppendBinary(make([]byte, 0, signatureHeaderSize+len(s.Values)*8))
+}
+
+// AppendBinary appends binary form of signature to dst.
+func (s *Signature) AppendBinary(dst []byte) ([]byte, error) {
+	var pos uint64
+	if s.Positional {
+		pos = 1
+	}
+	dst = binary.LittleEndian.AppendUint64(dst, signatureDumpSignature)
+	dst = binary.LittleEndian.AppendUint64(dst, math.Float64bits(signatureDumpVersion))
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(s.Kind))
+	dst = binary.LittleEndian.AppendUint64(dst, s.K)
+	dst = binary.LittleEndian.AppendUint64(dst, s.B)
+	dst = binary.LittleEndian.AppendUint64(dst, pos)
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(len(s.Values)))
+	for i := 0; i < len(s.Values); i++ {
+		dst = binary.LittleEndian.AppendUint64(dst, s.Values[i])
+	}
+	return dst, nil
+}
+
+// UnmarshalBinary decodes signature from binary form.
+func (s *Signature) UnmarshalBinary(p []byte) error {
+	if len(p) < signatureHeaderSize {
+		return io.ErrUnexpectedEOF
+	}
+	count, err := s.decodeHeader(p[:signatureHeaderSize])
+	if err != nil {
+		return err
+	}
+	p = p[signatureHeaderSize:]
+	if uint64(len(p)) != count*8 {
+		return io.ErrUnexpectedEOF
+	}
+	s.Values = slices.Grow(s.Values[:0], int(count))
+	for i := uint64(0); i < count; i++ {
+		s.Values = append(s.Values, binary.LittleEndian.Uint64(p[i*8:]))
+	}
+	return nil
+}
+
+// WriteTo writes signature to w.
+func (s *Signature) WriteTo(w io.Writer) (int64, error) {
+	buf, _ := s.MarshalBinary()
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadFrom reads signature from r.
+func (s *Signature) ReadFrom(r io.Reader) (n int64, err error) {
+	var (
+		buf [signatureHeaderSize]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	var count uint64
+	if count, err = s.decodeHeader(buf[:]); err != nil {
+		return
+	}
+	s.Values = s.Values[:0]
+	var val [8]byte
+	for i := uint64(0); i < count; i++ {
+		m, err = io.ReadFull(r, val[:])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		s.Values = append(s.Values, binary.LittleEndian.Uint64(val[:]))
+	}
+	return
+}
+
+func (s *Signature) decodeHeader(p []byte) (uint64, error) {
+	if binary.LittleEndian.Uint64(p[0:8]) != signatureDumpSignature {
+		return 0, pbtk.ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint64(p[8:16]) != math.Float64bits(signatureDumpVersion) {
+		return 0, pbtk.ErrVersionMismatch
+	}
+	s.Kind = Kind(binary.LittleEndian.Uint64(p[16:24]))
+	s.K = binary.LittleEndian.Uint64(p[24:32])
+	s.B = binary.LittleEndian.Uint64(p[32:40])
+	s.Positional = binary.LittleEndian.Uint64(p[40:48]) != 0
+	return binary.LittleEndian.Uint64(p[48:56]), nil
+}
@@ -6,13 +6,31 @@ import (
 	"github.com/koykov/pbtk/shingle"
 )
 
+// Fingerprint widths.
+const (
+	Width64  = 64
+	Width128 = 128
+	Width256 = 256
+)
+
 type Config[T byteseq.Q] struct {
 	// Hash algorithm to use.
-	// Mandatory param.
+	// Mandatory param for Width64.
 	Algo pbtk.Hasher
+	// 128-bit hash algorithm to use.
+	// Mandatory param for Width128 and Width256.
+	Algo128 pbtk.Hasher128
 	// Shingler to vector input data.
+	// If shingler implements shingle.HashShingler, its hash sums use instead of hashing of each shingle by Algo.
 	// Mandatory param.
 	Shingler shingle.Shingler[T]
+	// Fingerprint width in bits. Must be one of Width64, Width128 or Width256.
+	// If this param omitted, Width64 will use.
+	Width uint64
+	// Streaming mode: Add/AddWeighted take chunks of the single document (see lsh.AddReader), shingles spanning chunks
+	// boundaries handle as if the document was solid. Fingerprint accumulates until Hash/AppendHash call, that
+	// finishes the document, so the next Add starts a new one. Shingler must implement shingle.StreamShingler.
+	Stream bool
 }
 
 func NewConfig[T byteseq.Q](algo pbtk.Hasher, shingler shingle.Shingler[T]) *Config[T] {
@@ -22,7 +40,46 @@ func NewConfig[T byteseq.Q](algo pbtk.Hasher, shingler shingle.Shingler[T]) *Con
 	}
 }
 
+func (c *Config[T]) WithAlgo128(algo pbtk.Hasher128) *Config[T] {
+	c.Algo128 = algo
+	return c
+}
+
+func (c *Config[T]) WithWidth(width uint64) *Config[T] {
+	c.Width = width
+	return c
+}
+
+func (c *Config[T]) WithStream() *Config[T] {
+	c.Stream = true
+	return c
+}
+
 func (c *Config[T]) copy() *Config[T] {
 	cpy := *c
 	return &cpy
 }
+
+type IndexConfig struct {
+	// Maximal Hamming distance of queries.
+	// Zero value means search of exact duplicates only.
+	K uint64
+	// Number of blocks to split fingerprint. Index keeps C(Blocks, K) tables, each table guarantees exact match of
+	// Blocks-K blocks. More blocks means longer exact prefix (fewer candidates to check), but more tables (memory).
+	// If this param omitted, K+1 will use.
+	Blocks uint64
+}
+
+func NewIndexConfig(k uint64) *IndexConfig {
+	return &IndexConfig{K: k}
+}
+
+func (c *IndexConfig) WithBlocks(blocks uint64) *IndexConfig {
+	c.Blocks = blocks
+	return c
+}
+
+func (c *IndexConfig) copy() *IndexConfig {
+	cpy := *c
+	return &cpy
+}
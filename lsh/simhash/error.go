@@ -0,0 +1,11 @@
+package simhash
+
+import "errors"
+
+var (
+	ErrInvalidBlocks    = errors.New("blocks must be in range (K..64]")
+	ErrTooManyTables    = errors.New("too many tables, decrease blocks number")
+	ErrDistanceOverflow = errors.New("query distance must be less or equal K")
+	ErrLengthMismatch   = errors.New("ids and fingerprints length mismatch")
+	ErrConfigMismatch   = errors.New("dump config mismatch")
+)
@@ -1,27 +1,35 @@
 package simhash
 
 import (
+	"math"
 	"sync"
 	"unsafe"
 
 	"github.com/koykov/byteseq"
 	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/shingle"
 	"github.com/koykov/simd/memclr64"
 )
 
-const vectorsz = 64
+const vectorsz = Width256
 
 type hash[T byteseq.Q] struct {
 	conf   *Config[T]
-	vector [vectorsz]int64
+	vector [vectorsz]float64
 	token  []T
+	hsum   []uint64 // hash sums of shingles (hash shingler)
+	hs     shingle.HashShingler[T]
+	ss     shingle.StreamShingler[T]
+	open   bool    // document is streaming now
+	weight float64 // weight of the last chunk of streaming document
+	buf    []byte
 	once   sync.Once
 
 	err error
 }
 
-func NewHasher[T byteseq.Q](conf *Config[T]) (lsh.Hasher[T], error) {
+func NewHasher[T byteseq.Q](conf *Config[T]) (lsh.WeightedHasher[T], error) {
 	if conf == nil {
 		return nil, pbtk.ErrInvalidConfig
 	}
@@ -32,55 +40,168 @@ func NewHasher[T byteseq.Q](conf *Config[T]) (lsh.Hasher[T], error) {
 	return h, nil
 }
 
+// Add adds each shingle of value with weight 1.
 func (h *hash[T]) Add(value T) error {
+	return h.AddWeighted(value, 1)
+}
+
+// AddWeighted adds each shingle of value with given weight (e.g. TF-IDF of the feature).
+func (h *hash[T]) AddWeighted(value T, weight float64) error {
 	if h.once.Do(h.init); h.err != nil {
 		return h.err
 	}
-	h.token = h.conf.Shingler.AppendShingle(h.token, value)
-	for i := 0; i < len(h.token); i++ {
-		hsum := h.conf.Algo.Sum64([]byte(h.token[i]))
-		for j := uint64(0); j < vectorsz; j += 8 {
-			h.vector[j+0] += btable[(hsum>>j+0)&1]
-			h.vector[j+1] += btable[(hsum>>j+1)&1]
-			h.vector[j+2] += btable[(hsum>>j+2)&1]
-			h.vector[j+3] += btable[(hsum>>j+3)&1]
-			h.vector[j+4] += btable[(hsum>>j+4)&1]
-			h.vector[j+5] += btable[(hsum>>j+5)&1]
-			h.vector[j+6] += btable[(hsum>>j+6)&1]
-			h.vector[j+7] += btable[(hsum>>j+7)&1]
+	if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return lsh.ErrInvalidWeight
+	}
+	if h.conf.Stream {
+		if !h.open {
+			// new document
+			memclr64.ClearUnsafe(unsafe.Pointer(&h.vector), vectorsz*8)
+			h.open = true
 		}
+		h.token = h.ss.AppendChunk(h.token[:0], value)
+		h.addTokens(weight)
+		h.weight = weight
+		return nil
 	}
+	if h.hs != nil {
+		h.hsum = h.hs.AppendHashes(h.hsum[:0], value)
+		for i := 0; i < len(h.hsum); i++ {
+			h.addHashed(h.hsum[i], weight)
+		}
+	} else {
+		h.token = h.conf.Shingler.AppendShingle(h.token[:0], value)
+		h.addTokens(weight)
+	}
+	// shingles already hashed, so shingler buffers may be released for the next value
+	h.conf.Shingler.Reset()
 	return nil
 }
 
+func (h *hash[T]) addTokens(weight float64) {
+	for i := 0; i < len(h.token); i++ {
+		if h.hs != nil {
+			h.addHashed(h.hs.Sum64(h.token[i]), weight)
+			continue
+		}
+		h.buf = append(h.buf[:0], h.token[i]...)
+		if h.conf.Width == Width64 {
+			h.addWord(0, h.conf.Algo.Sum64(h.buf), weight)
+			continue
+		}
+		hsum := h.conf.Algo128.Sum128(h.buf)
+		h.addWord(0, hsum[0], weight)
+		h.addWord(1, hsum[1], weight)
+		if h.conf.Width == Width256 {
+			// extend 128-bit hash sum to 256 bits
+			h.addWord(2, pbtk.Fmix64(hsum[0]^0x9e3779b97f4a7c15), weight)
+			h.addWord(3, pbtk.Fmix64(hsum[1]^0xc2b2ae3d27d4eb4f), weight)
+		}
+	}
+}
+
+// Same as above, but uses hash sum of hash shingler. Wider fingerprints extend 64-bit hash sum.
+func (h *hash[T]) addHashed(hsum uint64, weight float64) {
+	h.addWord(0, hsum, weight)
+	if h.conf.Width == Width64 {
+		return
+	}
+	h.addWord(1, pbtk.Fmix64(hsum^0x9e3779b97f4a7c15), weight)
+	if h.conf.Width == Width256 {
+		h.addWord(2, pbtk.Fmix64(hsum^0xc2b2ae3d27d4eb4f), weight)
+		h.addWord(3, pbtk.Fmix64(hsum^0x165667b19e3779f9), weight)
+	}
+}
+
+func (h *hash[T]) addWord(w, hsum uint64, weight float64) {
+	btable := [2]float64{-weight, weight}
+	vec := h.vector[w*64 : w*64+64]
+	for j := uint64(0); j < 64; j += 8 {
+		vec[j+0] += btable[(hsum>>(j+0))&1]
+		vec[j+1] += btable[(hsum>>(j+1))&1]
+		vec[j+2] += btable[(hsum>>(j+2))&1]
+		vec[j+3] += btable[(hsum>>(j+3))&1]
+		vec[j+4] += btable[(hsum>>(j+4))&1]
+		vec[j+5] += btable[(hsum>>(j+5))&1]
+		vec[j+6] += btable[(hsum>>(j+6))&1]
+		vec[j+7] += btable[(hsum>>(j+7))&1]
+	}
+}
+
 func (h *hash[T]) Hash() []uint64 {
-	var r [1]uint64
+	var r [vectorsz / 64]uint64
 	return h.AppendHash(r[:0])
 }
 
+// AppendHash appends fingerprint to dst as Width/64 words.
 func (h *hash[T]) AppendHash(dst []uint64) []uint64 {
-	var r uint64
-	for i := 0; i < vectorsz; i++ {
-		if h.vector[i] >= 0 {
-			r = r | rtable[i]
+	if h.open {
+		// finish streaming document, the rest of shingles takes weight of the last chunk
+		h.token = h.ss.Flush(h.token[:0])
+		h.addTokens(h.weight)
+		h.open = false
+	}
+	for w := uint64(0); w < h.conf.Width/64; w++ {
+		var r uint64
+		vec := h.vector[w*64 : w*64+64]
+		for i := 0; i < 64; i++ {
+			if vec[i] >= 0 {
+				r = r | rtable[i]
+			}
 		}
+		dst = append(dst, r)
 	}
-	return append(dst, r)
+	return dst
+}
+
+// Bitwise returns true since signature is a binary fingerprint.
+func (h *hash[T]) Bitwise() bool {
+	return true
+}
+
+// Params returns params of fingerprints.
+func (h *hash[T]) Params() lsh.Params {
+	return lsh.Params{Kind: lsh.KindSimHash, K: h.conf.Width, B: 64}
 }
 
 func (h *hash[T]) Reset() {
 	memclr64.ClearUnsafe(unsafe.Pointer(&h.vector), vectorsz*8)
 	h.token = h.token[:0]
+	h.hsum = h.hsum[:0]
+	h.buf = h.buf[:0]
+	h.open = false
 	h.conf.Shingler.Reset()
 }
 
 func (h *hash[T]) init() {
-	if h.conf.Algo == nil {
-		h.err = pbtk.ErrNoHasher
+	if h.conf.Width == 0 {
+		h.conf.Width = Width64
+	}
+	switch h.conf.Width {
+	case Width64:
+		if h.conf.Algo == nil {
+			h.err = pbtk.ErrNoHasher
+			return
+		}
+	case Width128, Width256:
+		if h.conf.Algo128 == nil {
+			h.err = pbtk.ErrNoHasher
+			return
+		}
+	default:
+		h.err = lsh.ErrInvalidWidth
 		return
 	}
 	if h.conf.Shingler == nil {
 		h.err = lsh.ErrNoShingler
 		return
 	}
+	h.hs, _ = h.conf.Shingler.(shingle.HashShingler[T])
+	if h.conf.Stream {
+		var ok bool
+		if h.ss, ok = h.conf.Shingler.(shingle.StreamShingler[T]); !ok {
+			h.err = lsh.ErrStreamUnsupported
+			return
+		}
+	}
 }
@@ -4,14 +4,17 @@ import (
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/shingle"
 )
 
 var (
-	testh   = xxhash.Hasher64[[]byte]{}
-	testshc = shingle.NewChar[[]byte](3, "") // 3-gram
-	testshw = shingle.NewWord[[]byte](2, "") // 2-word shingle
+	testh    = xxhash.Hasher64[[]byte]{}
+	testh128 = xxhash.Hasher128[[]byte]{}
+	testshc  = shingle.NewChar[[]byte](3, "")        // 3-gram
+	testshw  = shingle.NewWord[[]byte](2, "")        // 2-word shingle
+	testshr  = shingle.NewRollingChar[[]byte](3, "") // 3-gram with rolling hash sums
 )
 
 func TestHash(t *testing.T) {
@@ -25,6 +28,97 @@ func TestHash(t *testing.T) {
 		_ = err
 		lsh.TestMe(t, h, lsh.TestDistHamming, 1, 1.0)
 	})
+	t.Run("rolling", func(t *testing.T) {
+		h, err := NewHasher[[]byte](NewConfig(testh, testshr))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestMe(t, h, lsh.TestDistHamming, 1, 1.0)
+	})
+	t.Run("stream", func(t *testing.T) {
+		// solid and stream hashers need own shinglers
+		for _, newsh := range []func() shingle.Shingler[[]byte]{
+			func() shingle.Shingler[[]byte] { return shingle.NewChar[[]byte](3, "") },
+			func() shingle.Shingler[[]byte] { return shingle.NewWord[[]byte](2, "") },
+			func() shingle.Shingler[[]byte] { return shingle.NewRollingChar[[]byte](3, "") },
+		} {
+			for _, w := range []uint64{Width64, Width256} {
+				solid, _ := NewHasher[[]byte](NewConfig(testh, newsh()).WithAlgo128(testh128).WithWidth(w))
+				stream, err := NewHasher[[]byte](NewConfig(testh, newsh()).WithAlgo128(testh128).WithWidth(w).WithStream())
+				if err != nil {
+					t.Fatal(err)
+				}
+				lsh.TestStreamMe(t, solid, stream)
+			}
+		}
+		// weight of each chunk applies to shingles completed by the chunk
+		h, _ := NewHasher[[]byte](NewConfig(testh, shingle.NewWord[[]byte](1, "")).WithStream())
+		_ = h.AddWeighted([]byte("foo "), 10)
+		_ = h.AddWeighted([]byte("bar"), .1)
+		a := h.Hash()
+		_ = h.Add([]byte("foo"))
+		if d := lsh.Hamming(a, h.Hash()); d != 0 {
+			t.Errorf("expected equal fingerprints, got distance %d", d)
+		}
+		if _, err := NewHasher[[]byte](NewConfig(testh, shingle.NewNOP[[]byte]()).WithStream()); err != lsh.ErrStreamUnsupported {
+			t.Errorf("expected stream unsupported error, got %v", err)
+		}
+	})
+	t.Run("width", func(t *testing.T) {
+		for _, w := range []uint64{Width128, Width256} {
+			h, err := NewHasher[[]byte](NewConfig(nil, testshc).WithAlgo128(testh128).WithWidth(w))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_ = h.Add([]byte("A sad man is crying"))
+			a := h.Hash()
+			h.Reset()
+			_ = h.Add([]byte("A sad man is crying loudly"))
+			b := h.Hash()
+			if len(a) != int(w/64) {
+				t.Fatalf("expected %d words fingerprint, got %d", w/64, len(a))
+			}
+			if d := lsh.Hamming(a, b); d > w/4 {
+				t.Errorf("width %d: too big distance %d between similar texts", w, d)
+			}
+		}
+		if _, err := NewHasher[[]byte](NewConfig(testh, testshc).WithWidth(96)); err != lsh.ErrInvalidWidth {
+			t.Errorf("expected invalid width error, got %v", err)
+		}
+		if _, err := NewHasher[[]byte](NewConfig(testh, testshc).WithWidth(Width128)); err != pbtk.ErrNoHasher {
+			t.Errorf("expected no hasher error, got %v", err)
+		}
+	})
+	t.Run("signature", func(t *testing.T) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testshc))
+		lsh.TestSignatureMe(t, h, lsh.Params{Kind: lsh.KindSimHash, K: Width64, B: 64})
+		h, _ = NewHasher[[]byte](NewConfig(nil, testshc).WithAlgo128(testh128).WithWidth(Width256))
+		lsh.TestSignatureMe(t, h, lsh.Params{Kind: lsh.KindSimHash, K: Width256, B: 64})
+	})
+	t.Run("weighted", func(t *testing.T) {
+		shw := shingle.NewWord[[]byte](1, "")
+		h, _ := NewHasher[[]byte](NewConfig(testh, shw))
+		_ = h.AddWeighted([]byte("foo"), 10)
+		a := h.Hash()
+		_ = h.AddWeighted([]byte("bar"), .1)
+		b := h.Hash()
+		// feature with tiny weight can't overturn dominating one
+		if d := lsh.Hamming(a, b); d != 0 {
+			t.Errorf("expected equal fingerprints, got distance %d", d)
+		}
+		h.Reset()
+		_ = h.Add([]byte("foo"))
+		_ = h.Add([]byte("bar"))
+		c := h.Hash()
+		h.Reset()
+		_ = h.Add([]byte("foo bar"))
+		if d := lsh.Hamming(c, h.Hash()); d != 0 {
+			t.Errorf("expected equal fingerprints, got distance %d", d)
+		}
+		if err := h.AddWeighted([]byte("foo"), -1); err != lsh.ErrInvalidWeight {
+			t.Errorf("expected invalid weight error, got %v", err)
+		}
+	})
 }
 
 func BenchmarkHash(b *testing.B) {
@@ -38,4 +132,12 @@ func BenchmarkHash(b *testing.B) {
 		_ = err
 		lsh.BenchMe(b, h)
 	})
+	b.Run("rolling", func(b *testing.B) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testshr))
+		lsh.BenchMe(b, h)
+	})
+	b.Run("width256", func(b *testing.B) {
+		h, _ := NewHasher[[]byte](NewConfig(nil, testshc).WithAlgo128(testh128).WithWidth(Width256))
+		lsh.BenchMe(b, h)
+	})
 }
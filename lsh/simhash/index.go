@@ -0,0 +1,361 @@
+package simhash
+
+import (
+	"cmp"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/koykov/pbtk"
+)
+
+const (
+	indexDumpSignature = 0x3b9e52d1c07a46f8
+	indexDumpVersion   = 1.0
+
+	maxTables = 256
+)
+
+// Index of 64-bit SimHash fingerprints to search all fingerprints within given Hamming distance (Manku et al. scheme).
+// See https://research.google/pubs/detecting-near-duplicates-for-web-crawling/ for details.
+//
+// Fingerprint splits to B blocks, at least B-K blocks of two fingerprints within distance K are equal. So, index keeps
+// C(B, K) tables of permuted fingerprints (each combination of B-K blocks moves to the top bits) sorted by value. Query
+// checks only fingerprints with equal top bits in each table.
+//
+// Caution! Index isn't lock-free structure, all operations are protected by RW mutex.
+type Index struct {
+	once   sync.Once
+	conf   *IndexConfig
+	mux    sync.RWMutex
+	tables []table
+
+	err error
+}
+
+type table struct {
+	perm    []move  // blocks moves to build table key
+	inv     []move  // reverse moves to restore fingerprint
+	mask    uint64  // mask of exact prefix
+	sorted  []entry // entries sorted by key
+	pending []entry // recently inserted entries, merges to sorted on overflow
+}
+
+type entry struct {
+	key, id uint64
+}
+
+type move struct {
+	src, dst, mask uint64
+}
+
+func NewIndex(conf *IndexConfig) (*Index, error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	idx := &Index{conf: conf.copy()}
+	if idx.once.Do(idx.init); idx.err != nil {
+		return nil, idx.err
+	}
+	return idx, nil
+}
+
+// Insert adds fingerprint of document id to the index.
+func (idx *Index) Insert(id, fingerprint uint64) error {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return idx.err
+	}
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	for i := 0; i < len(idx.tables); i++ {
+		t := &idx.tables[i]
+		t.pending = append(t.pending, entry{key: permute(t.perm, fingerprint), id: id})
+		if len(t.pending) > max(256, len(t.sorted)/16) {
+			t.merge()
+		}
+	}
+	return nil
+}
+
+// Build flushes the index and bulk loads fingerprints of documents. ids[i] is an id of fingerprints[i] document.
+func (idx *Index) Build(ids, fingerprints []uint64) error {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return idx.err
+	}
+	if len(ids) != len(fingerprints) {
+		return ErrLengthMismatch
+	}
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	for i := 0; i < len(idx.tables); i++ {
+		t := &idx.tables[i]
+		t.sorted, t.pending = t.sorted[:0], t.pending[:0]
+		t.sorted = slices.Grow(t.sorted, len(ids))
+		for j := 0; j < len(ids); j++ {
+			t.sorted = append(t.sorted, entry{key: permute(t.perm, fingerprints[j]), id: ids[j]})
+		}
+		slices.SortFunc(t.sorted, cmpEntry)
+	}
+	return nil
+}
+
+// QueryWithin returns sorted ids of documents which fingerprints are within Hamming distance k of given fingerprint.
+func (idx *Index) QueryWithin(fingerprint, k uint64) ([]uint64, error) {
+	return idx.AppendQueryWithin(nil, fingerprint, k)
+}
+
+// AppendQueryWithin appends sorted ids of documents which fingerprints are within Hamming distance k of given
+// fingerprint to dst.
+func (idx *Index) AppendQueryWithin(dst []uint64, fingerprint, k uint64) ([]uint64, error) {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return dst, idx.err
+	}
+	if k > idx.conf.K {
+		return dst, ErrDistanceOverflow
+	}
+	off := len(dst)
+	idx.mux.RLock()
+	for i := 0; i < len(idx.tables); i++ {
+		t := &idx.tables[i]
+		q := permute(t.perm, fingerprint)
+		lo := q & t.mask
+		j, _ := slices.BinarySearchFunc(t.sorted, lo, func(e entry, key uint64) int { return cmp.Compare(e.key, key) })
+		for ; j < len(t.sorted) && t.sorted[j].key&t.mask == lo; j++ {
+			if uint64(bits.OnesCount64(t.sorted[j].key^q)) <= k {
+				dst = append(dst, t.sorted[j].id)
+			}
+		}
+		for j = 0; j < len(t.pending); j++ {
+			if uint64(bits.OnesCount64(t.pending[j].key^q)) <= k {
+				dst = append(dst, t.pending[j].id)
+			}
+		}
+	}
+	idx.mux.RUnlock()
+	// deduplicate documents found in several tables
+	slices.Sort(dst[off:])
+	return dst[:off+len(slices.Compact(dst[off:]))], nil
+}
+
+// Tables returns number of tables and exact prefix length in bits.
+func (idx *Index) Tables() (tables, prefix uint64) {
+	if idx.once.Do(idx.init); idx.err != nil || len(idx.tables) == 0 {
+		return
+	}
+	return uint64(len(idx.tables)), uint64(bits.OnesCount64(idx.tables[0].mask))
+}
+
+// Len returns number of fingerprints in the index.
+func (idx *Index) Len() uint64 {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return 0
+	}
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+	return uint64(len(idx.tables[0].sorted) + len(idx.tables[0].pending))
+}
+
+// Reset flushes the index.
+func (idx *Index) Reset() {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return
+	}
+	idx.mux.Lock()
+	defer idx.mux.Unlock()
+	for i := 0; i < len(idx.tables); i++ {
+		idx.tables[i].sorted = idx.tables[i].sorted[:0]
+		idx.tables[i].pending = idx.tables[i].pending[:0]
+	}
+}
+
+// WriteTo writes index to w. Dump contains header and list of documents ids with their fingerprints, so tables
+// rebuild on reading.
+func (idx *Index) WriteTo(w io.Writer) (n int64, err error) {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return 0, idx.err
+	}
+	idx.mux.RLock()
+	defer idx.mux.RUnlock()
+
+	t := &idx.tables[0]
+	const blocksz = 4096
+	buf := make([]byte, 0, blocksz)
+	buf = binary.LittleEndian.AppendUint64(buf, indexDumpSignature)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(indexDumpVersion))
+	buf = binary.LittleEndian.AppendUint64(buf, idx.conf.K)
+	buf = binary.LittleEndian.AppendUint64(buf, idx.conf.Blocks)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(t.sorted)+len(t.pending)))
+	var m int
+	for _, list := range [2][]entry{t.sorted, t.pending} {
+		for i := 0; i < len(list); i++ {
+			buf = binary.LittleEndian.AppendUint64(buf, list[i].id)
+			buf = binary.LittleEndian.AppendUint64(buf, permute(t.inv, list[i].key))
+			if len(buf) >= blocksz {
+				m, err = w.Write(buf)
+				n += int64(m)
+				if err != nil {
+					return
+				}
+				buf = buf[:0]
+			}
+		}
+	}
+	m, err = w.Write(buf)
+	n += int64(m)
+	return
+}
+
+// ReadFrom reads index from r. Dump must be written by index with the same config.
+func (idx *Index) ReadFrom(r io.Reader) (n int64, err error) {
+	if idx.once.Do(idx.init); idx.err != nil {
+		return 0, idx.err
+	}
+	var (
+		buf [40]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint64(buf[0:8]) != indexDumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint64(buf[8:16]) != math.Float64bits(indexDumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	if binary.LittleEndian.Uint64(buf[16:24]) != idx.conf.K || binary.LittleEndian.Uint64(buf[24:32]) != idx.conf.Blocks {
+		return n, ErrConfigMismatch
+	}
+	count := binary.LittleEndian.Uint64(buf[32:40])
+
+	ids, fps := make([]uint64, 0, count), make([]uint64, 0, count)
+	var rec [16]byte
+	for i := uint64(0); i < count; i++ {
+		m, err = io.ReadFull(r, rec[:])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		ids = append(ids, binary.LittleEndian.Uint64(rec[0:8]))
+		fps = append(fps, binary.LittleEndian.Uint64(rec[8:16]))
+	}
+	err = idx.Build(ids, fps)
+	return
+}
+
+// merge sorts pending entries and merges them to sorted entries from the tail.
+func (t *table) merge() {
+	slices.SortFunc(t.pending, cmpEntry)
+	i, j := len(t.sorted)-1, len(t.pending)-1
+	t.sorted = append(t.sorted, t.pending...)
+	for k := len(t.sorted) - 1; j >= 0; k-- {
+		if i >= 0 && t.sorted[i].key > t.pending[j].key {
+			t.sorted[k] = t.sorted[i]
+			i--
+		} else {
+			t.sorted[k] = t.pending[j]
+			j--
+		}
+	}
+	t.pending = t.pending[:0]
+}
+
+func (idx *Index) init() {
+	c := idx.conf
+	if c.Blocks == 0 {
+		c.Blocks = c.K + 1
+	}
+	if c.Blocks <= c.K || c.Blocks > 64 {
+		idx.err = ErrInvalidBlocks
+		return
+	}
+	if binomial(c.Blocks, c.K) > maxTables {
+		idx.err = ErrTooManyTables
+		return
+	}
+
+	// split 64 bits to blocks, first 64%B blocks are one bit longer
+	offs, sizes := make([]uint64, c.Blocks), make([]uint64, c.Blocks)
+	var off uint64
+	for i := uint64(0); i < c.Blocks; i++ {
+		sizes[i] = 64 / c.Blocks
+		if i < 64%c.Blocks {
+			sizes[i]++
+		}
+		offs[i] = off
+		off += sizes[i]
+	}
+
+	// each combination of B-K blocks makes a table with these blocks at the top bits
+	chosen := make([]bool, c.Blocks)
+	combine(c.Blocks, c.Blocks-c.K, func(comb []uint64) {
+		clear(chosen)
+		for _, b := range comb {
+			chosen[b] = true
+		}
+		var t table
+		dst := uint64(64)
+		place := func(b uint64) {
+			dst -= sizes[b]
+			mask := uint64(math.MaxUint64) >> (64 - sizes[b])
+			t.perm = append(t.perm, move{src: offs[b], dst: dst, mask: mask})
+			t.inv = append(t.inv, move{src: dst, dst: offs[b], mask: mask})
+		}
+		for _, b := range comb {
+			place(b)
+		}
+		t.mask = ^uint64(0) << dst
+		for b := uint64(0); b < c.Blocks; b++ {
+			if !chosen[b] {
+				place(b)
+			}
+		}
+		idx.tables = append(idx.tables, t)
+	})
+}
+
+func permute(moves []move, x uint64) (r uint64) {
+	for i := 0; i < len(moves); i++ {
+		m := &moves[i]
+		r |= (x >> m.src & m.mask) << m.dst
+	}
+	return
+}
+
+// combine calls fn for each combination of k numbers from range [0..n).
+func combine(n, k uint64, fn func([]uint64)) {
+	comb := make([]uint64, k)
+	var rec func(start, depth uint64)
+	rec = func(start, depth uint64) {
+		if depth == k {
+			fn(comb)
+			return
+		}
+		for i := start; i <= n-(k-depth); i++ {
+			comb[depth] = i
+			rec(i+1, depth+1)
+		}
+	}
+	rec(0, 0)
+}
+
+func binomial(n, k uint64) uint64 {
+	k = min(k, n-k)
+	r := uint64(1)
+	for i := uint64(1); i <= k; i++ {
+		r = r * (n - k + i) / i
+		if r > maxTables {
+			return r
+		}
+	}
+	return r
+}
+
+func cmpEntry(a, b entry) int {
+	return cmp.Compare(a.key, b.key)
+}
@@ -0,0 +1,209 @@
+package simhash
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/simtest"
+)
+
+// Generates n fingerprints, each fourth is a near-duplicate of one of the previous fingerprints.
+func testFingerprints(rng *rand.Rand, n int) []uint64 {
+	fps := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		if i > 0 && i%4 == 0 {
+			fp := fps[rng.Intn(i)]
+			for _, j := range rng.Perm(64)[:rng.Intn(6)] {
+				fp ^= 1 << j
+			}
+			fps[i] = fp
+			continue
+		}
+		fps[i] = rng.Uint64()
+	}
+	return fps
+}
+
+func testBruteforce(fps []uint64, q, k uint64) (r []uint64) {
+	for i := 0; i < len(fps); i++ {
+		if uint64(bits.OnesCount64(fps[i]^q)) <= k {
+			r = append(r, uint64(i))
+		}
+	}
+	return
+}
+
+func testIndex(t *testing.T, idx *Index, c *IndexConfig, ids, fps []uint64) {
+	n := len(fps)
+	check := func(t *testing.T, idx *Index, k uint64) {
+		for i := 0; i < n; i += 7 {
+			r, err := idx.QueryWithin(fps[i], k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expect := testBruteforce(fps, fps[i], k); !slices.Equal(r, expect) {
+				t.Fatalf("query %d within %d: expected %v, got %v", i, k, expect, r)
+			}
+		}
+	}
+	t.Run("insert", func(t *testing.T) {
+		for i := 0; i < n; i++ {
+			_ = idx.Insert(ids[i], fps[i])
+		}
+		if idx.Len() != uint64(n) {
+			t.Fatalf("expected %d fingerprints, got %d", n, idx.Len())
+		}
+		for k := uint64(0); k <= c.K; k++ {
+			check(t, idx, k)
+		}
+	})
+	t.Run("build", func(t *testing.T) {
+		if err := idx.Build(ids, fps); err != nil {
+			t.Fatal(err)
+		}
+		if idx.Len() != uint64(n) {
+			t.Fatalf("expected %d fingerprints, got %d", n, idx.Len())
+		}
+		check(t, idx, c.K)
+	})
+	t.Run("io", func(t *testing.T) {
+		_ = idx.Insert(uint64(n), fps[0]) // pending entry
+		var buf bytes.Buffer
+		wn, err := idx.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expect := int64(40 + (n+1)*16); wn != expect {
+			t.Fatalf("expected %d bytes, got %d", expect, wn)
+		}
+		cpy, _ := NewIndex(c)
+		rn, err := cpy.ReadFrom(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rn != wn {
+			t.Fatalf("expected %d bytes read, got %d", wn, rn)
+		}
+		r0, _ := idx.QueryWithin(fps[0], c.K)
+		r1, _ := cpy.QueryWithin(fps[0], c.K)
+		if !slices.Equal(r0, r1) || !slices.Contains(r1, uint64(n)) {
+			t.Errorf("restored index query mismatch: expected %v, got %v", r0, r1)
+		}
+		other, _ := NewIndex(NewIndexConfig(c.K + 1))
+		if _, err = other.ReadFrom(bytes.NewReader(buf.Bytes())); err != ErrConfigMismatch {
+			t.Errorf("expected config mismatch error, got %v", err)
+		}
+		if _, err = other.ReadFrom(bytes.NewReader(make([]byte, 40))); err != pbtk.ErrInvalidSignature {
+			t.Errorf("expected invalid signature error, got %v", err)
+		}
+	})
+	t.Run("reset", func(t *testing.T) {
+		idx.Reset()
+		if r, _ := idx.QueryWithin(fps[0], c.K); idx.Len() != 0 || len(r) != 0 {
+			t.Errorf("expected empty index, got %d fingerprints", idx.Len())
+		}
+	})
+}
+
+func TestIndex(t *testing.T) {
+	const n = 1e4
+	rng := rand.New(rand.NewSource(1))
+	fps := testFingerprints(rng, n)
+	ids := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		ids[i] = uint64(i)
+	}
+	for _, c := range []*IndexConfig{
+		NewIndexConfig(0),
+		NewIndexConfig(3),
+		NewIndexConfig(3).WithBlocks(6),
+		NewIndexConfig(5).WithBlocks(8),
+	} {
+		idx, err := NewIndex(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables, prefix := idx.Tables()
+		t.Run(fmt.Sprintf("K%d/tables%d/prefix%d", c.K, tables, prefix), func(t *testing.T) { testIndex(t, idx, c, ids, fps) })
+	}
+	t.Run("errors", func(t *testing.T) {
+		if _, err := NewIndex(NewIndexConfig(3).WithBlocks(3)); err != ErrInvalidBlocks {
+			t.Errorf("expected invalid blocks error, got %v", err)
+		}
+		if _, err := NewIndex(NewIndexConfig(8).WithBlocks(32)); err != ErrTooManyTables {
+			t.Errorf("expected too many tables error, got %v", err)
+		}
+		idx, _ := NewIndex(NewIndexConfig(3))
+		if _, err := idx.QueryWithin(0, 4); err != ErrDistanceOverflow {
+			t.Errorf("expected distance overflow error, got %v", err)
+		}
+		if err := idx.Build(ids, fps[:1]); err != ErrLengthMismatch {
+			t.Errorf("expected length mismatch error, got %v", err)
+		}
+	})
+}
+
+func BenchmarkIndex(b *testing.B) {
+	const n = 1e6
+	rng := rand.New(rand.NewSource(1))
+	fps := testFingerprints(rng, n)
+	ids := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		ids[i] = uint64(i)
+	}
+	b.Run("insert", func(b *testing.B) {
+		idx, _ := NewIndex(NewIndexConfig(3))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = idx.Insert(uint64(i), fps[i%n])
+		}
+	})
+	b.Run("build", func(b *testing.B) {
+		idx, _ := NewIndex(NewIndexConfig(3))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = idx.Build(ids, fps)
+		}
+	})
+	for _, c := range []*IndexConfig{NewIndexConfig(3), NewIndexConfig(3).WithBlocks(6)} {
+		idx, _ := NewIndex(c)
+		_ = idx.Build(ids, fps)
+		tables, _ := idx.Tables()
+		b.Run(fmt.Sprintf("query/tables%d", tables), func(b *testing.B) {
+			var buf []uint64
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf, _ = idx.AppendQueryWithin(buf[:0], fps[i%n], 3)
+			}
+		})
+	}
+	simtest.EachTestingDataset(func(_ int, ds *simtest.Dataset) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testshw))
+		idx, _ := NewIndex(NewIndexConfig(3))
+		qs := make([]uint64, 0, len(ds.Tuples))
+		for i := 0; i < len(ds.Tuples); i++ {
+			h.Reset()
+			_ = h.Add(ds.Tuples[i].A)
+			_ = idx.Insert(uint64(ds.Tuples[i].ID), h.Hash()[0])
+			h.Reset()
+			_ = h.Add(ds.Tuples[i].B)
+			qs = append(qs, h.Hash()[0])
+		}
+		b.Run("query/"+ds.Name, func(b *testing.B) {
+			var buf []uint64
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf, _ = idx.AppendQueryWithin(buf[:0], qs[i%len(qs)], 3)
+			}
+		})
+	})
+}
@@ -1,12 +1,9 @@
 package simhash
 
-var (
-	btable = [2]int64{-1, 1}
-	rtable = [vectorsz]uint64{}
-)
+var rtable = [64]uint64{}
 
 func init() {
-	for i := uint64(0); i < vectorsz; i++ {
+	for i := uint64(0); i < 64; i++ {
 		rtable[i] = uint64(1) << i
 	}
 }
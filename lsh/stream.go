@@ -0,0 +1,35 @@
+package lsh
+
+import (
+	"io"
+
+	"github.com/koykov/byteseq"
+)
+
+// AddReader reads document from r and adds it to hasher by chunks until EOF. Hasher must be configured in streaming
+// mode, so shingles spanning chunks boundaries handle as if the document was solid. Optional buf uses to read chunks,
+// 32KB buffer allocates otherwise.
+func AddReader[T byteseq.Q](h Hasher[T], r io.Reader, buf []byte) (n int64, err error) {
+	if len(buf) == 0 {
+		buf = make([]byte, 32*1024)
+	}
+	for {
+		m, rerr := r.Read(buf)
+		n += int64(m)
+		if m > 0 {
+			if err = h.Add(byteseq.B2Q[T](buf[:m])); err != nil {
+				return
+			}
+		}
+		if rerr == io.EOF {
+			if n == 0 {
+				// empty document
+				err = h.Add(byteseq.B2Q[T](buf[:0]))
+			}
+			return
+		}
+		if rerr != nil {
+			return n, rerr
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package lsh
 
 import (
+	"bytes"
 	"fmt"
-	"math/bits"
+	"io"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/simtest"
 )
 
@@ -34,6 +38,126 @@ func TestMe[T []byte](t *testing.T, hash Hasher[T], distFn func([]uint64, []uint
 	})
 }
 
+// TestStreamMe checks that streaming hasher makes the same signatures from documents read by small chunks as solid
+// hasher makes from the whole documents.
+func TestStreamMe[T []byte](t *testing.T, solid, stream Hasher[T]) {
+	docs := []string{
+		"",
+		"foo",
+		"A sad man is crying",
+		"The young boys are playing outdoors and the man is smiling nearby",
+		"Lorem ipsum dolor sit amet,  consectetur adipiscing elit.\nMauris varius nisi erat, ac vulputate elit malesuada ut.",
+		"Съешь же ещё этих мягких французских булок, да выпей чаю. 我爱北京天安门 Ünïcödé",
+	}
+	var buf [7]byte
+	stream.Reset()
+	for _, doc := range docs {
+		solid.Reset()
+		_ = solid.Add(T(doc))
+		expect := solid.Hash()
+		// Hash finishes the document, so reset isn't required
+		if _, err := AddReader[T](stream, strings.NewReader(doc), buf[:]); err != nil {
+			t.Fatal(err)
+		}
+		if r := stream.Hash(); !slices.Equal(r, expect) {
+			t.Errorf("signature mismatch of '%s'", doc)
+		}
+	}
+}
+
+// TestSignatureMe checks params of signature made by hasher and its serialization round trip.
+func TestSignatureMe[T []byte](t *testing.T, hash Hasher[T], expect Params) {
+	hash.Reset()
+	_ = hash.Add(T("The young boys are playing outdoors and the man is smiling nearby"))
+	sig := NewSignature[T](hash)
+	if sig.Params != expect {
+		t.Fatalf("params mismatch: expected %+v, got %+v", expect, sig.Params)
+	}
+	if !slices.Equal(sig.Values, hash.Hash()) {
+		t.Fatal("signature values mismatch")
+	}
+	p, err := sig.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cpy Signature
+	if err = cpy.UnmarshalBinary(p); err != nil {
+		t.Fatal(err)
+	}
+	if !cpy.Equal(sig) {
+		t.Errorf("unmarshalled signature mismatch: %+v", cpy)
+	}
+	var buf bytes.Buffer
+	wn, err := sig.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wn != int64(len(p)) {
+		t.Fatalf("expected %d bytes, got %d", len(p), wn)
+	}
+	rn, err := cpy.ReadFrom(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rn != wn || !cpy.Equal(sig) {
+		t.Errorf("read signature mismatch: %+v", cpy)
+	}
+	if err = cpy.UnmarshalBinary(p[:len(p)-1]); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected unexpected EOF error, got %v", err)
+	}
+	if err = cpy.UnmarshalBinary(make([]byte, len(p))); err != pbtk.ErrInvalidSignature {
+		t.Errorf("expected invalid signature error, got %v", err)
+	}
+	other := *sig
+	other.K++
+	if err = sig.Compatible(&other); err != ErrSignatureMismatch {
+		t.Errorf("expected signature mismatch error, got %v", err)
+	}
+}
+
+var testSignaturePairs = [][2]string{
+	{"Four children are doing backbends in the gym", "Four children are doing backbends in the park"},
+	{"A man is sitting near a bike and is writing a note", "A man is standing near a bike and is writing on a piece paper"},
+	{"One white dog and one black one are sitting side by side on the grass", "A black and a white dog are joyfully running on the grass"},
+}
+
+// TestSignaturesMe checks that comparison of signatures stored by hasher equals comparison of the source texts. cmp
+// compares the texts, cmpSig compares signatures and must reject signatures with foreign params. hash must be the same
+// hasher as LSH of the comparator.
+func TestSignaturesMe(t *testing.T, hash Hasher[[]byte], cmp func(a, b []byte) (float64, error),
+	cmpSig func(a, b *Signature) (float64, error)) {
+	for _, tp := range testSignaturePairs {
+		expect, err := cmp([]byte(tp[0]), []byte(tp[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash.Reset()
+		_ = hash.Add([]byte(tp[0]))
+		sa := NewSignature[[]byte](hash)
+		hash.Reset()
+		_ = hash.Add([]byte(tp[1]))
+		sb := NewSignature[[]byte](hash)
+		// comparator isn't reset to check that previous state doesn't affect the result
+		r, err := cmpSig(sa, sb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != expect {
+			t.Errorf("signatures comparison mismatch: expected %f, got %f", expect, r)
+		}
+		// signatures of hasher with different params must be rejected
+		sc := *sb
+		sc.K++
+		if _, err = cmpSig(sa, &sc); err != ErrSignatureMismatch {
+			t.Errorf("expected signature mismatch error, got %v", err)
+		}
+		sa.K++
+		if _, err = cmpSig(sa, &sc); err != ErrSignatureMismatch {
+			t.Errorf("expected signature mismatch error of hasher params, got %v", err)
+		}
+	}
+}
+
 func BenchMe[T []byte](b *testing.B, hash Hasher[T]) {
 	stages := [][]byte{
 		[]byte("foo"),
@@ -71,18 +195,7 @@ func BenchMe[T []byte](b *testing.B, hash Hasher[T]) {
 }
 
 func TestDistHamming(h0, h1 []uint64, _ uint64) (r float64) {
-	n := max(len(h0), len(h1))
-	for i := 0; i < n; i++ {
-		var v0, v1 uint64
-		if i < len(h0) {
-			v0 = h0[i]
-		}
-		if i < len(h1) {
-			v1 = h1[i]
-		}
-		r += float64(bits.OnesCount64(v0 ^ v1))
-	}
-	return r
+	return float64(Hamming(h0, h1))
 }
 
 func TestDistJaccard(h0, h1 []uint64, n uint64) (r float64) {
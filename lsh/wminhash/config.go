@@ -0,0 +1,41 @@
+package wminhash
+
+import (
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/shingle"
+)
+
+type Config[T byteseq.Q] struct {
+	// Hash algorithm to use.
+	// Mandatory param.
+	Algo pbtk.Hasher
+	// Number of samples (signature length).
+	// Mandatory param.
+	K uint64
+	// Shingler to vector input data.
+	// If shingler implements shingle.HashShingler, its hash sums use instead of hashing of each shingle by Algo.
+	// Mandatory param.
+	Shingler shingle.Shingler[T]
+	// Seed of random samples.
+	// Hashers with different seeds produce incomparable signatures.
+	Seed uint64
+}
+
+func NewConfig[T byteseq.Q](algo pbtk.Hasher, k uint64, shingler shingle.Shingler[T]) *Config[T] {
+	return &Config[T]{
+		Algo:     algo,
+		K:        k,
+		Shingler: shingler,
+	}
+}
+
+func (c *Config[T]) WithSeed(seed uint64) *Config[T] {
+	c.Seed = seed
+	return c
+}
+
+func (c *Config[T]) copy() *Config[T] {
+	cpy := *c
+	return &cpy
+}
@@ -0,0 +1,146 @@
+package wminhash
+
+import (
+	"math"
+	"sync"
+
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/shingle"
+)
+
+type hash[T byteseq.Q] struct {
+	conf  *Config[T]
+	token []T
+	hsum  []uint64 // hash sums of shingles (hash shingler)
+	hs    shingle.HashShingler[T]
+	buf   []byte
+	w     map[uint64]float64 // shingle hash -> weight
+	once  sync.Once
+
+	err error
+}
+
+// NewHasher makes new weighted MinHash hasher based on Improved Consistent Weighted Sampling (ICWS).
+// See https://ieeexplore.ieee.org/document/5693978 for details.
+func NewHasher[T byteseq.Q](conf *Config[T]) (lsh.WeightedHasher[T], error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	h := &hash[T]{conf: conf.copy()}
+	if h.once.Do(h.init); h.err != nil {
+		return nil, h.err
+	}
+	return h, nil
+}
+
+// Add adds each shingle of value with weight 1. Thus, weight of shingle is a number of its occurrences.
+func (h *hash[T]) Add(value T) error {
+	return h.AddWeighted(value, 1)
+}
+
+// AddWeighted adds each shingle of value with given weight. Weights of equal shingles accumulate.
+func (h *hash[T]) AddWeighted(value T, weight float64) error {
+	if h.once.Do(h.init); h.err != nil {
+		return h.err
+	}
+	if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		return lsh.ErrInvalidWeight
+	}
+	if weight == 0 {
+		return nil
+	}
+	if h.hs != nil {
+		h.hsum = h.hs.AppendHashes(h.hsum[:0], value)
+		for i := 0; i < len(h.hsum); i++ {
+			h.w[h.hsum[i]] += weight
+		}
+	} else {
+		h.token = h.conf.Shingler.AppendShingle(h.token[:0], value)
+		for i := 0; i < len(h.token); i++ {
+			h.buf = append(h.buf[:0], h.token[i]...)
+			h.w[h.conf.Algo.Sum64(h.buf)] += weight
+		}
+	}
+	// shingles already hashed, so shingler buffers may be released for the next value
+	h.conf.Shingler.Reset()
+	return nil
+}
+
+func (h *hash[T]) Hash() []uint64 {
+	r := make([]uint64, 0, h.conf.K)
+	return h.AppendHash(r)
+}
+
+// AppendHash appends K samples to dst. Each sample is a pair (shingle, quantized weight) with minimal ICWS rank, and
+// probability of samples collision is equal to weighted Jaccard similarity of two documents.
+func (h *hash[T]) AppendHash(dst []uint64) []uint64 {
+	if len(h.w) == 0 {
+		return dst
+	}
+	for k := uint64(0); k < h.conf.K; k++ {
+		var (
+			sample uint64
+			mn     = math.Inf(1)
+		)
+		for e, s := range h.w {
+			rnd := rng(e ^ pbtk.Fmix64((k+1)*0x9e3779b97f4a7c15^h.conf.Seed))
+			r := -math.Log(rnd.next() * rnd.next()) // r ~ Gamma(2,1)
+			c := -math.Log(rnd.next() * rnd.next()) // c ~ Gamma(2,1)
+			beta := rnd.next()                      // beta ~ Uniform(0,1)
+			t := math.Floor(math.Log(s)/r + beta)
+			// ln(a) = ln(c) - ln(y) - r, where y = exp(r(t-beta))
+			lna := math.Log(c) - r*(t-beta) - r
+			if lna < mn || (lna == mn && e < sample) {
+				mn = lna
+				sample = pbtk.Fmix64(e ^ uint64(int64(t))*0xc2b2ae3d27d4eb4f)
+			}
+		}
+		dst = append(dst, sample)
+	}
+	return dst
+}
+
+// Positional returns true since i-th values of two signatures represent the same sample.
+func (h *hash[T]) Positional() bool {
+	return true
+}
+
+// Params returns params of signatures.
+func (h *hash[T]) Params() lsh.Params {
+	return lsh.Params{Kind: lsh.KindWeightedMinHash, K: h.conf.K, B: 64, Positional: true}
+}
+
+func (h *hash[T]) Reset() {
+	h.conf.Shingler.Reset()
+	h.token = h.token[:0]
+	h.hsum = h.hsum[:0]
+	h.buf = h.buf[:0]
+	clear(h.w)
+}
+
+func (h *hash[T]) init() {
+	if h.conf.Algo == nil {
+		h.err = pbtk.ErrNoHasher
+		return
+	}
+	if h.conf.K == 0 {
+		h.err = lsh.ErrZeroK
+		return
+	}
+	if h.conf.Shingler == nil {
+		h.err = lsh.ErrNoShingler
+		return
+	}
+	h.hs, _ = h.conf.Shingler.(shingle.HashShingler[T])
+	h.w = make(map[uint64]float64)
+}
+
+// rng is a splitmix64 generator of uniform values in range (0..1).
+type rng uint64
+
+func (r *rng) next() float64 {
+	*r += 0x9e3779b97f4a7c15
+	return (float64(pbtk.Fmix64(uint64(*r))>>11) + .5) / (1 << 53)
+}
@@ -0,0 +1,86 @@
+package wminhash
+
+import (
+	"math"
+	"testing"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/shingle"
+)
+
+var (
+	testh   = xxhash.Hasher64[[]byte]{}
+	testshc = shingle.NewChar[[]byte](3, "") // 3-gram
+	testshw = shingle.NewWord[[]byte](1, "") // 1-word shingle
+	testk   = uint64(256)
+)
+
+func TestHash(t *testing.T) {
+	t.Run("char", func(t *testing.T) {
+		h, err := NewHasher[[]byte](NewConfig(testh, testk, testshc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestMe(t, h, lsh.TestDistJaccard, testk, 1.0)
+	})
+	t.Run("rolling", func(t *testing.T) {
+		h, err := NewHasher[[]byte](NewConfig(testh, testk, shingle.NewRollingChar[[]byte](3, "")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lsh.TestMe(t, h, lsh.TestDistJaccard, testk, 1.0)
+	})
+	t.Run("weighted", func(t *testing.T) {
+		// weighted Jaccard = sum(min)/sum(max) = (1+2+1)/(3+4+1+2) = 0.4
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshw))
+		_ = h.AddWeighted([]byte("foo"), 1)
+		_ = h.AddWeighted([]byte("bar"), 4)
+		_ = h.AddWeighted([]byte("baz"), 1)
+		_ = h.AddWeighted([]byte("qux"), 2)
+		a := h.Hash()
+		h.Reset()
+		_ = h.AddWeighted([]byte("foo"), 3)
+		_ = h.AddWeighted([]byte("bar"), 2)
+		_ = h.AddWeighted([]byte("baz"), 1)
+		b := h.Hash()
+		if len(a) != int(testk) || len(b) != int(testk) {
+			t.Fatalf("expected signatures of length %d, got %d and %d", testk, len(a), len(b))
+		}
+		if j := lsh.TestDistJaccard(a, b, testk); math.Abs(j-.4) > .1 {
+			t.Errorf("weighted jaccard estimation too inaccurate: expected %f, got %f", .4, j)
+		}
+	})
+	t.Run("counts", func(t *testing.T) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshw))
+		_ = h.Add([]byte("foo bar"))
+		_ = h.Add([]byte("bar"))
+		a := h.Hash()
+		h.Reset()
+		_ = h.AddWeighted([]byte("foo"), 1)
+		_ = h.AddWeighted([]byte("bar"), 2)
+		if b := h.Hash(); lsh.TestDistJaccard(a, b, testk) != 1 {
+			t.Error("shingle counts and explicit weights produce different signatures")
+		}
+	})
+	t.Run("signature", func(t *testing.T) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshc))
+		lsh.TestSignatureMe(t, h, lsh.Params{Kind: lsh.KindWeightedMinHash, K: testk, B: 64, Positional: true})
+	})
+	t.Run("invalid weight", func(t *testing.T) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, testk, testshw))
+		if err := h.AddWeighted([]byte("foo"), -1); err != lsh.ErrInvalidWeight {
+			t.Errorf("expected invalid weight error, got %v", err)
+		}
+		if err := h.AddWeighted([]byte("foo"), math.NaN()); err != lsh.ErrInvalidWeight {
+			t.Errorf("expected invalid weight error, got %v", err)
+		}
+	})
+}
+
+func BenchmarkHash(b *testing.B) {
+	b.Run("char", func(b *testing.B) {
+		h, _ := NewHasher[[]byte](NewConfig(testh, 64, testshc))
+		lsh.BenchMe(b, h)
+	})
+}
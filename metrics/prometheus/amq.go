@@ -17,6 +17,10 @@ func (mw *mwAMQ) Capacity(cap uint64) {
 	amqCap.WithLabelValues(mw.name).Set(float64(cap))
 }
 
+func (mw *mwAMQ) FPP(fpp float64) {
+	amqFPP.WithLabelValues(mw.name).Set(fpp)
+}
+
 func (mw *mwAMQ) Set(err error) error {
 	result := "success"
 	if err != nil {
@@ -52,6 +56,11 @@ func init() {
 		Help: "Indicates how many items filter may contain.",
 	}, []string{"name"})
 
+	amqFPP = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "amq_fpp",
+		Help: "Indicates estimated false positive probability of the filter at the current fill.",
+	}, []string{"name"})
+
 	amqSet = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "amq_set",
 		Help: "Indicates how many times new items was set to the filter.",
@@ -67,11 +76,11 @@ func init() {
 		Help: "Indicates how many times filter was checked and check result (positive/negative).",
 	}, []string{"name", "result"})
 
-	prometheus.MustRegister(amqCap, amqSet, amqUnset, amqContains)
+	prometheus.MustRegister(amqCap, amqFPP, amqSet, amqUnset, amqContains)
 }
 
 var (
-	amqCap                        *prometheus.GaugeVec
+	amqCap, amqFPP                *prometheus.GaugeVec
 	amqSet, amqUnset, amqContains *prometheus.CounterVec
 
 	_ = NewAMQ
@@ -12,9 +12,19 @@ type base[T byteseq.Q] struct {
 	ctable map[rune]struct{}
 	cbuf   []byte
 	spc    []int
+	norm   Normalizer
+	nbuf   []byte
+	st     stream
 }
 
-func (b *base[T]) init() {
+func (b *base[T]) init(normalizers []Normalizer) {
+	switch len(normalizers) {
+	case 0:
+	case 1:
+		b.norm = normalizers[0]
+	default:
+		b.norm = NewPipeline(normalizers...)
+	}
 	if b.ctable == nil {
 		b.ctable = make(map[rune]struct{})
 	}
@@ -26,6 +36,10 @@ func (b *base[T]) init() {
 
 func (b *base[T]) clean(s T, collapseSpaces bool) []byte {
 	ss := byteseq.Q2S(s)
+	if b.norm != nil {
+		b.nbuf = b.norm.AppendNormalize(b.nbuf[:0], byteseq.Q2B(s))
+		ss = byteseq.Q2S(b.nbuf)
+	}
 	b.spc = append(b.spc, 0)
 	var space, pspace bool
 	for i, c := range ss {
@@ -56,6 +70,7 @@ func (b *base[T]) clean(s T, collapseSpaces bool) []byte {
 func (b *base[T]) reset() {
 	b.cbuf = b.cbuf[:0]
 	b.spc = b.spc[:0]
+	b.resetStream()
 }
 
 func spcp(p int) int {
@@ -1,7 +1,6 @@
 package shingle
 
 import (
-	"fmt"
 	"unicode/utf8"
 
 	"github.com/koykov/byteseq"
@@ -13,9 +12,11 @@ type char[T byteseq.Q] struct {
 	w []uint64
 }
 
-func NewChar[T byteseq.Q](k uint64, cleanSet string) Shingler[T] {
+// NewChar makes char shingler of size k. Runes of cleanSet removes from the text. Optional normalizers apply
+// to the text before cleaning (see Normalizer).
+func NewChar[T byteseq.Q](k uint64, cleanSet string, normalizers ...Normalizer) Shingler[T] {
 	sh := &char[T]{base: base[T]{cset: cleanSet}, k: k}
-	sh.init()
+	sh.init(normalizers)
 	return sh
 }
 
@@ -37,6 +38,8 @@ func (sh *char[T]) AppendShingle(dst []T, s T) []T {
 	}
 	bl := uint64(len(b))
 	_ = b[bl-1]
+	// offsets of runes of the current text only
+	sh.w = sh.w[:0]
 	for i := uint64(0); i < bl; {
 		_, l := utf8.DecodeRune(b[i:])
 		ul := uint64(l)
@@ -46,11 +49,6 @@ func (sh *char[T]) AppendShingle(dst []T, s T) []T {
 	lo, hi := uint64(0), sh.k
 	_, _ = sh.w[len(sh.w)-1], sc[len(sc)-1]
 	for hi < uint64(len(sh.w)) {
-		if sh.w[hi] == 0 {
-			fmt.Println(string(sh.base.cbuf))
-			fmt.Println(lo, hi)
-			fmt.Printf("%+v\n", sh.w)
-		}
 		dst = append(dst, sc[sh.w[lo]:sh.w[hi]])
 		lo++
 		hi++
@@ -68,6 +66,8 @@ func (sh *char[T]) Each(s T, fn func(T)) {
 	}
 	bl := uint64(len(b))
 	_ = b[bl-1]
+	// offsets of runes of the current text only
+	sh.w = sh.w[:0]
 	for i := uint64(0); i < bl; {
 		_, l := utf8.DecodeRune(b[i:])
 		ul := uint64(l)
@@ -88,3 +88,46 @@ func (sh *char[T]) Reset() {
 	sh.base.reset()
 	sh.w = sh.w[:0]
 }
+
+func (sh *char[T]) AppendChunk(dst []T, chunk T) []T {
+	return sh.appendChunk(dst, byteseq.Q2B(chunk), false)
+}
+
+func (sh *char[T]) Flush(dst []T) []T {
+	return sh.appendChunk(dst, nil, true)
+}
+
+func (sh *char[T]) appendChunk(dst []T, chunk []byte, final bool) []T {
+	var off int
+	if sh.st.next > 0 {
+		off = int(sh.w[sh.st.next])
+	}
+	b := sh.feed(off, chunk, final, false)
+	sc := byteseq.B2Q[T](b)
+	sh.w = sh.w[:0]
+	for i := 0; i < len(b); {
+		_, l := utf8.DecodeRune(b[i:])
+		sh.w = append(sh.w, uint64(i))
+		i += l
+	}
+	sh.w = append(sh.w, uint64(len(b)))
+
+	// emit all k-grams of the window, last k-1 runes stay in the window for the next chunk
+	if n, k := len(sh.w)-1, int(sh.k); k > 0 {
+		lo := 0
+		for ; lo+k <= n; lo++ {
+			dst = append(dst, sc[sh.w[lo]:sh.w[lo+k]])
+		}
+		if lo > 0 {
+			sh.st.next, sh.st.emitted = lo, true
+		}
+	}
+	if final {
+		if !sh.st.emitted {
+			// the whole text is shorter than k
+			dst = append(dst, sc)
+		}
+		sh.st.done = true
+	}
+	return dst
+}
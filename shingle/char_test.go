@@ -103,6 +103,25 @@ func TestChar(t *testing.T) {
 			})
 		})
 	}
+	t.Run("reuse", func(t *testing.T) {
+		// shingler without reset shingles the accumulated text, offsets of runes of previous calls must not pile up
+		for i := 1; i < len(cstages); i++ {
+			a, b := cstages[i-1].text, cstages[i].text
+			expect := NewChar[string](3, "").Shingle(a + b)
+			sh := NewChar[string](3, "")
+			_ = sh.Shingle(a)
+			if r := sh.Shingle(b); !sheq(r, expect) {
+				t.Errorf("expected %+v, got %+v", expect, r)
+			}
+			sh = NewChar[string](3, "")
+			sh.Each(a, func(string) {})
+			var r []string
+			sh.Each(b, func(s string) { r = append(r, s) })
+			if !sheq(r, expect) {
+				t.Errorf("expected %+v, got %+v", expect, r)
+			}
+		}
+	})
 }
 
 func BenchmarkChar(b *testing.B) {
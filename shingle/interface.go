@@ -8,3 +8,25 @@ type Shingler[T byteseq.Q] interface {
 	Each(s T, fn func(T))
 	Reset()
 }
+
+// HashShingler describes shingler that produces 64-bit hash sums of shingles directly, without intermediate
+// substrings. LSH hashers use these hash sums instead of own hash algorithm, so equal shingles must produce equal hash
+// sums regardless of the text around them.
+type HashShingler[T byteseq.Q] interface {
+	Shingler[T]
+	// AppendHashes appends hash sums of shingles of s to dst.
+	AppendHashes(dst []uint64, s T) []uint64
+	// Sum64 returns hash sum of the shingle produced by the shingler (e.g. by AppendShingle or AppendChunk).
+	Sum64(shingle T) uint64
+}
+
+// StreamShingler describes shingler that takes text by chunks, e.g. read from io.Reader. Shingles spanning chunks
+// boundaries produce as if the text was solid.
+type StreamShingler[T byteseq.Q] interface {
+	Shingler[T]
+	// AppendChunk appends shingles completed by the next chunk of text to dst. Shingles refer to internal buffers and
+	// remain valid until the next call.
+	AppendChunk(dst []T, chunk T) []T
+	// Flush appends the rest of shingles to dst and finishes the text. Next chunk starts a new text.
+	Flush(dst []T) []T
+}
@@ -0,0 +1,200 @@
+package shingle
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalizer transforms text before shingling, so that texts differing only in case, accents, etc. produce equal
+// shingles.
+// Normalizers keep internal buffers and aren't thread-safe, like shinglers.
+type Normalizer interface {
+	// AppendNormalize appends normalized s to dst.
+	AppendNormalize(dst, s []byte) []byte
+}
+
+type pipeline struct {
+	list       []Normalizer
+	buf0, buf1 []byte
+}
+
+// NewPipeline makes normalizer that applies given normalizers one by one.
+func NewPipeline(list ...Normalizer) Normalizer {
+	return &pipeline{list: list}
+}
+
+func (p *pipeline) AppendNormalize(dst, s []byte) []byte {
+	if len(p.list) == 0 {
+		return append(dst, s...)
+	}
+	src := s
+	for i := 0; i < len(p.list)-1; i++ {
+		p.buf0 = p.list[i].AppendNormalize(p.buf0[:0], src)
+		src = p.buf0
+		p.buf0, p.buf1 = p.buf1, p.buf0
+	}
+	return p.list[len(p.list)-1].AppendNormalize(dst, src)
+}
+
+type lowercase struct{}
+
+// NewLowercase makes normalizer that converts text to lower case.
+func NewLowercase() Normalizer {
+	return lowercase{}
+}
+
+func (lowercase) AppendNormalize(dst, s []byte) []byte {
+	for len(s) > 0 {
+		r, l := utf8.DecodeRune(s)
+		if r < utf8.RuneSelf {
+			if 'A' <= r && r <= 'Z' {
+				r += 'a' - 'A'
+			}
+			dst = append(dst, byte(r))
+		} else {
+			dst = utf8.AppendRune(dst, unicode.ToLower(r))
+		}
+		s = s[l:]
+	}
+	return dst
+}
+
+type transformer struct {
+	t transform.Transformer
+}
+
+// NewCaseFold makes normalizer that applies Unicode case folding (e.g. "Straße" -> "strasse"). Case folding is more
+// aggressive than lowercasing and is intended for case-insensitive comparison.
+func NewCaseFold() Normalizer {
+	return &transformer{t: cases.Fold()}
+}
+
+// NewNFC makes normalizer that applies Unicode canonical composition.
+func NewNFC() Normalizer {
+	return &transformer{t: norm.NFC}
+}
+
+// NewNFKC makes normalizer that applies Unicode compatibility composition (e.g. "ﬁ" -> "fi", "①" -> "1").
+func NewNFKC() Normalizer {
+	return &transformer{t: norm.NFKC}
+}
+
+// NewStripAccents makes normalizer that removes diacritical marks (e.g. "Café" -> "Cafe").
+func NewStripAccents() Normalizer {
+	return &transformer{t: transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)}
+}
+
+func (n *transformer) AppendNormalize(dst, s []byte) []byte {
+	n.t.Reset()
+	dst = slices.Grow(dst, len(s)+utf8.UTFMax)
+	for {
+		nDst, nSrc, err := n.t.Transform(dst[len(dst):cap(dst)], s, true)
+		dst, s = dst[:len(dst)+nDst], s[nSrc:]
+		if err != transform.ErrShortDst {
+			if err != nil {
+				// keep the rest of text as is
+				dst = append(dst, s...)
+			}
+			return dst
+		}
+		dst = slices.Grow(dst, len(s)*2+utf8.UTFMax)
+	}
+}
+
+type maskNumbers struct {
+	mask rune
+}
+
+// NewMaskNumbers makes normalizer that replaces each sequence of digits with mask rune (e.g. "2 of 15" -> "# of #"
+// for mask '#'). Thus, texts differing only in numbers produce equal shingles.
+func NewMaskNumbers(mask rune) Normalizer {
+	return maskNumbers{mask: mask}
+}
+
+func (n maskNumbers) AppendNormalize(dst, s []byte) []byte {
+	var digit bool
+	for len(s) > 0 {
+		r, l := utf8.DecodeRune(s)
+		s = s[l:]
+		if unicode.IsDigit(r) {
+			if !digit {
+				dst = utf8.AppendRune(dst, n.mask)
+			}
+			digit = true
+			continue
+		}
+		digit = false
+		dst = utf8.AppendRune(dst, r)
+	}
+	return dst
+}
+
+type stopwords struct {
+	set map[string]struct{}
+}
+
+// NewStopwords makes normalizer that removes words of given lists (see StopwordsEnglish, StopwordsRussian, etc.).
+// Words compare as is, so put lowercase or case fold normalizer before stopwords. Punctuation around the word
+// ignores on comparison.
+func NewStopwords(lists ...[]string) Normalizer {
+	n := stopwords{set: make(map[string]struct{})}
+	for _, list := range lists {
+		for _, w := range list {
+			n.set[w] = struct{}{}
+		}
+	}
+	return n
+}
+
+func (n stopwords) AppendNormalize(dst, s []byte) []byte {
+	var off, words int
+	for i := 0; i <= len(s); {
+		var (
+			r = ' '
+			l = 1
+		)
+		if i < len(s) {
+			r, l = utf8.DecodeRune(s[i:])
+		}
+		if !unicode.IsSpace(r) {
+			i += l
+			continue
+		}
+		if word := s[off:i]; len(word) > 0 {
+			if _, ok := n.set[string(trimPunct(word))]; !ok {
+				if words > 0 {
+					dst = append(dst, ' ')
+				}
+				dst = append(dst, word...)
+				words++
+			}
+		}
+		i += l
+		off = i
+	}
+	return dst
+}
+
+func trimPunct(b []byte) []byte {
+	for len(b) > 0 {
+		r, l := utf8.DecodeRune(b)
+		if !unicode.IsPunct(r) {
+			break
+		}
+		b = b[l:]
+	}
+	for len(b) > 0 {
+		r, l := utf8.DecodeLastRune(b)
+		if !unicode.IsPunct(r) {
+			break
+		}
+		b = b[:len(b)-l]
+	}
+	return b
+}
@@ -0,0 +1,64 @@
+package shingle
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizer(t *testing.T) {
+	stages := []struct {
+		name   string
+		norm   Normalizer
+		text   string
+		expect string
+	}{
+		{"lowercase", NewLowercase(), "Hello, МИР!", "hello, мир!"},
+		{"casefold", NewCaseFold(), "Straße ΣΟΦΙΑ", "strasse σοφια"},
+		{"nfc", NewNFC(), "Café", "Café"},
+		{"nfkc", NewNFKC(), "ﬁle ① Ｈｅｌｌｏ", "file 1 Hello"},
+		{"accents", NewStripAccents(), "Café crème brûlée, ёлка", "Cafe creme brulee, елка"},
+		{"numbers", NewMaskNumbers('#'), "2 of 15, v1.25", "# of #, v#.#"},
+		{"stopwords", NewStopwords(StopwordsEnglish), "the quick fox,  and the dog.", "quick fox, dog."},
+		{"stopwords multi", NewStopwords(StopwordsEnglish, StopwordsRussian), "кот и the dog", "кот dog"},
+		{"stopwords empty", NewStopwords(StopwordsEnglish), "the and of", ""},
+		{"pipeline", NewPipeline(NewNFKC(), NewCaseFold(), NewStripAccents(), NewStopwords(StopwordsFrench),
+			NewMaskNumbers('0')), "Le Café de ２０２４", "cafe 0"},
+		{"pipeline empty", NewPipeline(), "Foo", "Foo"},
+	}
+	for _, st := range stages {
+		t.Run(st.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ { // check buffers reuse
+				if r := string(st.norm.AppendNormalize(nil, []byte(st.text))); r != st.expect {
+					t.Errorf("expected '%s', got '%s'", st.expect, r)
+				}
+			}
+			if r := string(st.norm.AppendNormalize([]byte("prefix:"), []byte(st.text))); r != "prefix:"+st.expect {
+				t.Errorf("expected '%s', got '%s'", "prefix:"+st.expect, r)
+			}
+		})
+	}
+	t.Run("shingler", func(t *testing.T) {
+		shc := NewChar[string](3, "", NewLowercase(), NewStripAccents())
+		a := slices.Clone(shc.Shingle("Café"))
+		shc.Reset()
+		if b := shc.Shingle("cafe"); !slices.Equal(a, b) {
+			t.Errorf("expected equal shingles, got %v and %v", a, b)
+		}
+		shw := NewWord[string](2, ",.", NewLowercase(), NewStopwords(StopwordsEnglish))
+		if r := shw.Shingle("The cat and THE Dog."); !slices.Equal(r, []string{"cat dog"}) {
+			t.Errorf("expected [cat dog], got %v", r)
+		}
+	})
+}
+
+func BenchmarkNormalizer(b *testing.B) {
+	text := []byte("Lorem ipsum dolor sit amet, consectetur adipiscing elit. Café crème brûlée 2024 Straße.")
+	norm := NewPipeline(NewNFKC(), NewCaseFold(), NewStripAccents(), NewStopwords(StopwordsEnglish), NewMaskNumbers('0'))
+	var buf []byte
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = norm.AppendNormalize(buf[:0], text)
+	}
+}
@@ -0,0 +1,91 @@
+package shingle
+
+import (
+	"math/bits"
+	"unicode/utf8"
+
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk"
+)
+
+type rollingChar[T byteseq.Q] struct {
+	char[T]
+	r []rune
+}
+
+// NewRollingChar makes char shingler of size k that also produces hash sums of shingles (see HashShingler). Hash sums
+// calculate using rolling hash (cyclic polynomial, aka buzhash) over runes of the text, so each next k-gram costs O(1)
+// instead of hashing the whole shingle. Shingles themselves are the same as NewChar produces.
+func NewRollingChar[T byteseq.Q](k uint64, cleanSet string, normalizers ...Normalizer) HashShingler[T] {
+	sh := &rollingChar[T]{char: char[T]{base: base[T]{cset: cleanSet}, k: k}}
+	sh.init(normalizers)
+	return sh
+}
+
+func (sh *rollingChar[T]) AppendHashes(dst []uint64, s T) []uint64 {
+	// hash sums don't refer to cleaned text, so the buffers may be truncated back after hashing
+	off, spcoff := len(sh.cbuf), len(sh.spc)
+	b := sh.clean(s, false)[off:]
+	sh.r = sh.r[:0]
+	for len(b) > 0 {
+		r, l := utf8.DecodeRune(b)
+		sh.r = append(sh.r, r)
+		b = b[l:]
+	}
+	sh.cbuf, sh.spc = sh.cbuf[:off], sh.spc[:spcoff]
+
+	k := int(sh.k)
+	if len(sh.r) <= k || k == 0 {
+		var h uint64
+		for i := 0; i < len(sh.r); i++ {
+			h = bits.RotateLeft64(h, 1) ^ buzsum(sh.r[i])
+		}
+		return append(dst, buzfin(h))
+	}
+	var h uint64
+	for i := 0; i < k; i++ {
+		h = bits.RotateLeft64(h, 1) ^ buzsum(sh.r[i])
+	}
+	dst = append(dst, buzfin(h))
+	for i := k; i < len(sh.r); i++ {
+		// roll out leading rune (rotated k times since it was added) and roll in the next one
+		h = bits.RotateLeft64(h, 1) ^ bits.RotateLeft64(buzsum(sh.r[i-k]), k) ^ buzsum(sh.r[i])
+		dst = append(dst, buzfin(h))
+	}
+	return dst
+}
+
+func (sh *rollingChar[T]) Sum64(shingle T) uint64 {
+	var h uint64
+	for _, r := range byteseq.Q2S(shingle) {
+		h = bits.RotateLeft64(h, 1) ^ buzsum(r)
+	}
+	return buzfin(h)
+}
+
+func (sh *rollingChar[T]) Reset() {
+	sh.char.Reset()
+	sh.r = sh.r[:0]
+}
+
+// Random values of the first 256 runes. Rest of runes mix on the fly.
+var buztable = func() (t [256]uint64) {
+	x := uint64(0x2545f4914f6cdd1d)
+	for i := 0; i < len(t); i++ {
+		x += 0x9e3779b97f4a7c15
+		t[i] = pbtk.Fmix64(x)
+	}
+	return
+}()
+
+func buzsum(r rune) uint64 {
+	if uint32(r) < uint32(len(buztable)) {
+		return buztable[r]
+	}
+	return pbtk.Fmix64(uint64(r) ^ 0xc2b2ae3d27d4eb4f)
+}
+
+// buzfin breaks linear structure of cyclic polynomial hash, since LSH hashers derive K hash sums from it.
+func buzfin(h uint64) uint64 {
+	return pbtk.Fmix64(h ^ 0x9e3779b97f4a7c15)
+}
@@ -0,0 +1,62 @@
+package shingle
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRollingChar(t *testing.T) {
+	texts := []string{
+		"",
+		"ab",
+		"abc",
+		"Stock markets hit record highs!",
+		"Привет, мир! 你好，世界 abcabcabc",
+	}
+	for _, k := range []uint64{0, 1, 3, 5} {
+		sh := NewRollingChar[string](k, "!,", NewLowercase())
+		for _, text := range texts {
+			var shingles []string
+			for _, s := range sh.Shingle(text) {
+				shingles = append(shingles, strings.Clone(s)) // shingles refer to internal buffer
+			}
+			sh.Reset()
+			hashes := sh.AppendHashes(nil, text)
+			if len(hashes) != len(shingles) {
+				t.Fatalf("k=%d '%s': expected %d hash sums, got %d", k, text, len(shingles), len(hashes))
+			}
+			// hash sum of each shingle must be equal to hash sum of the same k-gram rolled in another text
+			for i, s := range shingles {
+				if h := sh.AppendHashes(nil, s); len(h) != 1 || h[0] != hashes[i] || sh.Sum64(s) != hashes[i] {
+					t.Errorf("k=%d '%s': hash sum mismatch of shingle '%s'", k, text, s)
+				}
+			}
+			// buffers reuse without reset
+			if again := sh.AppendHashes(nil, text); !slices.Equal(again, hashes) {
+				t.Errorf("k=%d '%s': hash sums differ on second call", k, text)
+			}
+			sh.Reset()
+		}
+	}
+	t.Run("distinct", func(t *testing.T) {
+		sh := NewRollingChar[string](4, "")
+		hashes := sh.AppendHashes(nil, "abcdefghijklmnopqrstuvwxyz0123456789")
+		slices.Sort(hashes)
+		if len(slices.Compact(hashes)) != 33 {
+			t.Error("expected distinct hash sums of distinct shingles")
+		}
+	})
+}
+
+func BenchmarkRollingChar(b *testing.B) {
+	text := "Tech giant Apple - after months of speculation - finally unveiled its revolutionary M4 AI chip at WWDC 2024."
+	sh := NewRollingChar[string](5, "")
+	var buf []uint64
+	b.SetBytes(int64(len(text)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = sh.AppendHashes(buf[:0], text)
+	}
+}
@@ -0,0 +1,47 @@
+package shingle
+
+// Stopwords lists to use with NewStopwords normalizer.
+var (
+	StopwordsEnglish = []string{
+		"a", "about", "above", "after", "again", "against", "all", "am", "an", "and", "any", "are", "as", "at", "be",
+		"because", "been", "before", "being", "below", "between", "both", "but", "by", "can", "could", "did", "do",
+		"does", "doing", "down", "during", "each", "few", "for", "from", "further", "had", "has", "have", "having", "he",
+		"her", "here", "hers", "herself", "him", "himself", "his", "how", "i", "if", "in", "into", "is", "it", "its",
+		"itself", "just", "me", "more", "most", "my", "myself", "no", "nor", "not", "now", "of", "off", "on", "once",
+		"only", "or", "other", "our", "ours", "ourselves", "out", "over", "own", "same", "she", "should", "so", "some",
+		"such", "than", "that", "the", "their", "theirs", "them", "themselves", "then", "there", "these", "they",
+		"this", "those", "through", "to", "too", "under", "until", "up", "very", "was", "we", "were", "what", "when",
+		"where", "which", "while", "who", "whom", "why", "will", "with", "would", "you", "your", "yours", "yourself",
+		"yourselves",
+	}
+	StopwordsRussian = []string{
+		"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас", "весь", "во", "вот", "все",
+		"всего", "всех", "вы", "где", "да", "даже", "для", "до", "его", "ее", "её", "если", "есть", "еще", "ещё", "же",
+		"за", "здесь", "и", "из", "или", "им", "их", "к", "как", "ко", "когда", "кто", "ли", "либо", "мне", "может",
+		"мы", "на", "над", "надо", "наш", "не", "него", "нее", "неё", "нет", "ни", "них", "но", "ну", "о", "об",
+		"однако", "он", "она", "они", "оно", "от", "очень", "по", "под", "при", "с", "со", "так", "также", "такой",
+		"там", "те", "тем", "то", "того", "тоже", "той", "только", "том", "ты", "у", "уже", "хотя", "чего", "чей",
+		"чем", "что", "чтобы", "чье", "чья", "эта", "эти", "это", "я",
+	}
+	StopwordsGerman = []string{
+		"aber", "alle", "als", "also", "am", "an", "auch", "auf", "aus", "bei", "bin", "bis", "bist", "da", "damit",
+		"dann", "das", "dass", "dem", "den", "der", "des", "dich", "die", "dir", "doch", "du", "durch", "ein", "eine",
+		"einem", "einen", "einer", "eines", "er", "es", "für", "hat", "hatte", "ich", "ihm", "ihn", "ihr", "im", "in",
+		"ist", "ja", "kann", "kein", "man", "mich", "mir", "mit", "nach", "nicht", "noch", "nur", "ob", "oder", "sich",
+		"sie", "sind", "so", "über", "um", "und", "uns", "unter", "vom", "von", "vor", "war", "was", "wenn", "wer",
+		"wie", "wir", "wird", "zu", "zum", "zur",
+	}
+	StopwordsFrench = []string{
+		"à", "au", "aux", "avec", "ce", "ces", "cette", "dans", "de", "des", "du", "elle", "elles", "en", "est", "et",
+		"été", "eu", "il", "ils", "je", "la", "le", "les", "leur", "leurs", "lui", "ma", "mais", "me", "même", "mes",
+		"moi", "mon", "ne", "nos", "notre", "nous", "on", "ou", "où", "par", "pas", "pour", "qu", "que", "qui", "sa",
+		"se", "ses", "son", "sont", "sur", "ta", "te", "tes", "toi", "ton", "tu", "un", "une", "vos", "votre", "vous",
+	}
+	StopwordsSpanish = []string{
+		"a", "al", "algo", "como", "con", "cuando", "de", "del", "desde", "donde", "el", "él", "ella", "ellos", "en",
+		"entre", "era", "es", "esa", "ese", "esta", "está", "este", "esto", "fue", "ha", "hay", "la", "las", "le",
+		"les", "lo", "los", "más", "me", "mi", "muy", "ni", "no", "nos", "o", "para", "pero", "por", "porque", "que",
+		"qué", "se", "si", "sí", "sin", "sobre", "su", "sus", "también", "te", "tu", "un", "una", "uno", "y", "ya",
+		"yo",
+	}
+)
@@ -0,0 +1,73 @@
+package shingle
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/koykov/byteseq"
+)
+
+// State of text streaming.
+type stream struct {
+	carry   []byte // raw tail of the text after the last whitespace, waits for the next chunk
+	next    int    // first unit (rune or token) of the window the next shingle starts with
+	emitted bool   // at least one shingle of the text was emitted
+	done    bool   // text was flushed
+}
+
+// feed prepares the window (cbuf) of the stream: drops first off bytes of the window (text of emitted shingles),
+// appends chunk to the carry and moves cleaned part of the carry up to the last whitespace to the window. The whole
+// carry moves to the window if final is true.
+//
+// Text splits by whitespace, so normalizers and tokenizers never see a part of the word.
+func (b *base[T]) feed(off int, chunk []byte, final, collapseSpaces bool) []byte {
+	if b.st.done {
+		off = len(b.cbuf)
+		b.st.emitted, b.st.done = false, false
+	}
+	b.st.next = 0
+	b.cbuf = b.cbuf[:copy(b.cbuf, b.cbuf[off:])]
+	b.spc = b.spc[:0]
+
+	b.st.carry = append(b.st.carry, chunk...)
+	cut := len(b.st.carry)
+	if !final {
+		cut = 0
+		if i := bytes.LastIndexFunc(b.st.carry, unicode.IsSpace); i >= 0 {
+			_, l := utf8.DecodeRune(b.st.carry[i:])
+			cut = i + l
+		}
+	}
+	if cut == 0 {
+		return b.cbuf
+	}
+	wlen := len(b.cbuf)
+	b.clean(byteseq.B2Q[T](b.st.carry[:cut]), collapseSpaces)
+	b.st.carry = b.st.carry[:copy(b.st.carry, b.st.carry[cut:])]
+	if collapseSpaces && wlen > 0 && b.cbuf[wlen-1] == ' ' {
+		// collapse spaces on the junction
+		i := wlen
+		for i < len(b.cbuf) && b.cbuf[i] == ' ' {
+			i++
+		}
+		b.cbuf = append(b.cbuf[:wlen], b.cbuf[i:]...)
+	}
+	if wlen > 0 && len(b.cbuf) > wlen {
+		// chunks were split by whitespace, so restore separator if cleaning dropped it
+		r0, _ := utf8.DecodeLastRune(b.cbuf[:wlen])
+		r1, _ := utf8.DecodeRune(b.cbuf[wlen:])
+		if !unicode.IsSpace(r0) && !unicode.IsSpace(r1) {
+			b.cbuf = append(b.cbuf, 0)
+			copy(b.cbuf[wlen+1:], b.cbuf[wlen:])
+			b.cbuf[wlen] = ' '
+		}
+	}
+	return b.cbuf
+}
+
+func (b *base[T]) resetStream() {
+	b.st.carry = b.st.carry[:0]
+	b.st.next = 0
+	b.st.emitted, b.st.done = false, false
+}
@@ -0,0 +1,98 @@
+package shingle
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	texts := []string{
+		"",
+		"foo",
+		"Stock markets hit record highs!",
+		"NASA's Mars rover discovers ancient riverbed - scientists thrilled!",
+		"Привет,  мир! Café crème brûlée 2024,   the end.",
+		"Go语言 我爱北京 hello 天 world",
+	}
+	shinglers := []struct {
+		name string
+		new  func() StreamShingler[string]
+	}{
+		{"char/1", func() StreamShingler[string] { return NewChar[string](1, "").(StreamShingler[string]) }},
+		{"char/3", func() StreamShingler[string] { return NewChar[string](3, "").(StreamShingler[string]) }},
+		{"char/5/clean", func() StreamShingler[string] {
+			return NewChar[string](5, "!,-", NewLowercase()).(StreamShingler[string])
+		}},
+		{"char/0", func() StreamShingler[string] { return NewChar[string](0, "").(StreamShingler[string]) }},
+		{"rolling/4", func() StreamShingler[string] { return NewRollingChar[string](4, "").(StreamShingler[string]) }},
+		{"word/1", func() StreamShingler[string] { return NewWord[string](1, "").(StreamShingler[string]) }},
+		{"word/3", func() StreamShingler[string] { return NewWord[string](3, "!,-").(StreamShingler[string]) }},
+		{"word/2/stopwords", func() StreamShingler[string] {
+			return NewWord[string](2, "", NewLowercase(), NewStopwords(StopwordsEnglish)).(StreamShingler[string])
+		}},
+		{"word/2/uax29", func() StreamShingler[string] {
+			return NewWordTokenizer[string](2, "", NewUAX29Tokenizer()).(StreamShingler[string])
+		}},
+		{"word/2/cjk", func() StreamShingler[string] {
+			return NewWordTokenizer[string](2, "", NewCJKBigramTokenizer(nil)).(StreamShingler[string])
+		}},
+		{"skipgram/3/2", func() StreamShingler[string] {
+			return NewSkipGram[string](3, 2, "", nil).(StreamShingler[string])
+		}},
+	}
+	collect := func(dst []string, list []string) []string {
+		for _, s := range list {
+			dst = append(dst, strings.Clone(s)) // shingles refer to internal buffers
+		}
+		return dst
+	}
+	for _, s := range shinglers {
+		for _, chunksz := range []int{1, 2, 3, 7, 1000} {
+			t.Run(fmt.Sprintf("%s/chunk%d", s.name, chunksz), func(t *testing.T) {
+				sh := s.new()
+				for _, text := range texts {
+					expect := collect(nil, sh.Shingle(text))
+					sh.Reset()
+					// feed the text twice to check state of the stream after flush
+					for i := 0; i < 2; i++ {
+						var r []string
+						for off := 0; off < len(text); off += chunksz {
+							r = collect(r, sh.AppendChunk(nil, text[off:min(off+chunksz, len(text))]))
+						}
+						r = collect(r, sh.Flush(nil))
+						if !slices.Equal(r, expect) {
+							t.Errorf("'%s': expected %q, got %q", text, expect, r)
+						}
+					}
+					sh.Reset()
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkStream(b *testing.B) {
+	text := strings.Repeat("Tech giant Apple - after months of speculation - finally unveiled its revolutionary M4 AI chip. ", 100)
+	for _, st := range []struct {
+		name string
+		sh   StreamShingler[string]
+	}{
+		{"char", NewChar[string](3, "").(StreamShingler[string])},
+		{"word", NewWord[string](2, "").(StreamShingler[string])},
+	} {
+		b.Run(st.name, func(b *testing.B) {
+			var buf []string
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for off := 0; off < len(text); off += 512 {
+					buf = st.sh.AppendChunk(buf[:0], text[off:min(off+512, len(text))])
+				}
+				buf = st.sh.Flush(buf[:0])
+			}
+		})
+	}
+}
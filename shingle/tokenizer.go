@@ -0,0 +1,261 @@
+package shingle
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token represents position of the token in the text as [Lo, Hi) bytes range.
+type Token struct {
+	Lo, Hi int
+}
+
+// Tokenizer splits text to tokens (words) for word shingler.
+type Tokenizer interface {
+	// AppendTokens appends tokens of s to dst.
+	AppendTokens(dst []Token, s []byte) []Token
+}
+
+type whitespace struct{}
+
+// NewWhitespaceTokenizer makes tokenizer that splits text by whitespace characters. Punctuation stays glued to the
+// tokens. This is the default tokenizer of word shingler.
+func NewWhitespaceTokenizer() Tokenizer {
+	return whitespace{}
+}
+
+func (whitespace) AppendTokens(dst []Token, s []byte) []Token {
+	lo := -1
+	for i := 0; i < len(s); {
+		r, l := rune(s[i]), 1
+		if r >= utf8.RuneSelf {
+			r, l = utf8.DecodeRune(s[i:])
+		}
+		if unicode.IsSpace(r) {
+			if lo >= 0 {
+				dst = append(dst, Token{Lo: lo, Hi: i})
+				lo = -1
+			}
+		} else if lo < 0 {
+			lo = i
+		}
+		i += l
+	}
+	if lo >= 0 {
+		dst = append(dst, Token{Lo: lo, Hi: len(s)})
+	}
+	return dst
+}
+
+// Word boundary classes of runes (see https://unicode.org/reports/tr29/#Word_Boundaries).
+const (
+	wbOther = iota
+	wbALetter
+	wbNumeric
+	wbKatakana
+	wbIdeo // ideographs and hiragana, each rune is a separate token
+	wbExtendNumLet
+	wbExtend
+	wbMidLetter
+	wbMidNum
+	wbMidNumLet
+)
+
+type uax29 struct{}
+
+// NewUAX29Tokenizer makes tokenizer based on Unicode word boundaries rules (simplified UAX #29 without dictionary
+// segmentation). Punctuation and whitespace don't produce tokens, while words like "can't", "3.14" or "snake_case"
+// keep solid. Each ideograph makes a separate token.
+func NewUAX29Tokenizer() Tokenizer {
+	return uax29{}
+}
+
+func (uax29) AppendTokens(dst []Token, s []byte) []Token {
+	lo, prev := -1, wbOther
+	for i := 0; i < len(s); {
+		r, l := utf8.DecodeRune(s[i:])
+		c := wbClass(r)
+		if lo >= 0 {
+			// WB4: extend and format characters attach to the previous one
+			if c == wbExtend {
+				i += l
+				continue
+			}
+			// WB5, WB8-WB10, WB13, WB13a, WB13b
+			if wbJoins(prev, c) {
+				prev = c
+				i += l
+				continue
+			}
+			// WB6, WB7, WB11, WB12: letters/numbers separated by single middle character
+			if (c == wbMidLetter || c == wbMidNum || c == wbMidNumLet) && i+l < len(s) {
+				r1, l1 := utf8.DecodeRune(s[i+l:])
+				c1 := wbClass(r1)
+				if (prev == wbALetter && c1 == wbALetter && c != wbMidNum) ||
+					(prev == wbNumeric && c1 == wbNumeric && c != wbMidLetter) {
+					prev = c1
+					i += l + l1
+					continue
+				}
+			}
+			dst = append(dst, Token{Lo: lo, Hi: i})
+			lo = -1
+		}
+		switch c {
+		case wbIdeo:
+			dst = append(dst, Token{Lo: i, Hi: i + l})
+		case wbALetter, wbNumeric, wbKatakana, wbExtendNumLet:
+			lo, prev = i, c
+		}
+		i += l
+	}
+	if lo >= 0 {
+		dst = append(dst, Token{Lo: lo, Hi: len(s)})
+	}
+	return dst
+}
+
+func wbJoins(a, b int) bool {
+	switch {
+	case (a == wbALetter || a == wbNumeric) && (b == wbALetter || b == wbNumeric):
+		return true
+	case a == wbKatakana && b == wbKatakana:
+		return true
+	case a == wbExtendNumLet || b == wbExtendNumLet:
+		return wbWordlike(a) && wbWordlike(b)
+	}
+	return false
+}
+
+func wbWordlike(c int) bool {
+	return c == wbALetter || c == wbNumeric || c == wbKatakana || c == wbExtendNumLet
+}
+
+func wbClass(r rune) int {
+	switch r {
+	case '\'', '.', '\u2018', '\u2019', '\u2024', '\ufe52', '\uff07', '\uff0e':
+		return wbMidNumLet
+	case ':', '\u00b7', '\u0387', '\u05f4', '\u2027', '\ufe13', '\ufe55', '\uff1a':
+		return wbMidLetter
+	case ',', ';', '\u037e', '\u0589', '\u060c', '\u066c', '\ufe10', '\ufe14', '\ufe50', '\ufe54', '\uff0c', '\uff1b':
+		return wbMidNum
+	}
+	switch {
+	case r < utf8.RuneSelf:
+		switch {
+		case 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+			return wbALetter
+		case '0' <= r && r <= '9':
+			return wbNumeric
+		case r == '_':
+			return wbExtendNumLet
+		}
+		return wbOther
+	case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r):
+		return wbIdeo
+	case unicode.Is(unicode.Katakana, r):
+		return wbKatakana
+	case unicode.IsLetter(r):
+		return wbALetter
+	case unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	case unicode.In(r, unicode.Mn, unicode.Mc, unicode.Me, unicode.Cf):
+		return wbExtend
+	}
+	return wbOther
+}
+
+type regex struct {
+	re *regexp.Regexp
+}
+
+// NewRegexTokenizer makes tokenizer that considers each match of re as a token.
+// Caution! Regular expressions matching is much slower than other tokenizers.
+func NewRegexTokenizer(re *regexp.Regexp) Tokenizer {
+	return regex{re: re}
+}
+
+func (t regex) AppendTokens(dst []Token, s []byte) []Token {
+	for off := 0; off < len(s); {
+		loc := t.re.FindIndex(s[off:])
+		if loc == nil {
+			break
+		}
+		if loc[1] > loc[0] {
+			dst = append(dst, Token{Lo: off + loc[0], Hi: off + loc[1]})
+		}
+		if loc[1] == 0 {
+			// empty match at the current position, skip one rune to avoid infinite loop
+			_, l := utf8.DecodeRune(s[off:])
+			loc[1] = l
+		}
+		off += loc[1]
+	}
+	return dst
+}
+
+type cjkBigram struct {
+	base Tokenizer
+}
+
+// NewCJKBigramTokenizer makes tokenizer that splits runs of CJK characters (Han, Hiragana, Katakana, Hangul) to
+// overlapping bigrams, since these languages don't separate words by spaces. Rest of the text tokenizes by base
+// tokenizer (UAX #29 if base is nil). Single CJK character makes a unigram.
+func NewCJKBigramTokenizer(base Tokenizer) Tokenizer {
+	if base == nil {
+		base = NewUAX29Tokenizer()
+	}
+	return cjkBigram{base: base}
+}
+
+func (t cjkBigram) AppendTokens(dst []Token, s []byte) []Token {
+	var (
+		off  int      // start of non-CJK segment
+		p, q = -1, -1 // start of the previous and current CJK rune in the run
+	)
+	for i := 0; i < len(s); {
+		r, l := utf8.DecodeRune(s[i:])
+		if !isCJK(r) {
+			if q >= 0 {
+				if p < 0 {
+					dst = append(dst, Token{Lo: q, Hi: i})
+				}
+				p, q, off = -1, -1, i
+			}
+			i += l
+			continue
+		}
+		if q < 0 {
+			dst = t.appendBase(dst, s[off:i], off)
+		} else {
+			dst = append(dst, Token{Lo: q, Hi: i + l})
+			p = q
+		}
+		q = i
+		i += l
+	}
+	if q >= 0 {
+		if p < 0 {
+			dst = append(dst, Token{Lo: q, Hi: len(s)})
+		}
+		return dst
+	}
+	return t.appendBase(dst, s[off:], off)
+}
+
+func (t cjkBigram) appendBase(dst []Token, s []byte, off int) []Token {
+	n := len(dst)
+	dst = t.base.AppendTokens(dst, s)
+	for i := n; i < len(dst); i++ {
+		dst[i].Lo += off
+		dst[i].Hi += off
+	}
+	return dst
+}
+
+func isCJK(r rune) bool {
+	return r >= 0x1100 && (unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul))
+}
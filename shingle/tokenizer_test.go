@@ -0,0 +1,119 @@
+package shingle
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	stages := []struct {
+		name   string
+		tkn    Tokenizer
+		text   string
+		expect []string
+	}{
+		{"whitespace", NewWhitespaceTokenizer(), "  Hello,\tworld!\n foo ", []string{"Hello,", "world!", "foo"}},
+		{"whitespace empty", NewWhitespaceTokenizer(), " \t ", nil},
+		{"uax29", NewUAX29Tokenizer(), "Hello, world! Can't stop at 3.14 or 1,000.", []string{"Hello", "world", "Can't", "stop", "at", "3.14", "or", "1,000"}},
+		{"uax29 underscore", NewUAX29Tokenizer(), "snake_case x2 -- end.", []string{"snake_case", "x2", "end"}},
+		{"uax29 cyrillic", NewUAX29Tokenizer(), "Привет, мир-труд!", []string{"Привет", "мир", "труд"}},
+		{"uax29 ideographs", NewUAX29Tokenizer(), "Go语言", []string{"Go", "语", "言"}},
+		{"uax29 katakana", NewUAX29Tokenizer(), "カタカナ abc", []string{"カタカナ", "abc"}},
+		{"regex", NewRegexTokenizer(regexp.MustCompile(`[a-z]+`)), "abc 123 de-f", []string{"abc", "de", "f"}},
+		{"regex empty match", NewRegexTokenizer(regexp.MustCompile(`[0-9]*`)), "a1b22", []string{"1", "22"}},
+		{"cjk bigram", NewCJKBigramTokenizer(nil), "我爱北京 hello 天 world", []string{"我爱", "爱北", "北京", "hello", "天", "world"}},
+		{"cjk bigram mixed", NewCJKBigramTokenizer(nil), "foo東京bar", []string{"foo", "東京", "bar"}},
+		{"cjk bigram base", NewCJKBigramTokenizer(NewWhitespaceTokenizer()), "a, 한국어", []string{"a,", "한국", "국어"}},
+	}
+	for _, st := range stages {
+		t.Run(st.name, func(t *testing.T) {
+			b := []byte(st.text)
+			var r []string
+			for _, tok := range st.tkn.AppendTokens(nil, b) {
+				r = append(r, string(b[tok.Lo:tok.Hi]))
+			}
+			if !slices.Equal(r, st.expect) {
+				t.Errorf("expected %q, got %q", st.expect, r)
+			}
+		})
+	}
+}
+
+func TestWordTokenizer(t *testing.T) {
+	stages := []struct {
+		name   string
+		sh     Shingler[string]
+		text   string
+		expect []string
+	}{
+		{"uax29", NewWordTokenizer[string](2, "", NewUAX29Tokenizer()), "Hello,  world! Foo.", []string{"Hello world", "world Foo"}},
+		{"uax29 short", NewWordTokenizer[string](3, "", NewUAX29Tokenizer()), "Hello, world!", []string{"Hello world"}},
+		{"cjk", NewWordTokenizer[string](1, "", NewCJKBigramTokenizer(nil)), "东京 Tokyo", []string{"东京", "Tokyo"}},
+		{"skip-gram", NewSkipGram[string](2, 1, "", nil), "a b c d", []string{"a b", "a c", "b c", "b d", "c d"}},
+		{"skip-gram k3", NewSkipGram[string](3, 1, "", nil), "a b c d", []string{"a b c", "a b d", "a c d", "b c d"}},
+		{"skip-gram k3 skip2", NewSkipGram[string](3, 2, "", nil), "a b c d e", []string{
+			"a b c", "a b d", "a b e", "a c d", "a c e", "a d e", "b c d", "b c e", "b d e", "c d e",
+		}},
+		{"skip-gram no skip", NewSkipGram[string](2, 0, "", NewUAX29Tokenizer()), "a, b. c", []string{"a b", "b c"}},
+		{"skip-gram short", NewSkipGram[string](3, 2, "", nil), "a b", []string{"a b"}},
+	}
+	for _, st := range stages {
+		t.Run(st.name, func(t *testing.T) {
+			for i := 0; i < 2; i++ { // check buffers reuse
+				if r := st.sh.Shingle(st.text); !slices.Equal(r, st.expect) {
+					t.Errorf("expected %q, got %q", st.expect, r)
+				}
+				st.sh.Reset()
+			}
+			var r []string
+			st.sh.Each(st.text, func(s string) { r = append(r, s) })
+			if !slices.Equal(r, st.expect) {
+				t.Errorf("each: expected %q, got %q", st.expect, r)
+			}
+			st.sh.Reset()
+		})
+	}
+	t.Run("robustness", func(t *testing.T) {
+		// skip-grams of texts differing by one inserted word share more shingles than contiguous ones
+		a, b := "the quick brown fox jumps", "the quick red brown fox jumps"
+		common := func(sh Shingler[string]) int {
+			x := slices.Clone(sh.Shingle(a))
+			sh.Reset()
+			var n int
+			for _, s := range sh.Shingle(b) {
+				if slices.Contains(x, s) {
+					n++
+				}
+			}
+			sh.Reset()
+			return n
+		}
+		if c0, c1 := common(NewWord[string](2, "")), common(NewSkipGram[string](2, 1, "", nil)); c1 <= c0 {
+			t.Errorf("expected skip-grams to share more shingles, got %d <= %d", c1, c0)
+		}
+	})
+}
+
+func BenchmarkTokenizer(b *testing.B) {
+	text := []byte("Tech giant Apple - after months of speculation - finally unveiled its revolutionary M4 AI chip at WWDC 2024.")
+	for _, st := range []struct {
+		name string
+		tkn  Tokenizer
+	}{
+		{"whitespace", NewWhitespaceTokenizer()},
+		{"uax29", NewUAX29Tokenizer()},
+		{"regex", NewRegexTokenizer(regexp.MustCompile(`\w+`))},
+		{"cjk bigram", NewCJKBigramTokenizer(nil)},
+	} {
+		b.Run(st.name, func(b *testing.B) {
+			var buf []Token
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf = st.tkn.AppendTokens(buf[:0], text)
+			}
+		})
+	}
+}
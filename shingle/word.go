@@ -4,73 +4,180 @@ import "github.com/koykov/byteseq"
 
 type word[T byteseq.Q] struct {
 	base[T]
-	k uint64
+	k, skip uint64
+	tkn     Tokenizer
+	join    bool
+	toks    []Token
+	comb    []int
+	sbuf    []byte
+	ebuf    []T
 }
 
-func NewWord[T byteseq.Q](k uint64, cleanSet string) Shingler[T] {
-	sh := &word[T]{base: base[T]{cset: cleanSet}, k: k}
-	sh.init()
+// NewWord makes word shingler of size k. Text splits to words by whitespace characters. Runes of cleanSet removes
+// from the text. Optional normalizers apply to the text before cleaning (see Normalizer).
+func NewWord[T byteseq.Q](k uint64, cleanSet string, normalizers ...Normalizer) Shingler[T] {
+	return newWord[T](k, 0, cleanSet, nil, normalizers)
+}
+
+// NewWordTokenizer makes word shingler of size k that splits text to words by given tokenizer (see Tokenizer).
+// Words of shingle join with single space.
+func NewWordTokenizer[T byteseq.Q](k uint64, cleanSet string, tokenizer Tokenizer, normalizers ...Normalizer) Shingler[T] {
+	return newWord[T](k, 0, cleanSet, tokenizer, normalizers)
+}
+
+// NewSkipGram makes shingler of k-skip-grams: each shingle consists of k words in original order with at most skip
+// words skipped between them (e.g. 2-skip-1-grams of "a b c d" are "a b", "a c", "b c", "b d", "c d"). Skip-grams are
+// more robust to inserted/removed words than contiguous shingles. If tokenizer is nil, text splits by whitespace.
+// Words of shingle join with single space.
+func NewSkipGram[T byteseq.Q](k, skip uint64, cleanSet string, tokenizer Tokenizer, normalizers ...Normalizer) Shingler[T] {
+	sh := newWord[T](k, skip, cleanSet, tokenizer, normalizers)
+	sh.join = true
 	return sh
 }
 
-func (sh *word[T]) Shingle(s T) []T {
-	bcap := 1
-	if sh.k > 0 {
-		bcap = len(s) / 3 / int(sh.k)
+func newWord[T byteseq.Q](k, skip uint64, cleanSet string, tokenizer Tokenizer, normalizers []Normalizer) *word[T] {
+	sh := &word[T]{base: base[T]{cset: cleanSet}, k: max(k, 1), skip: skip, tkn: tokenizer}
+	if sh.tkn == nil {
+		sh.tkn = NewWhitespaceTokenizer()
 	}
-	buf := make([]T, 0, bcap)
+	// whitespace tokens of cleaned text are separated by single space, so shingles may refer to the text directly
+	_, ws := sh.tkn.(whitespace)
+	sh.join = !ws
+	sh.init(normalizers)
+	return sh
+}
+
+func (sh *word[T]) Shingle(s T) []T {
+	bcap := len(s) / 3 / int(sh.k)
+	buf := make([]T, 0, max(bcap, 1))
 	return sh.AppendShingle(buf, s)
 }
 
 func (sh *word[T]) AppendShingle(dst []T, s T) []T {
-	b := sh.clean(s, true)
-	sc := byteseq.B2Q[T](b)
-	if len(b) < 2 {
-		dst = append(dst, sc)
-		return dst
+	off := len(sh.cbuf)
+	b := sh.clean(s, true)[off:]
+	sh.toks = sh.tkn.AppendTokens(sh.toks[:0], b)
+	n, k := len(sh.toks), int(sh.k)
+	if n == 0 {
+		return append(dst, byteseq.B2Q[T](b))
 	}
-	lo, hi, i := 0, sh.k, 0
-	_, _ = sh.spc[len(sh.spc)-1], sc[len(sc)-1]
-	for ; hi < uint64(len(sh.spc)); i++ {
-		dst = trimq(dst, sc[sh.spc[lo]:sh.spc[hi]])
-		lo++
-		hi++
+	if n < k {
+		return sh.appendShingle(dst, b, sh.toks)
 	}
-	if i == 0 {
-		dst = append(dst, sc[sh.spc[lo]:])
-	}
-	return dst
+	return sh.appendShingles(dst, b, 0, n-k+1)
 }
 
-func (sh *word[T]) Each(s T, fn func(T)) {
-	b := sh.clean(s, true)
-	sc := byteseq.B2Q[T](b)
-	if len(b) < 2 {
-		fn(sc)
-		return
+func (sh *word[T]) AppendChunk(dst []T, chunk T) []T {
+	return sh.appendChunk(dst, byteseq.Q2B(chunk), false)
+}
+
+func (sh *word[T]) Flush(dst []T) []T {
+	return sh.appendChunk(dst, nil, true)
+}
+
+func (sh *word[T]) appendChunk(dst []T, chunk []byte, final bool) []T {
+	off := len(sh.cbuf)
+	if sh.st.next < len(sh.toks) {
+		off = sh.toks[sh.st.next].Lo
+	}
+	sh.sbuf = sh.sbuf[:0]
+	b := sh.feed(off, chunk, final, true)
+	sh.toks = sh.tkn.AppendTokens(sh.toks[:0], b)
+
+	// shingles starting at lo need k-1+skip next tokens, so the rest of shingles waits for the next chunk
+	n, k := len(sh.toks), int(sh.k)
+	lo, hi := 0, n-k-int(sh.skip)+1
+	if final {
+		hi = n - k + 1
 	}
-	lo, hi, i := 0, sh.k, 0
-	_, _ = sh.spc[len(sh.spc)-1], sc[len(sc)-1]
-	for ; hi < uint64(len(sh.spc)); i++ {
-		fn(sc[sh.spc[lo]:sh.spc[hi]])
-		lo++
-		hi++
+	if hi > lo {
+		dst = sh.appendShingles(dst, b, lo, hi)
+		sh.st.next, sh.st.emitted = hi, true
 	}
-	if i == 0 {
-		fn(sc[sh.spc[lo]:])
+	if final {
+		if !sh.st.emitted {
+			// the whole text is shorter than k words
+			if n == 0 {
+				dst = append(dst, byteseq.B2Q[T](b))
+			} else {
+				dst = sh.appendShingle(dst, b, sh.toks)
+			}
+		}
+		sh.st.done = true
 	}
+	return dst
 }
 
-func (sh *word[T]) Reset() {
-	sh.base.reset()
+// appendShingles appends shingles starting at tokens [lo, hi).
+func (sh *word[T]) appendShingles(dst []T, b []byte, lo, hi int) []T {
+	n, k := len(sh.toks), int(sh.k)
+	if sh.skip == 0 {
+		for i := lo; i < hi; i++ {
+			dst = sh.appendShingle(dst, b, sh.toks[i:i+k])
+		}
+		return dst
+	}
+
+	// k-skip-grams: for each first word enumerate combinations of k-1 words from the window of k-1+skip next words
+	for i := lo; i < hi; i++ {
+		whi := min(n-1, i+k-1+int(sh.skip))
+		sh.comb = append(sh.comb[:0], i)
+		for j := 1; j < k; j++ {
+			sh.comb = append(sh.comb, i+j)
+		}
+		for {
+			start := len(sh.sbuf)
+			for j := 0; j < k; j++ {
+				if j > 0 {
+					sh.sbuf = append(sh.sbuf, ' ')
+				}
+				t := sh.toks[sh.comb[j]]
+				sh.sbuf = append(sh.sbuf, b[t.Lo:t.Hi]...)
+			}
+			dst = append(dst, byteseq.B2Q[T](sh.sbuf[start:]))
+
+			// next combination
+			j := k - 1
+			for ; j > 0 && sh.comb[j] == whi-(k-1-j); j-- {
+			}
+			if j == 0 {
+				break
+			}
+			sh.comb[j]++
+			for j++; j < k; j++ {
+				sh.comb[j] = sh.comb[j-1] + 1
+			}
+		}
+	}
+	return dst
 }
 
-func trimq[T byteseq.Q](dst []T, s T) []T {
-	if s[0] == ' ' {
-		s = s[1:]
+// appendShingle appends shingle of contiguous tokens.
+func (sh *word[T]) appendShingle(dst []T, b []byte, toks []Token) []T {
+	if !sh.join {
+		return append(dst, byteseq.B2Q[T](b[toks[0].Lo:toks[len(toks)-1].Hi]))
 	}
-	if len(s) > 0 {
-		dst = append(dst, s)
+	start := len(sh.sbuf)
+	for i := 0; i < len(toks); i++ {
+		if i > 0 {
+			sh.sbuf = append(sh.sbuf, ' ')
+		}
+		sh.sbuf = append(sh.sbuf, b[toks[i].Lo:toks[i].Hi]...)
 	}
-	return dst
+	return append(dst, byteseq.B2Q[T](sh.sbuf[start:]))
+}
+
+func (sh *word[T]) Each(s T, fn func(T)) {
+	sh.ebuf = sh.AppendShingle(sh.ebuf[:0], s)
+	for i := 0; i < len(sh.ebuf); i++ {
+		fn(sh.ebuf[i])
+	}
+}
+
+func (sh *word[T]) Reset() {
+	sh.base.reset()
+	sh.toks = sh.toks[:0]
+	sh.sbuf = sh.sbuf[:0]
+	clear(sh.ebuf)
+	sh.ebuf = sh.ebuf[:0]
 }
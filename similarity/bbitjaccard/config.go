@@ -0,0 +1,30 @@
+package bbitjaccard
+
+import (
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk/lsh"
+)
+
+type Config[T byteseq.Q] struct {
+	// b-bit MinHash hasher with positional signatures (see bbitminhash and minhash.ModeOnePermutation).
+	// Mandatory param.
+	LSH lsh.Hasher[T]
+	// Size of elements universe (D) to correct estimation taking into account sizes of sets (see EstimateSizes).
+	// If this param omitted, universe considers infinitely large comparing to sets sizes, that is precise for hashed
+	// elements.
+	Universe uint64
+}
+
+func NewConfig[T byteseq.Q](lsh lsh.Hasher[T]) *Config[T] {
+	return &Config[T]{LSH: lsh}
+}
+
+func (c *Config[T]) WithUniverse(d uint64) *Config[T] {
+	c.Universe = d
+	return c
+}
+
+func (c *Config[T]) copy() *Config[T] {
+	cpy := *c
+	return &cpy
+}
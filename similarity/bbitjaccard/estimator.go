@@ -0,0 +1,140 @@
+package bbitjaccard
+
+import (
+	"math"
+	"sync"
+
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/similarity"
+)
+
+// Estimator of Jaccard similarity by b-bit MinHash signatures.
+type Estimator[T byteseq.Q] interface {
+	similarity.Estimator[T]
+	// EstimateSizes estimates similarity of precomputed signatures of sets with known sizes fa and fb. Sizes take
+	// into account only if config's Universe provided.
+	EstimateSizes(a, b *lsh.Signature, fa, fb uint64) (float64, error)
+}
+
+type estimator[T byteseq.Q] struct {
+	lsh.VectorPair[T]
+	conf *Config[T]
+	b    uint64
+	once sync.Once
+
+	err error
+}
+
+// NewEstimator makes Jaccard estimator with Li & König correction of accidental collisions of b-bit values.
+// See https://arxiv.org/abs/0910.3349 for details.
+func NewEstimator[T byteseq.Q](conf *Config[T]) (Estimator[T], error) {
+	e := &estimator[T]{conf: conf.copy()}
+	if e.once.Do(e.init); e.err != nil {
+		return nil, e.err
+	}
+	return e, nil
+}
+
+func (e *estimator[T]) Estimate(a, b T) (r float64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+
+	abuf, bbuf, err := e.VectorizePair(e.conf.LSH, a, b)
+	if err != nil {
+		return
+	}
+	r = e.estimate(abuf, bbuf, 0, 0)
+	return
+}
+
+func (e *estimator[T]) EstimateSignatures(a, b *lsh.Signature) (r float64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	if err = lsh.CompatibleHasher(e.conf.LSH, a, b); err != nil {
+		return
+	}
+	r = e.estimate(a.Values, b.Values, 0, 0)
+	return
+}
+
+func (e *estimator[T]) EstimateSizes(a, b *lsh.Signature, fa, fb uint64) (r float64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	if err = lsh.CompatibleHasher(e.conf.LSH, a, b); err != nil {
+		return
+	}
+	r = e.estimate(a.Values, b.Values, fa, fb)
+	return
+}
+
+// R = (P - C1) / (1 - C2), where P is a fraction of equal b-bit values.
+func (e *estimator[T]) estimate(abuf, bbuf []uint64, fa, fb uint64) float64 {
+	n := min(len(abuf), len(bbuf))
+	if n == 0 {
+		return 0
+	}
+	var eq float64
+	for i := 0; i < n; i++ {
+		if abuf[i] == bbuf[i] {
+			eq++
+		}
+	}
+	c1, c2 := e.corrections(fa, fb)
+	r := (eq/float64(n) - c1) / (1 - c2)
+	return max(0, min(1, r))
+}
+
+// corrections returns C1 and C2 terms of the collision probability P = C1 + (1 - C2) * R.
+func (e *estimator[T]) corrections(fa, fb uint64) (c1, c2 float64) {
+	d := e.conf.Universe
+	if d == 0 || fa == 0 || fb == 0 {
+		// infinite universe: b lower bits of different values are equal with probability 2^-b
+		c := math.Ldexp(1, -int(e.b))
+		return c, c
+	}
+	r1, r2 := float64(min(fa, d))/float64(d), float64(min(fb, d))/float64(d)
+	a1, a2 := e.a(r1), e.a(r2)
+	c1 = a1*r2/(r1+r2) + a2*r1/(r1+r2)
+	c2 = a1*r1/(r1+r2) + a2*r2/(r1+r2)
+	return
+}
+
+// A = r * (1-r)^(2^b-1) / (1 - (1-r)^(2^b))
+func (e *estimator[T]) a(r float64) float64 {
+	if r >= 1 {
+		return 0
+	}
+	l, n := math.Log1p(-r), math.Ldexp(1, int(e.b))
+	return r * math.Exp((n-1)*l) / -math.Expm1(n*l)
+}
+
+func (e *estimator[T]) Reset() {
+	e.VectorPair.Reset()
+	e.conf.LSH.Reset()
+}
+
+func (e *estimator[T]) init() {
+	if e.conf.LSH == nil {
+		e.err = similarity.ErrNoLSH
+		return
+	}
+	p, ok := e.conf.LSH.(lsh.Parametric)
+	if !ok {
+		e.err = similarity.ErrIncompatibleLSH
+		return
+	}
+	// b-bit values of K hashes mode aren't minwise sketch, so only positional signatures are comparable
+	params := p.Params()
+	if params.Kind != lsh.KindBBitMinHash || !params.Positional {
+		e.err = similarity.ErrIncompatibleLSH
+		return
+	}
+	e.b = params.B
+}
@@ -0,0 +1,120 @@
+package bbitjaccard
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/lsh/bbitminhash"
+	"github.com/koykov/pbtk/lsh/minhash"
+	"github.com/koykov/pbtk/shingle"
+	"github.com/koykov/pbtk/similarity"
+)
+
+var (
+	testh       = xxhash.Hasher64[[]byte]{}
+	testshc     = shingle.NewChar[[]byte](3, "") // 3-gram
+	testk       = uint64(256)
+	testlshc, _ = bbitminhash.NewHasher[[]byte](bbitminhash.NewConfig[[]byte](testh, testk, testshc, 4).
+			WithMode(minhash.ModeOnePermutation))
+)
+
+// 100 common words of 125 total, so J(a,b) = 0.8 for 1-word shingles.
+func testSets() (a, b []byte) {
+	for i := 0; i < 125; i++ {
+		if i < 100 || i%2 == 0 {
+			a = strconv.AppendInt(append(a, 'w'), int64(i), 10)
+			a = append(a, ' ')
+		}
+		if i < 100 || i%2 == 1 {
+			b = strconv.AppendInt(append(b, 'w'), int64(i), 10)
+			b = append(b, ' ')
+		}
+	}
+	return
+}
+
+func TestEstimator(t *testing.T) {
+	t.Run("char", func(t *testing.T) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlshc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		similarity.TestMe(t, e, 1)
+	})
+	t.Run("correction", func(t *testing.T) {
+		const j = 100. / 125
+		a, b := testSets()
+		for _, bits := range []uint64{1, 2, 4, 8} {
+			t.Run(fmt.Sprintf("b%d", bits), func(t *testing.T) {
+				h, _ := bbitminhash.NewHasher[[]byte](bbitminhash.NewConfig[[]byte](testh, 1024,
+					shingle.NewWord[[]byte](1, ""), bits).WithMode(minhash.ModeOnePermutation))
+				e, err := NewEstimator[[]byte](NewConfig[[]byte](h))
+				if err != nil {
+					t.Fatal(err)
+				}
+				r, _ := e.Estimate(a, b)
+				if math.Abs(r-j) > .05 {
+					t.Errorf("estimation too inaccurate: expected %f, got %f", j, r)
+				}
+				// uncorrected fraction of equal values overestimates similarity due to accidental collisions
+				h.Reset()
+				_ = h.Add(a)
+				sa := h.AppendHash(nil)
+				h.Reset()
+				_ = h.Add(b)
+				sb := h.AppendHash(nil)
+				if p := lsh.TestDistJaccard(sa, sb, 1024); p < r {
+					t.Errorf("expected uncorrected estimation %f greater than corrected %f", p, r)
+				}
+			})
+		}
+	})
+	t.Run("sizes", func(t *testing.T) {
+		e, _ := NewEstimator[[]byte](NewConfig[[]byte](testlshc).WithUniverse(math.MaxUint64))
+		_ = testlshc.Add([]byte("A sad man is crying"))
+		sa := lsh.NewSignature[[]byte](testlshc)
+		testlshc.Reset()
+		_ = testlshc.Add([]byte("A sad man is crying loudly"))
+		sb := lsh.NewSignature[[]byte](testlshc)
+		testlshc.Reset()
+		// huge universe makes the same correction as infinite one
+		r0, _ := e.EstimateSignatures(sa, sb)
+		r1, _ := e.EstimateSizes(sa, sb, 17, 24)
+		if math.Abs(r0-r1) > 1e-9 {
+			t.Errorf("expected %f, got %f", r0, r1)
+		}
+		// minimums of dense sets are small numbers, so their lower bits collide accidentally less often
+		e, _ = NewEstimator[[]byte](NewConfig[[]byte](testlshc).WithUniverse(100))
+		if r2, _ := e.EstimateSizes(sa, sb, 17, 24); r2 <= r1 {
+			t.Errorf("expected estimation greater than %f, got %f", r1, r2)
+		}
+	})
+	t.Run("signatures", func(t *testing.T) {
+		e, _ := NewEstimator[[]byte](NewConfig[[]byte](testlshc))
+		similarity.TestSignaturesMe(t, e, testlshc)
+	})
+	t.Run("incompatible", func(t *testing.T) {
+		kh, _ := bbitminhash.NewHasher[[]byte](bbitminhash.NewConfig[[]byte](testh, testk, testshc, 4))
+		mh, _ := minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, testk, testshc).
+			WithMode(minhash.ModeOnePermutation))
+		for _, h := range []lsh.Hasher[[]byte]{kh, mh} {
+			if _, err := NewEstimator[[]byte](NewConfig[[]byte](h)); err != similarity.ErrIncompatibleLSH {
+				t.Errorf("expected incompatible LSH error, got %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkEstimator(b *testing.B) {
+	b.Run("char", func(b *testing.B) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlshc))
+		if err != nil {
+			b.Fatal(err)
+		}
+		similarity.BenchMe(b, e)
+	})
+}
@@ -32,7 +32,33 @@ func (e *estimator[T]) Estimate(a, b T) (r float64, err error) {
 	}
 
 	abuf, bbuf, err := e.VectorizePair(e.conf.LSH, a, b)
-	if len(abuf) == 0 || len(bbuf) == 0 || err != nil {
+	if err != nil {
+		return
+	}
+	r = e.estimate(abuf, bbuf)
+	return
+}
+
+func (e *estimator[T]) EstimateSignatures(a, b *lsh.Signature) (r float64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	if err = lsh.CompatibleHasher(e.conf.LSH, a, b); err != nil {
+		return
+	}
+	r = e.estimate(a.Values, b.Values)
+	return
+}
+
+func (e *estimator[T]) estimate(abuf, bbuf []uint64) (r float64) {
+	if len(abuf) == 0 || len(bbuf) == 0 {
+		return
+	}
+	if b, ok := e.conf.LSH.(lsh.Bitwise); ok && b.Bitwise() {
+		// binary fingerprints: angle between vectors is proportional to Hamming distance
+		n := max(len(abuf), len(bbuf))
+		r = math.Cos(math.Pi * float64(lsh.Hamming(abuf, bbuf)) / float64(n*64))
 		return
 	}
 	var amag, bmag float64
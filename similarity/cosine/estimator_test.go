@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/lsh/minhash"
+	"github.com/koykov/pbtk/lsh/simhash"
 	"github.com/koykov/pbtk/shingle"
 	"github.com/koykov/pbtk/similarity"
 )
@@ -15,6 +17,8 @@ var (
 	testshw     = shingle.NewWord[[]byte](2, "") // 2-word shingle
 	testlshc, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, testshc))
 	testlshw, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, testshw))
+	testlshs, _ = simhash.NewHasher[[]byte](simhash.NewConfig[[]byte](nil, testshc).
+			WithAlgo128(xxhash.Hasher128[[]byte]{}).WithWidth(simhash.Width256))
 )
 
 func TestEstimator(t *testing.T) {
@@ -32,6 +36,23 @@ func TestEstimator(t *testing.T) {
 		}
 		similarity.TestMe(t, e, 1)
 	})
+	t.Run("simhash", func(t *testing.T) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlshs))
+		if err != nil {
+			t.Fatal(err)
+		}
+		similarity.TestMe(t, e, 1)
+		e.Reset()
+		if r, _ := e.Estimate([]byte("A sad man is crying"), []byte("A sad man is crying")); r != 1 {
+			t.Errorf("expected similarity 1 of equal texts, got %f", r)
+		}
+	})
+	t.Run("signatures", func(t *testing.T) {
+		for _, h := range []lsh.Hasher[[]byte]{testlshc, testlshs} {
+			e, _ := NewEstimator[[]byte](NewConfig[[]byte](h))
+			similarity.TestSignaturesMe(t, e, h)
+		}
+	})
 }
 
 func BenchmarkEstimator(b *testing.B) {
@@ -2,4 +2,7 @@ package similarity
 
 import "errors"
 
-var ErrNoLSH = errors.New("no LSH provided")
+var (
+	ErrNoLSH           = errors.New("no LSH provided")
+	ErrIncompatibleLSH = errors.New("LSH produces signatures incompatible with estimator")
+)
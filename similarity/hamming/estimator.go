@@ -1,7 +1,6 @@
 package hamming
 
 import (
-	"math/bits"
 	"sync"
 
 	"github.com/koykov/byteseq"
@@ -32,25 +31,33 @@ func (e *estimator[T]) Estimate(a, b T) (r float64, err error) {
 	}
 
 	abuf, bbuf, err := e.VectorizePair(e.conf.LSH, a, b)
-	if len(abuf) == 0 || len(bbuf) == 0 || err != nil {
+	if err != nil {
 		return
 	}
-	n := max(len(abuf), len(bbuf))
-	_, _ = abuf[len(abuf)-1], bbuf[len(bbuf)-1]
-	for i := 0; i < n; i++ {
-		var av, bv uint64
-		if i < len(abuf) {
-			av = abuf[i]
-		}
-		if i < len(bbuf) {
-			bv = bbuf[i]
-		}
-		r += float64(bits.OnesCount64(av ^ bv))
+	r = e.estimate(abuf, bbuf)
+	return
+}
+
+func (e *estimator[T]) EstimateSignatures(a, b *lsh.Signature) (r float64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
 	}
-	r = 1 - r/float64(n*64)
+	if err = lsh.CompatibleHasher(e.conf.LSH, a, b); err != nil {
+		return
+	}
+	r = e.estimate(a.Values, b.Values)
 	return
 }
 
+func (e *estimator[T]) estimate(abuf, bbuf []uint64) float64 {
+	if len(abuf) == 0 || len(bbuf) == 0 {
+		return 0
+	}
+	n := max(len(abuf), len(bbuf))
+	return 1 - float64(lsh.Hamming(abuf, bbuf))/float64(n*64)
+}
+
 func (e *estimator[T]) Reset() {
 	e.VectorPair.Reset()
 	e.conf.LSH.Reset()
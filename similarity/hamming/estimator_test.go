@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/lsh/simhash"
 	"github.com/koykov/pbtk/shingle"
 	"github.com/koykov/pbtk/similarity"
@@ -15,6 +16,8 @@ var (
 	testshw     = shingle.NewWord[[]byte](2, "") // 2-word shingle
 	testlshc, _ = simhash.NewHasher[[]byte](simhash.NewConfig[[]byte](testh, testshc))
 	testlshw, _ = simhash.NewHasher[[]byte](simhash.NewConfig[[]byte](testh, testshw))
+	testlshx, _ = simhash.NewHasher[[]byte](simhash.NewConfig[[]byte](nil, testshc).
+			WithAlgo128(xxhash.Hasher128[[]byte]{}).WithWidth(simhash.Width256))
 )
 
 func TestEstimator(t *testing.T) {
@@ -32,6 +35,19 @@ func TestEstimator(t *testing.T) {
 		}
 		similarity.TestMe(t, e, 1)
 	})
+	t.Run("width256", func(t *testing.T) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlshx))
+		if err != nil {
+			t.Fatal(err)
+		}
+		similarity.TestMe(t, e, 1)
+	})
+	t.Run("signatures", func(t *testing.T) {
+		for _, h := range []lsh.Hasher[[]byte]{testlshc, testlshx} {
+			e, _ := NewEstimator[[]byte](NewConfig[[]byte](h))
+			similarity.TestSignaturesMe(t, e, h)
+		}
+	})
 }
 
 func BenchmarkEstimator(b *testing.B) {
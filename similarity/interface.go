@@ -1,8 +1,15 @@
 package similarity
 
-import "github.com/koykov/byteseq"
+import (
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk/lsh"
+)
 
 type Estimator[T byteseq.Q] interface {
 	Estimate(a, b T) (float64, error)
+	// EstimateSignatures estimates similarity of precomputed signatures without rehashing of the source texts.
+	// Signatures must be produced by hasher of the same kind and params as estimator's LSH, otherwise
+	// lsh.ErrSignatureMismatch returns.
+	EstimateSignatures(a, b *lsh.Signature) (float64, error)
 	Reset()
 }
@@ -32,7 +32,39 @@ func (e *estimator[T]) Estimate(a, b T) (r float64, err error) {
 	}
 
 	abuf, bbuf, err := e.VectorizePair(e.conf.LSH, a, b)
-	if len(abuf) == 0 || len(bbuf) == 0 || err != nil {
+	if err != nil {
+		return
+	}
+	r = e.estimate(abuf, bbuf)
+	return
+}
+
+func (e *estimator[T]) EstimateSignatures(a, b *lsh.Signature) (r float64, err error) {
+	if e.once.Do(e.init); e.err != nil {
+		err = e.err
+		return
+	}
+	if err = lsh.CompatibleHasher(e.conf.LSH, a, b); err != nil {
+		return
+	}
+	r = e.estimate(a.Values, b.Values)
+	return
+}
+
+func (e *estimator[T]) estimate(abuf, bbuf []uint64) (r float64) {
+	if len(abuf) == 0 || len(bbuf) == 0 {
+		return
+	}
+	if p, ok := e.conf.LSH.(lsh.Positional); ok && p.Positional() {
+		// positional signatures: fraction of equal values at the same positions
+		n := min(len(abuf), len(bbuf))
+		var eq float64
+		for i := 0; i < n; i++ {
+			if abuf[i] == bbuf[i] {
+				eq++
+			}
+		}
+		r = eq / float64(n)
 		return
 	}
 	e.rstr()
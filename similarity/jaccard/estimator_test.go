@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/lsh/minhash"
+	"github.com/koykov/pbtk/lsh/wminhash"
 	"github.com/koykov/pbtk/shingle"
 	"github.com/koykov/pbtk/similarity"
 )
@@ -15,6 +17,10 @@ var (
 	testshw     = shingle.NewWord[[]byte](2, "") // 2-word shingle
 	testlshc, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, testshc))
 	testlshw, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, testshw))
+	testlsho, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 256, testshc).
+			WithMode(minhash.ModeOnePermutation))
+	testlshr, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, shingle.NewRollingChar[[]byte](3, "")))
+	testlshm, _ = wminhash.NewHasher[[]byte](wminhash.NewConfig[[]byte](testh, 256, testshc))
 )
 
 func TestEstimator(t *testing.T) {
@@ -32,6 +38,33 @@ func TestEstimator(t *testing.T) {
 		}
 		similarity.TestMe(t, e, 1)
 	})
+	t.Run("one permutation", func(t *testing.T) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlsho))
+		if err != nil {
+			t.Fatal(err)
+		}
+		similarity.TestMe(t, e, 1)
+	})
+	t.Run("rolling", func(t *testing.T) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlshr))
+		if err != nil {
+			t.Fatal(err)
+		}
+		similarity.TestMe(t, e, 1)
+	})
+	t.Run("weighted", func(t *testing.T) {
+		e, err := NewEstimator[[]byte](NewConfig[[]byte](testlshm))
+		if err != nil {
+			t.Fatal(err)
+		}
+		similarity.TestMe(t, e, 1)
+	})
+	t.Run("signatures", func(t *testing.T) {
+		for _, h := range []lsh.Hasher[[]byte]{testlshc, testlsho, testlshm} {
+			e, _ := NewEstimator[[]byte](NewConfig[[]byte](h))
+			similarity.TestSignaturesMe(t, e, h)
+		}
+	})
 }
 
 func BenchmarkEstimator(b *testing.B) {
@@ -3,6 +3,7 @@ package similarity
 import (
 	"testing"
 
+	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/simtest"
 )
 
@@ -24,6 +25,15 @@ func TestMe(t *testing.T, est Estimator[[]byte], threshold float64) {
 	})
 }
 
+// TestSignaturesMe checks that estimation of signatures stored by hasher equals estimation of the source texts. hash
+// must be the same hasher as estimator's LSH.
+func TestSignaturesMe(t *testing.T, est Estimator[[]byte], hash lsh.Hasher[[]byte]) {
+	lsh.TestSignaturesMe(t, hash, func(a, b []byte) (float64, error) {
+		est.Reset()
+		return est.Estimate(a, b)
+	}, est.EstimateSignatures)
+}
+
 func BenchMe(b *testing.B, est Estimator[[]byte]) {
 	simtest.EachTestingDataset(func(_ int, ds *simtest.Dataset) {
 		b.Run(ds.Name, func(b *testing.B) {
@@ -2,13 +2,15 @@ package simtest
 
 import (
 	"encoding/csv"
-	"errors"
-	"fmt"
 	"io"
-	"net/http"
+	"math"
+	"math/rand"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/koykov/pbtk"
 )
 
 type Tuple struct {
@@ -17,6 +19,8 @@ type Tuple struct {
 	B          []byte  `tsv:"sentence_B"`
 	RelScore   float64 `tsv:"relatedness_score"`
 	Entailment []byte  `tsv:"entailment_judgment"`
+	// Exact Jaccard similarity of words sets of A and B. Known for synthetic datasets only, otherwise -1.
+	Jaccard float64
 }
 
 type Dataset struct {
@@ -24,89 +28,128 @@ type Dataset struct {
 	Tuples []Tuple
 }
 
-const (
-	datasetTrial = "https://github.com/koykov/dataset/raw/refs/heads/master/similarity/SICK_trial.tsv"
-	datasetTrain = "https://github.com/koykov/dataset/raw/refs/heads/master/similarity/SICK_train.tsv"
-	datasetTest  = "https://github.com/koykov/dataset/raw/refs/heads/master/similarity/SICK_test_annotated.tsv"
-)
-
-var datasets []Dataset
-
-func init() {
-	fread := func(rempath string) (Dataset, error) {
-		var ds Dataset
+// Source is a source of testing dataset. Source must be deterministic and must not depend on network or host
+// environment, so tests using it are reproducible.
+type Source interface {
+	Load() (Dataset, error)
+}
 
-		pos := strings.LastIndex(rempath, "/")
-		if pos == -1 {
-			return ds, fmt.Errorf("invalid path: %s", rempath)
-		}
-		fname := rempath[pos+1:]
-		ds.Name = strings.ReplaceAll(fname, ".tsv", "")
-		locpath := "/tmp/" + fname
+// TSV loads dataset from local file in SICK format (pair_ID, sentence_A, sentence_B, relatedness_score,
+// entailment_judgment columns with header line).
+type TSV string
 
-		var r io.Reader
+func (p TSV) Load() (ds Dataset, err error) {
+	path := string(p)
+	ds.Name = strings.TrimSuffix(filepath.Base(path), ".tsv")
 
-		if _, err := os.Stat(locpath); errors.Is(err, os.ErrNotExist) {
-			u, err := http.Get(rempath)
-			if err != nil {
-				return ds, err
-			}
+	f, err := os.Open(path)
+	if err != nil {
+		return ds, err
+	}
+	defer func() { _ = f.Close() }()
 
-			t, err := os.OpenFile(locpath, os.O_CREATE|os.O_WRONLY, 0644)
-			if err != nil {
-				return ds, err
-			}
-			defer func() { _ = t.Close() }()
-			_, err = io.Copy(t, u.Body)
-			if err != nil {
-				return ds, err
-			}
-			_ = u.Body.Close()
+	rdr := csv.NewReader(f)
+	rdr.Comma = '\t'
+	rdr.LazyQuotes = true
+	for i := 0; ; i++ {
+		rec, err := rdr.Read()
+		if err == io.EOF {
+			break
 		}
-
-		f, err := os.Open(locpath)
 		if err != nil {
 			return ds, err
 		}
-		defer func() { _ = f.Close() }()
-		r = f
-
-		rdr := csv.NewReader(r)
-		rdr.Comma = '\t'
-		for i := 0; ; i++ {
-			rec, err := rdr.Read()
-			if err == io.EOF {
-				break
-			}
-			if i == 0 {
-				continue
-			}
-			id, _ := strconv.Atoi(rec[0])
-			score, _ := strconv.ParseFloat(rec[3], 64)
-			t := Tuple{
-				ID:         id,
-				A:          []byte(rec[1]),
-				B:          []byte(rec[2]),
-				RelScore:   score,
-				Entailment: []byte(rec[4]),
-			}
-			ds.Tuples = append(ds.Tuples, t)
-			if err != nil {
-				break
-			}
+		if i == 0 || len(rec) < 5 {
+			continue
 		}
-		return ds, nil
-
+		id, _ := strconv.Atoi(rec[0])
+		score, _ := strconv.ParseFloat(rec[3], 64)
+		ds.Tuples = append(ds.Tuples, Tuple{
+			ID:         id,
+			A:          []byte(rec[1]),
+			B:          []byte(rec[2]),
+			RelScore:   score,
+			Entailment: []byte(rec[4]),
+			Jaccard:    -1,
+		})
 	}
-	if t, err := fread(datasetTrial); err == nil {
-		datasets = append(datasets, t)
+	return ds, nil
+}
+
+// Synthetic generates Pairs near-duplicate texts of Words distinct words in total (see pbtk.Words). Jaccard similarity
+// of words sets grows evenly from MinJaccard in the first pair to MaxJaccard in the last one.
+type Synthetic struct {
+	Name       string
+	Pairs      int
+	Words      int
+	MinJaccard float64
+	MaxJaccard float64
+	Seed       int64
+}
+
+func (s Synthetic) Load() (ds Dataset, err error) {
+	ds.Name = s.Name
+	ds.Tuples = make([]Tuple, 0, s.Pairs)
+	w := pbtk.NewWords(s.Seed)
+	rng := rand.New(rand.NewSource(s.Seed))
+	var a, b []uint64
+	for i := 0; i < s.Pairs; i++ {
+		j := s.MaxJaccard
+		if s.Pairs > 1 {
+			j = s.MinJaccard + (s.MaxJaccard-s.MinJaccard)*float64(i)/float64(s.Pairs-1)
+		}
+		// words of each pair are unique, so texts of different pairs don't intersect
+		base := uint64(i * s.Words)
+		common := int(math.Round(j * float64(s.Words)))
+		onlyA := (s.Words - common) / 2
+		a, b = a[:0], b[:0]
+		for k := 0; k < s.Words; k++ {
+			switch id := base + uint64(k); {
+			case k < common:
+				a, b = append(a, id), append(b, id)
+			case k < common+onlyA:
+				a = append(a, id)
+			default:
+				b = append(b, id)
+			}
+		}
+		rng.Shuffle(len(a), func(x, y int) { a[x], a[y] = a[y], a[x] })
+		rng.Shuffle(len(b), func(x, y int) { b[x], b[y] = b[y], b[x] })
+		ds.Tuples = append(ds.Tuples, Tuple{
+			ID:      i + 1,
+			A:       text(w, a),
+			B:       text(w, b),
+			Jaccard: float64(common) / float64(s.Words),
+		})
 	}
-	if t, err := fread(datasetTrain); err == nil {
-		datasets = append(datasets, t)
+	return
+}
+
+func text(w *pbtk.Words, ids []uint64) (r []byte) {
+	for i := 0; i < len(ids); i++ {
+		if i > 0 {
+			r = append(r, ' ')
+		}
+		r = w.AppendWord(r, ids[i])
 	}
-	if t, err := fread(datasetTest); err == nil {
-		datasets = append(datasets, t)
+	return
+}
+
+var datasets []Dataset
+
+// RegisterDataset loads dataset from src and adds it to the list of datasets of domains TestMe functions.
+func RegisterDataset(src Source) error {
+	ds, err := src.Load()
+	if err != nil {
+		return err
 	}
+	datasets = append(datasets, ds)
+	return nil
+}
+
+func init() {
+	_ = RegisterDataset(Synthetic{Name: "synthetic/near", Pairs: 500, Words: 12, MinJaccard: .5, MaxJaccard: 1, Seed: 1})
+	_ = RegisterDataset(Synthetic{Name: "synthetic/mixed", Pairs: 500, Words: 12, MinJaccard: 0, MaxJaccard: 1, Seed: 2})
 }
 
 func EachTestingDataset(f func(i int, ds *Dataset)) {
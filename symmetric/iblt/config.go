@@ -0,0 +1,99 @@
+package iblt
+
+import (
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk/lsh"
+)
+
+const (
+	defaultHashes = 3
+
+	defaultStrata      = 32
+	defaultStrataCells = 80
+)
+
+type Config struct {
+	// Expected size of symmetric difference of the sets (see Strata to estimate it or oddsketch differ).
+	// Mandatory param (except of explicit Cells).
+	Difference uint64
+	// Number of cells each key maps to.
+	// If this param omitted, defaultHashes (3) will use.
+	Hashes uint64
+	// Number of cells in the table.
+	// If this param omitted, it calculates from Difference.
+	Cells uint64
+}
+
+func NewConfig(difference uint64) *Config {
+	return &Config{Difference: difference}
+}
+
+func (c *Config) WithHashes(hashes uint64) *Config {
+	c.Hashes = hashes
+	return c
+}
+
+func (c *Config) WithCells(cells uint64) *Config {
+	c.Cells = cells
+	return c
+}
+
+func (c *Config) copy() *Config {
+	cpy := *c
+	return &cpy
+}
+
+type StrataConfig struct {
+	// Number of strata, each next stratum keeps twice fewer keys. Maximal value is 64.
+	// If this param omitted, defaultStrata (32) will use.
+	Strata uint64
+	// Number of cells of each stratum table.
+	// If this param omitted, defaultStrataCells (80) will use.
+	Cells uint64
+	// Number of cells each key maps to.
+	// If this param omitted, defaultHashes (3) will use.
+	Hashes uint64
+}
+
+func NewStrataConfig() *StrataConfig {
+	return &StrataConfig{}
+}
+
+func (c *StrataConfig) WithStrata(strata uint64) *StrataConfig {
+	c.Strata = strata
+	return c
+}
+
+func (c *StrataConfig) WithCells(cells uint64) *StrataConfig {
+	c.Cells = cells
+	return c
+}
+
+func (c *StrataConfig) WithHashes(hashes uint64) *StrataConfig {
+	c.Hashes = hashes
+	return c
+}
+
+func (c *StrataConfig) copy() *StrataConfig {
+	cpy := *c
+	return &cpy
+}
+
+type DifferConfig[T byteseq.Q] struct {
+	Config
+	// Hasher to calculate signatures of the texts. Values of signatures use as keys of the table.
+	// Mandatory param.
+	LSH lsh.Hasher[T]
+}
+
+func NewDifferConfig[T byteseq.Q](difference uint64, lsh lsh.Hasher[T]) *DifferConfig[T] {
+	return &DifferConfig[T]{
+		Config: Config{Difference: difference},
+		LSH:    lsh,
+	}
+}
+
+func (c *DifferConfig[T]) copy() *DifferConfig[T] {
+	cpy := *c
+	return &cpy
+}
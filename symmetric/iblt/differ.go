@@ -0,0 +1,94 @@
+package iblt
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/lsh"
+	"github.com/koykov/pbtk/symmetric"
+)
+
+type differ[T byteseq.Q] struct {
+	lsh.VectorPair[T]
+	conf *DifferConfig[T]
+	t    *Table
+	keys []uint64
+	a, b []uint64
+	once sync.Once
+
+	err error
+}
+
+// NewDiffer makes differ that returns exact size of symmetric difference of sets of LSH values of two texts, if it
+// doesn't exceed the expected difference much. Otherwise, ErrDecodeFailed returns with number of decoded values.
+func NewDiffer[T byteseq.Q](conf *DifferConfig[T]) (symmetric.Differ[T], error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	d := &differ[T]{conf: conf.copy()}
+	if d.once.Do(d.init); d.err != nil {
+		return nil, d.err
+	}
+	return d, nil
+}
+
+func (d *differ[T]) Diff(a, b T) (r float64, err error) {
+	if d.once.Do(d.init); d.err != nil {
+		err = d.err
+		return
+	}
+
+	abuf, bbuf, err := d.VectorizePair(d.conf.LSH, a, b)
+	if err != nil {
+		return
+	}
+	return d.diff(abuf, bbuf)
+}
+
+func (d *differ[T]) DiffSignatures(a, b *lsh.Signature) (r float64, err error) {
+	if d.once.Do(d.init); d.err != nil {
+		err = d.err
+		return
+	}
+	if err = lsh.CompatibleHasher(d.conf.LSH, a, b); err != nil {
+		return
+	}
+	return d.diff(a.Values, b.Values)
+}
+
+func (d *differ[T]) diff(abuf, bbuf []uint64) (r float64, err error) {
+	d.t.Reset()
+	// signatures may contain repeated values, but table works with sets
+	d.keys = append(d.keys[:0], abuf...)
+	for _, key := range d.uniq() {
+		_ = d.t.Insert(key)
+	}
+	d.keys = append(d.keys[:0], bbuf...)
+	for _, key := range d.uniq() {
+		_ = d.t.Delete(key)
+	}
+	d.a, d.b, err = d.t.AppendDecode(d.a[:0], d.b[:0])
+	r = float64(len(d.a) + len(d.b))
+	return
+}
+
+func (d *differ[T]) uniq() []uint64 {
+	slices.Sort(d.keys)
+	return slices.Compact(d.keys)
+}
+
+func (d *differ[T]) Reset() {
+	d.VectorPair.Reset()
+	d.t.Reset()
+	d.conf.LSH.Reset()
+}
+
+func (d *differ[T]) init() {
+	if d.conf.LSH == nil {
+		d.err = symmetric.ErrNoLSH
+		return
+	}
+	d.t, d.err = NewTable(&d.conf.Config)
+}
@@ -0,0 +1,59 @@
+package iblt
+
+import (
+	"testing"
+
+	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk/lsh/minhash"
+	"github.com/koykov/pbtk/shingle"
+	"github.com/koykov/pbtk/symmetric"
+)
+
+var (
+	testh       = xxhash.Hasher64[[]byte]{}
+	testshc     = shingle.NewChar[[]byte](3, "") // 3-gram
+	testlshc, _ = minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, testshc))
+)
+
+func TestDiffer(t *testing.T) {
+	t.Run("char", func(t *testing.T) {
+		d, err := NewDiffer[[]byte](NewDifferConfig[[]byte](1000, testlshc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		symmetric.TestMe(t, d, 0)
+	})
+	t.Run("exact", func(t *testing.T) {
+		// 3-grams of "abcd" and "abce": {abc, bcd} vs {abc, bce}
+		lshc, _ := minhash.NewHasher[[]byte](minhash.NewConfig[[]byte](testh, 50, shingle.NewChar[[]byte](3, "")))
+		d, _ := NewDiffer[[]byte](NewDifferConfig[[]byte](10, lshc))
+		r, err := d.Diff([]byte("abcd"), []byte("abce"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != 2 {
+			t.Errorf("expected difference 2, got %f", r)
+		}
+		d.Reset()
+		if r, _ = d.Diff([]byte("abcd"), []byte("abcd")); r != 0 {
+			t.Errorf("expected zero difference of equal texts, got %f", r)
+		}
+	})
+	t.Run("signatures", func(t *testing.T) {
+		d, _ := NewDiffer[[]byte](NewDifferConfig[[]byte](100, testlshc))
+		symmetric.TestSignaturesMe(t, d, testlshc)
+	})
+	t.Run("no LSH", func(t *testing.T) {
+		if _, err := NewDiffer[[]byte](NewDifferConfig[[]byte](10, nil)); err != symmetric.ErrNoLSH {
+			t.Errorf("expected no LSH error, got %v", err)
+		}
+	})
+}
+
+func BenchmarkDiffer(b *testing.B) {
+	d, err := NewDiffer[[]byte](NewDifferConfig[[]byte](1000, testlshc))
+	if err != nil {
+		b.Fatal(err)
+	}
+	symmetric.BenchMe(b, d)
+}
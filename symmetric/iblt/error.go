@@ -0,0 +1,10 @@
+package iblt
+
+import "errors"
+
+var (
+	ErrNoDifference   = errors.New("no expected difference provided")
+	ErrTooFewHashes   = errors.New("hashes number must be at least 2")
+	ErrConfigMismatch = errors.New("tables config mismatch")
+	ErrDecodeFailed   = errors.New("table can't be decoded completely, difference is greater than expected")
+)
@@ -0,0 +1,11 @@
+package iblt
+
+import "math"
+
+// Calculate number of cells to decode difference of d keys with probability about 98%. Asymptotic threshold of peeling
+// is about 1.22d cells for 3 hashes, but small tables need extra space to avoid cycles of keys.
+func optimalCells(d, k uint64) uint64 {
+	m := uint64(math.Ceil(1.5*float64(d)+6*math.Sqrt(float64(d)))) + 4*k
+	// round up to multiple of k, since each key maps to one cell of each of k partitions
+	return (m + k - 1) / k * k
+}
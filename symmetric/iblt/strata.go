@@ -0,0 +1,200 @@
+package iblt
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+
+	"github.com/koykov/pbtk"
+)
+
+const (
+	strataDumpSignature = 0x8f2b6d14c3a05e97
+	strataDumpVersion   = 1.0
+
+	strataSeed = 0x7c3a91e5b20d64f1
+)
+
+// Strata estimator of symmetric difference size to choose size of the table before reconciliation.
+//
+// Key goes to i-th stratum with probability 2^-(i+1), each stratum is a small Table. Estimation decodes differences of
+// strata from the top one; if i-th stratum can't be decoded, number of keys decoded before scales by 2^(i+1).
+//
+// Caution! Strata isn't thread-safe.
+type Strata struct {
+	once   sync.Once
+	conf   *StrataConfig
+	tables []*Table
+	a, b   []uint64
+
+	err error
+}
+
+func NewStrata(conf *StrataConfig) (*Strata, error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	s := &Strata{conf: conf.copy()}
+	if s.once.Do(s.init); s.err != nil {
+		return nil, s.err
+	}
+	return s, nil
+}
+
+// Insert adds key to the estimator.
+func (s *Strata) Insert(key uint64) error {
+	if s.once.Do(s.init); s.err != nil {
+		return s.err
+	}
+	return s.tables[s.stratum(key)].Insert(key)
+}
+
+// Delete removes key from the estimator.
+func (s *Strata) Delete(key uint64) error {
+	if s.once.Do(s.init); s.err != nil {
+		return s.err
+	}
+	return s.tables[s.stratum(key)].Delete(key)
+}
+
+// Estimate returns estimated size of symmetric difference of sets of s and other. Both estimators must have the same
+// config.
+func (s *Strata) Estimate(other *Strata) (uint64, error) {
+	if s.once.Do(s.init); s.err != nil {
+		return 0, s.err
+	}
+	if other == nil {
+		return 0, pbtk.ErrInvalidConfig
+	}
+	if other.once.Do(other.init); other.err != nil {
+		return 0, other.err
+	}
+	if len(s.tables) != len(other.tables) {
+		return 0, ErrConfigMismatch
+	}
+	var count uint64
+	for i := len(s.tables) - 1; i >= 0; i-- {
+		if err := s.tables[i].compatible(other.tables[i]); err != nil {
+			return 0, err
+		}
+		var err error
+		s.a, s.b, err = s.tables[i].decodeDiff(other.tables[i], s.a[:0], s.b[:0])
+		if err == ErrDecodeFailed {
+			return count << (i + 1), nil
+		}
+		count += uint64(len(s.a) + len(s.b))
+	}
+	return count, nil
+}
+
+// Reset flushes the estimator.
+func (s *Strata) Reset() {
+	if s.once.Do(s.init); s.err != nil {
+		return
+	}
+	for i := 0; i < len(s.tables); i++ {
+		s.tables[i].Reset()
+	}
+}
+
+// WriteTo writes estimator to w.
+func (s *Strata) WriteTo(w io.Writer) (n int64, err error) {
+	if s.once.Do(s.init); s.err != nil {
+		return 0, s.err
+	}
+	const blocksz = 4096
+	buf := make([]byte, 0, blocksz)
+	buf = binary.LittleEndian.AppendUint64(buf, strataDumpSignature)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(strataDumpVersion))
+	buf = binary.LittleEndian.AppendUint64(buf, s.conf.Strata)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(s.tables[0].cells)))
+	buf = binary.LittleEndian.AppendUint64(buf, s.conf.Hashes)
+	var m int
+	for i := 0; i < len(s.tables); i++ {
+		cells := s.tables[i].cells
+		for j := 0; j < len(cells); j++ {
+			buf = appendCell(buf, &cells[j])
+			if len(buf) >= blocksz {
+				m, err = w.Write(buf)
+				n += int64(m)
+				if err != nil {
+					return
+				}
+				buf = buf[:0]
+			}
+		}
+	}
+	m, err = w.Write(buf)
+	n += int64(m)
+	return
+}
+
+// ReadFrom reads estimator from r. Dump must be written by estimator with the same config.
+func (s *Strata) ReadFrom(r io.Reader) (n int64, err error) {
+	if s.once.Do(s.init); s.err != nil {
+		return 0, s.err
+	}
+	var (
+		buf [40]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint64(buf[0:8]) != strataDumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint64(buf[8:16]) != math.Float64bits(strataDumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	if binary.LittleEndian.Uint64(buf[16:24]) != s.conf.Strata ||
+		binary.LittleEndian.Uint64(buf[24:32]) != uint64(len(s.tables[0].cells)) ||
+		binary.LittleEndian.Uint64(buf[32:40]) != s.conf.Hashes {
+		return n, ErrConfigMismatch
+	}
+	// decode to scratch buffers to keep the estimator unchanged on partial read
+	for i := 0; i < len(s.tables); i++ {
+		t := s.tables[i]
+		t.peel = append(t.peel[:0], make([]cell, len(t.cells))...)
+		var n1 int64
+		n1, err = readCells(r, t.peel)
+		n += n1
+		if err != nil {
+			return
+		}
+	}
+	for i := 0; i < len(s.tables); i++ {
+		copy(s.tables[i].cells, s.tables[i].peel)
+	}
+	return
+}
+
+// stratum returns index of stratum by number of trailing zeros of key's hash sum.
+func (s *Strata) stratum(key uint64) int {
+	return min(bits.TrailingZeros64(pbtk.Fmix64(key^strataSeed)), len(s.tables)-1)
+}
+
+func (s *Strata) init() {
+	c := s.conf
+	if c.Strata == 0 {
+		c.Strata = defaultStrata
+	}
+	// hash sum can't have more than 64 trailing zeros
+	c.Strata = min(c.Strata, 64)
+	if c.Cells == 0 {
+		c.Cells = defaultStrataCells
+	}
+	if c.Hashes == 0 {
+		c.Hashes = defaultHashes
+	}
+	s.tables = make([]*Table, c.Strata)
+	for i := uint64(0); i < c.Strata; i++ {
+		if s.tables[i], s.err = NewTable(NewConfig(0).WithCells(c.Cells).WithHashes(c.Hashes)); s.err != nil {
+			return
+		}
+	}
+}
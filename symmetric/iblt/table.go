@@ -0,0 +1,321 @@
+package iblt
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"math/bits"
+	"sync"
+
+	"github.com/koykov/pbtk"
+)
+
+const (
+	dumpSignature = 0x51c8e07a2d4b93f6
+	dumpVersion   = 1.0
+
+	checkSeed = 0x2545f4914f6cdd1d
+)
+
+// Table is an Invertible Bloom Lookup Table of uint64 keys (ids or hash sums of items).
+// See https://arxiv.org/abs/1101.2245 and https://www.ics.uci.edu/~eppstein/pubs/EppGooUye-SIGCOMM-11.pdf for details.
+//
+// Each key maps to one cell of each of k partitions of the table. Cell keeps count of keys, XOR of keys and XOR of
+// their check sums. Subtraction of tables of two sets cancels common keys, so the rest decodes by peeling of pure
+// cells (with only one key) if the difference isn't much greater than the expected one.
+//
+// Caution! Table isn't thread-safe.
+type Table struct {
+	once  sync.Once
+	conf  *Config
+	part  uint64 // size of partition
+	cells []cell
+	peel  []cell // decoding buffer
+	queue []uint64
+
+	err error
+}
+
+type cell struct {
+	count        int64
+	keySum, hsum uint64
+}
+
+func NewTable(conf *Config) (*Table, error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	t := &Table{conf: conf.copy()}
+	if t.once.Do(t.init); t.err != nil {
+		return nil, t.err
+	}
+	return t, nil
+}
+
+// Insert adds key to the table.
+func (t *Table) Insert(key uint64) error {
+	if t.once.Do(t.init); t.err != nil {
+		return t.err
+	}
+	t.toggle(t.cells, key, 1)
+	return nil
+}
+
+// Delete removes key from the table. Key may be deleted before insertion, so table keeps negative count of it.
+func (t *Table) Delete(key uint64) error {
+	if t.once.Do(t.init); t.err != nil {
+		return t.err
+	}
+	t.toggle(t.cells, key, -1)
+	return nil
+}
+
+// Subtract subtracts other table from t. Both tables must have the same config.
+func (t *Table) Subtract(other *Table) error {
+	if t.once.Do(t.init); t.err != nil {
+		return t.err
+	}
+	if err := t.compatible(other); err != nil {
+		return err
+	}
+	for i := 0; i < len(t.cells); i++ {
+		c, o := &t.cells[i], &other.cells[i]
+		c.count -= o.count
+		c.keySum ^= o.keySum
+		c.hsum ^= o.hsum
+	}
+	return nil
+}
+
+// Decode lists keys inserted to the table (A\B for subtracted table) and deleted from it (B\A). Table keeps unchanged.
+func (t *Table) Decode() (onlyA, onlyB []uint64, err error) {
+	return t.AppendDecode(nil, nil)
+}
+
+// AppendDecode appends keys inserted to the table to onlyA and keys deleted from it to onlyB. If table can't be
+// decoded completely, decoded keys appends anyway and ErrDecodeFailed returns.
+func (t *Table) AppendDecode(onlyA, onlyB []uint64) ([]uint64, []uint64, error) {
+	if t.once.Do(t.init); t.err != nil {
+		return onlyA, onlyB, t.err
+	}
+	t.peel = append(t.peel[:0], t.cells...)
+	return t.decode(onlyA, onlyB)
+}
+
+// Cells returns number of cells in the table.
+func (t *Table) Cells() uint64 {
+	if t.once.Do(t.init); t.err != nil {
+		return 0
+	}
+	return uint64(len(t.cells))
+}
+
+// Reset flushes the table.
+func (t *Table) Reset() {
+	if t.once.Do(t.init); t.err != nil {
+		return
+	}
+	clear(t.cells)
+}
+
+// WriteTo writes table to w.
+func (t *Table) WriteTo(w io.Writer) (n int64, err error) {
+	if t.once.Do(t.init); t.err != nil {
+		return 0, t.err
+	}
+	const blocksz = 4096
+	buf := make([]byte, 0, blocksz)
+	buf = binary.LittleEndian.AppendUint64(buf, dumpSignature)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(dumpVersion))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(t.cells)))
+	buf = binary.LittleEndian.AppendUint64(buf, t.conf.Hashes)
+	var m int
+	for i := 0; i < len(t.cells); i++ {
+		buf = appendCell(buf, &t.cells[i])
+		if len(buf) >= blocksz {
+			m, err = w.Write(buf)
+			n += int64(m)
+			if err != nil {
+				return
+			}
+			buf = buf[:0]
+		}
+	}
+	m, err = w.Write(buf)
+	n += int64(m)
+	return
+}
+
+// ReadFrom reads table from r. Dump must be written by table with the same config.
+func (t *Table) ReadFrom(r io.Reader) (n int64, err error) {
+	if t.once.Do(t.init); t.err != nil {
+		return 0, t.err
+	}
+	var (
+		buf [32]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint64(buf[0:8]) != dumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint64(buf[8:16]) != math.Float64bits(dumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	if binary.LittleEndian.Uint64(buf[16:24]) != uint64(len(t.cells)) ||
+		binary.LittleEndian.Uint64(buf[24:32]) != t.conf.Hashes {
+		return n, ErrConfigMismatch
+	}
+	// decode to scratch buffer to keep the table unchanged on partial read
+	t.peel = append(t.peel[:0], make([]cell, len(t.cells))...)
+	n1, err := readCells(r, t.peel)
+	n += n1
+	if err != nil {
+		return
+	}
+	copy(t.cells, t.peel)
+	return
+}
+
+// decodeDiff decodes difference of tables t and other without modification of them.
+func (t *Table) decodeDiff(other *Table, onlyA, onlyB []uint64) ([]uint64, []uint64, error) {
+	t.peel = append(t.peel[:0], t.cells...)
+	for i := 0; i < len(t.peel); i++ {
+		c, o := &t.peel[i], &other.cells[i]
+		c.count -= o.count
+		c.keySum ^= o.keySum
+		c.hsum ^= o.hsum
+	}
+	return t.decode(onlyA, onlyB)
+}
+
+func (t *Table) decode(onlyA, onlyB []uint64) ([]uint64, []uint64, error) {
+	t.queue = t.queue[:0]
+	for i := 0; i < len(t.peel); i++ {
+		if t.pure(&t.peel[i]) {
+			t.queue = append(t.queue, uint64(i))
+		}
+	}
+	// each peeling empties at least one cell, so limit of peeled keys protects from cycles of spurious pure cells
+	limit, peeled := len(t.peel), 0
+	for len(t.queue) > 0 && peeled < limit {
+		i := t.queue[len(t.queue)-1]
+		t.queue = t.queue[:len(t.queue)-1]
+		c := &t.peel[i]
+		if !t.pure(c) {
+			// cell was peeled by other key
+			continue
+		}
+		key, count := c.keySum, c.count
+		peeled++
+		if count > 0 {
+			onlyA = append(onlyA, key)
+		} else {
+			onlyB = append(onlyB, key)
+		}
+		for j := uint64(0); j < t.conf.Hashes; j++ {
+			pos := t.pos(key, j)
+			p := &t.peel[pos]
+			p.count -= count
+			p.keySum ^= key
+			p.hsum ^= check(key)
+			if t.pure(p) {
+				t.queue = append(t.queue, pos)
+			}
+		}
+	}
+	for i := 0; i < len(t.peel); i++ {
+		if c := &t.peel[i]; c.count != 0 || c.keySum != 0 || c.hsum != 0 {
+			return onlyA, onlyB, ErrDecodeFailed
+		}
+	}
+	return onlyA, onlyB, nil
+}
+
+func (t *Table) pure(c *cell) bool {
+	return (c.count == 1 || c.count == -1) && c.hsum == check(c.keySum)
+}
+
+func (t *Table) toggle(cells []cell, key uint64, count int64) {
+	hsum := check(key)
+	for j := uint64(0); j < t.conf.Hashes; j++ {
+		c := &cells[t.pos(key, j)]
+		c.count += count
+		c.keySum ^= key
+		c.hsum ^= hsum
+	}
+}
+
+// pos returns position of key in j-th partition.
+func (t *Table) pos(key, j uint64) uint64 {
+	hi, _ := bits.Mul64(pbtk.Fmix64(key+j*0x9e3779b97f4a7c15), t.part) // fast range reduction to [0..part)
+	return j*t.part + hi
+}
+
+func (t *Table) compatible(other *Table) error {
+	if other == nil {
+		return pbtk.ErrInvalidConfig
+	}
+	if other.once.Do(other.init); other.err != nil {
+		return other.err
+	}
+	if len(t.cells) != len(other.cells) || t.conf.Hashes != other.conf.Hashes {
+		return ErrConfigMismatch
+	}
+	return nil
+}
+
+func (t *Table) init() {
+	c := t.conf
+	if c.Hashes == 0 {
+		c.Hashes = defaultHashes
+	}
+	if c.Hashes < 2 {
+		t.err = ErrTooFewHashes
+		return
+	}
+	if c.Cells == 0 {
+		if c.Difference == 0 {
+			t.err = ErrNoDifference
+			return
+		}
+		c.Cells = optimalCells(c.Difference, c.Hashes)
+	}
+	t.part = (c.Cells + c.Hashes - 1) / c.Hashes
+	t.cells = make([]cell, t.part*c.Hashes)
+}
+
+func appendCell(dst []byte, c *cell) []byte {
+	dst = binary.LittleEndian.AppendUint64(dst, uint64(c.count))
+	dst = binary.LittleEndian.AppendUint64(dst, c.keySum)
+	dst = binary.LittleEndian.AppendUint64(dst, c.hsum)
+	return dst
+}
+
+func readCells(r io.Reader, cells []cell) (n int64, err error) {
+	var (
+		buf [24]byte
+		m   int
+	)
+	for i := 0; i < len(cells); i++ {
+		m, err = io.ReadFull(r, buf[:])
+		n += int64(m)
+		if err != nil {
+			return
+		}
+		cells[i] = cell{
+			count:  int64(binary.LittleEndian.Uint64(buf[0:8])),
+			keySum: binary.LittleEndian.Uint64(buf[8:16]),
+			hsum:   binary.LittleEndian.Uint64(buf[16:24]),
+		}
+	}
+	return
+}
+
+func check(key uint64) uint64 {
+	return pbtk.Fmix64(key ^ checkSeed)
+}
@@ -0,0 +1,251 @@
+package iblt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/koykov/pbtk"
+)
+
+// Generates two sets with n common keys, da keys only in a and db keys only in b.
+func testSets(rng *rand.Rand, n, da, db int) (a, b, onlyA, onlyB []uint64) {
+	for i := 0; i < n; i++ {
+		k := rng.Uint64()
+		a, b = append(a, k), append(b, k)
+	}
+	for i := 0; i < da; i++ {
+		k := rng.Uint64()
+		a, onlyA = append(a, k), append(onlyA, k)
+	}
+	for i := 0; i < db; i++ {
+		k := rng.Uint64()
+		b, onlyB = append(b, k), append(onlyB, k)
+	}
+	slices.Sort(onlyA)
+	slices.Sort(onlyB)
+	return
+}
+
+func testTables(t testing.TB, c *Config, a, b []uint64) (ta, tb *Table) {
+	var err error
+	if ta, err = NewTable(c); err != nil {
+		t.Fatal(err)
+	}
+	tb, _ = NewTable(c)
+	for _, k := range a {
+		_ = ta.Insert(k)
+	}
+	for _, k := range b {
+		_ = tb.Insert(k)
+	}
+	return
+}
+
+func TestTable(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	t.Run("reconcile", func(t *testing.T) {
+		for _, d := range []int{1, 10, 100, 1000} {
+			t.Run(fmt.Sprintf("diff%d", d), func(t *testing.T) {
+				var fails int
+				const attempts = 50
+				for i := 0; i < attempts; i++ {
+					a, b, onlyA, onlyB := testSets(rng, 1000, d/2, d-d/2)
+					ta, tb := testTables(t, NewConfig(uint64(d)), a, b)
+					if err := ta.Subtract(tb); err != nil {
+						t.Fatal(err)
+					}
+					ra, rb, err := ta.Decode()
+					if err != nil {
+						fails++
+						continue
+					}
+					slices.Sort(ra)
+					slices.Sort(rb)
+					if !slices.Equal(ra, onlyA) || !slices.Equal(rb, onlyB) {
+						t.Fatalf("decoded keys mismatch: expected %d/%d keys, got %d/%d", len(onlyA), len(onlyB), len(ra), len(rb))
+					}
+				}
+				if fails > attempts/10 {
+					t.Errorf("too many decoding failures: %d of %d", fails, attempts)
+				}
+			})
+		}
+	})
+	t.Run("delete", func(t *testing.T) {
+		a, b, onlyA, onlyB := testSets(rng, 1000, 20, 30)
+		ta, _ := testTables(t, NewConfig(50), a, nil)
+		for _, k := range b {
+			_ = ta.Delete(k)
+		}
+		ra, rb, err := ta.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(ra)
+		slices.Sort(rb)
+		if !slices.Equal(ra, onlyA) || !slices.Equal(rb, onlyB) {
+			t.Error("decoded keys mismatch")
+		}
+	})
+	t.Run("overflow", func(t *testing.T) {
+		a, b, _, _ := testSets(rng, 100, 500, 500)
+		ta, tb := testTables(t, NewConfig(10), a, b)
+		_ = ta.Subtract(tb)
+		if _, _, err := ta.Decode(); err != ErrDecodeFailed {
+			t.Errorf("expected decode failed error, got %v", err)
+		}
+	})
+	t.Run("io", func(t *testing.T) {
+		a, b, onlyA, onlyB := testSets(rng, 1000, 5, 5)
+		ta, tb := testTables(t, NewConfig(10), a, b)
+		var buf bytes.Buffer
+		wn, err := tb.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expect := int64(32 + tb.Cells()*24); wn != expect {
+			t.Fatalf("expected %d bytes, got %d", expect, wn)
+		}
+		// table of replica b received over the wire
+		remote, _ := NewTable(NewConfig(10))
+		rn, err := remote.ReadFrom(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rn != wn {
+			t.Fatalf("expected %d bytes read, got %d", wn, rn)
+		}
+		_ = ta.Subtract(remote)
+		ra, rb, err := ta.Decode()
+		slices.Sort(ra)
+		slices.Sort(rb)
+		if err != nil || !slices.Equal(ra, onlyA) || !slices.Equal(rb, onlyB) {
+			t.Errorf("decoded keys mismatch: %v", err)
+		}
+		other, _ := NewTable(NewConfig(100))
+		if _, err = other.ReadFrom(bytes.NewReader(buf.Bytes())); err != ErrConfigMismatch {
+			t.Errorf("expected config mismatch error, got %v", err)
+		}
+		if _, err = other.ReadFrom(bytes.NewReader(make([]byte, 32))); err != pbtk.ErrInvalidSignature {
+			t.Errorf("expected invalid signature error, got %v", err)
+		}
+		// partial read keeps the table unchanged
+		if _, err = ta.ReadFrom(bytes.NewReader(buf.Bytes()[:wn-1])); err != io.ErrUnexpectedEOF {
+			t.Errorf("expected unexpected EOF error, got %v", err)
+		}
+		if ra1, rb1, err := ta.Decode(); err != nil || len(ra1) != len(ra) || len(rb1) != len(rb) {
+			t.Errorf("table changed by partial read: %v", err)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		if _, err := NewTable(NewConfig(0)); err != ErrNoDifference {
+			t.Errorf("expected no difference error, got %v", err)
+		}
+		if _, err := NewTable(NewConfig(10).WithHashes(1)); err != ErrTooFewHashes {
+			t.Errorf("expected too few hashes error, got %v", err)
+		}
+		ta, _ := NewTable(NewConfig(10))
+		tb, _ := NewTable(NewConfig(100))
+		if err := ta.Subtract(tb); err != ErrConfigMismatch {
+			t.Errorf("expected config mismatch error, got %v", err)
+		}
+		if err := ta.Subtract(nil); err != pbtk.ErrInvalidConfig {
+			t.Errorf("expected invalid config error, got %v", err)
+		}
+	})
+}
+
+func TestStrata(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, d := range []int{0, 10, 100, 1000, 10000} {
+		t.Run(fmt.Sprintf("diff%d", d), func(t *testing.T) {
+			a, b, _, _ := testSets(rng, 10000, d/2, d-d/2)
+			sa, err := NewStrata(NewStrataConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sb, _ := NewStrata(NewStrataConfig())
+			for _, k := range a {
+				_ = sa.Insert(k)
+			}
+			for _, k := range b {
+				_ = sb.Insert(k)
+			}
+			est, err := sa.Estimate(sb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if float64(est) < float64(d)/2 || float64(est) > float64(d)*2 {
+				t.Errorf("estimation too inaccurate: expected %d, got %d", d, est)
+			}
+		})
+	}
+	t.Run("io", func(t *testing.T) {
+		a, b, _, _ := testSets(rng, 1000, 50, 50)
+		sa, _ := NewStrata(NewStrataConfig())
+		sb, _ := NewStrata(NewStrataConfig())
+		for _, k := range a {
+			_ = sa.Insert(k)
+		}
+		for _, k := range b {
+			_ = sb.Insert(k)
+		}
+		var buf bytes.Buffer
+		wn, err := sb.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remote, _ := NewStrata(NewStrataConfig())
+		if rn, err := remote.ReadFrom(&buf); err != nil || rn != wn {
+			t.Fatalf("expected %d bytes read, got %d: %v", wn, rn, err)
+		}
+		e0, _ := sa.Estimate(sb)
+		e1, _ := sa.Estimate(remote)
+		if e0 != e1 {
+			t.Errorf("expected estimation %d, got %d", e0, e1)
+		}
+		// partial read keeps the estimator unchanged
+		buf.Reset()
+		wn, _ = sa.WriteTo(&buf)
+		if _, err = remote.ReadFrom(bytes.NewReader(buf.Bytes()[:wn-1])); err != io.ErrUnexpectedEOF {
+			t.Errorf("expected unexpected EOF error, got %v", err)
+		}
+		if e2, _ := sa.Estimate(remote); e2 != e1 {
+			t.Errorf("estimator changed by partial read: expected %d, got %d", e1, e2)
+		}
+		if _, err = sa.Estimate(nil); err != pbtk.ErrInvalidConfig {
+			t.Errorf("expected invalid config error, got %v", err)
+		}
+		other, _ := NewStrata(NewStrataConfig().WithStrata(16))
+		if _, err = sa.Estimate(other); err != ErrConfigMismatch {
+			t.Errorf("expected config mismatch error, got %v", err)
+		}
+	})
+}
+
+func BenchmarkTable(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	sa, sb, _, _ := testSets(rng, 1e5, 50, 50)
+	b.Run("insert", func(b *testing.B) {
+		t, _ := NewTable(NewConfig(100))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = t.Insert(sa[i%len(sa)])
+		}
+	})
+	b.Run("decode", func(b *testing.B) {
+		ta, tb := testTables(b, NewConfig(100), sa, sb)
+		_ = ta.Subtract(tb)
+		var ra, rb []uint64
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			ra, rb, _ = ta.AppendDecode(ra[:0], rb[:0])
+		}
+	})
+}
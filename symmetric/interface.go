@@ -1,8 +1,15 @@
 package symmetric
 
-import "github.com/koykov/byteseq"
+import (
+	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk/lsh"
+)
 
 type Differ[T byteseq.Q] interface {
 	Diff(a, b T) (float64, error)
+	// DiffSignatures estimates symmetric difference of precomputed signatures without rehashing of the source texts.
+	// Signatures must be produced by hasher of the same kind and params as differ's LSH, otherwise
+	// lsh.ErrSignatureMismatch returns.
+	DiffSignatures(a, b *lsh.Signature) (float64, error)
 	Reset()
 }
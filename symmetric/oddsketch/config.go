@@ -2,6 +2,7 @@ package oddsketch
 
 import (
 	"github.com/koykov/byteseq"
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/lsh"
 )
 
@@ -17,6 +18,11 @@ type Config[T byteseq.Q] struct {
 	// Hasher to calculate hash sum of the items.
 	// Mandatory param.
 	LSH lsh.Hasher[T]
+	// Strategy of bits positions calculation.
+	// If this param omitted, pbtk.HashStrategySalt will use - each LSH value flips exactly one bit.
+	// pbtk.HashStrategyDouble flips k bits derived from LSH value using enhanced double hashing. Since LSH values
+	// are hash sums already, pbtk.HashStrategyDouble128 works the same way.
+	HashStrategy pbtk.HashStrategy
 	// Setting up this section enables concurrent read/write operations.
 	Concurrent *ConcurrentConfig
 }
@@ -55,6 +61,11 @@ func (c *Config[T]) WithLSH(lsh lsh.Hasher[T]) *Config[T] {
 	return c
 }
 
+func (c *Config[T]) WithHashStrategy(strategy pbtk.HashStrategy) *Config[T] {
+	c.HashStrategy = strategy
+	return c
+}
+
 func (c *Config[T]) WithWriteAttemptsLimit(limit uint64) *Config[T] {
 	if c.Concurrent == nil {
 		c.Concurrent = &ConcurrentConfig{}
@@ -67,3 +78,46 @@ func (c *Config[T]) copy() *Config[T] {
 	cpy := *c
 	return &cpy
 }
+
+type SketchConfig struct {
+	// Number of desired items to store in the sketch.
+	// Mandatory param.
+	ItemsNumber uint64
+	// False positive probability value.
+	// If this param omit, defaultFPP (0.01) will use instead.
+	FPP float64
+	// Strategy of bits positions calculation (see Config.HashStrategy).
+	HashStrategy pbtk.HashStrategy
+	// Setting up this section enables concurrent add operations.
+	Concurrent *ConcurrentConfig
+}
+
+func NewSketchConfig(items uint64, fpp float64) *SketchConfig {
+	return &SketchConfig{
+		ItemsNumber: items,
+		FPP:         fpp,
+	}
+}
+
+func (c *SketchConfig) WithConcurrency() *SketchConfig {
+	c.Concurrent = &ConcurrentConfig{}
+	return c
+}
+
+func (c *SketchConfig) WithHashStrategy(strategy pbtk.HashStrategy) *SketchConfig {
+	c.HashStrategy = strategy
+	return c
+}
+
+func (c *SketchConfig) WithWriteAttemptsLimit(limit uint64) *SketchConfig {
+	if c.Concurrent == nil {
+		c.Concurrent = &ConcurrentConfig{}
+	}
+	c.Concurrent.WriteAttemptsLimit = limit
+	return c
+}
+
+func (c *SketchConfig) copy() *SketchConfig {
+	cpy := *c
+	return &cpy
+}
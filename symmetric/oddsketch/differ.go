@@ -1,12 +1,9 @@
 package oddsketch
 
 import (
-	"math"
 	"sync"
 
-	"github.com/koykov/bitvector"
 	"github.com/koykov/byteseq"
-	"github.com/koykov/pbtk/amq"
 	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/symmetric"
 )
@@ -14,8 +11,7 @@ import (
 type differ[T byteseq.Q] struct {
 	lsh.VectorPair[T]
 	conf   *Config[T]
-	m, k   uint64
-	v0, v1 bitvector.Interface
+	s0, s1 *Sketch
 	once   sync.Once
 
 	err error
@@ -36,28 +32,43 @@ func (d *differ[T]) Diff(a, b T) (r float64, err error) {
 	}
 
 	abuf, bbuf, err := d.VectorizePair(d.conf.LSH, a, b)
-	if len(abuf) == 0 || len(bbuf) == 0 || err != nil {
+	if err != nil {
 		return
 	}
-	for i := 0; i < len(abuf); i++ {
-		d.v0.Xor(abuf[i] % d.m)
+	return d.diff(abuf, bbuf)
+}
+
+func (d *differ[T]) DiffSignatures(a, b *lsh.Signature) (r float64, err error) {
+	if d.once.Do(d.init); d.err != nil {
+		err = d.err
+		return
 	}
-	for i := 0; i < len(bbuf); i++ {
-		d.v1.Xor(bbuf[i] % d.m)
+	if err = lsh.CompatibleHasher(d.conf.LSH, a, b); err != nil {
+		return
 	}
-	var diff uint64
-	if diff, err = d.v0.Difference(d.v1); err != nil || diff == 0 {
+	// sketches must be empty to compare the given signatures only
+	d.s0.Reset()
+	d.s1.Reset()
+	return d.diff(a.Values, b.Values)
+}
+
+func (d *differ[T]) diff(abuf, bbuf []uint64) (r float64, err error) {
+	if len(abuf) == 0 || len(bbuf) == 0 {
 		return
 	}
-	m, k := float64(d.m), float64(diff)
-	r = -m * math.Log(1-(2*k)/m)
-	return
+	for i := 0; i < len(abuf); i++ {
+		_ = d.s0.Add(abuf[i])
+	}
+	for i := 0; i < len(bbuf); i++ {
+		_ = d.s1.Add(bbuf[i])
+	}
+	return d.s0.EstimateDiff(d.s1)
 }
 
 func (d *differ[T]) Reset() {
 	d.VectorPair.Reset()
-	d.v0.Reset()
-	d.v1.Reset()
+	d.s0.Reset()
+	d.s1.Reset()
 	d.conf.LSH.Reset()
 }
 
@@ -67,30 +78,14 @@ func (d *differ[T]) init() {
 		d.err = symmetric.ErrNoLSH
 		return
 	}
-	if c.ItemsNumber == 0 {
-		d.err = amq.ErrNoItemsNumber
-		return
+	sc := &SketchConfig{
+		ItemsNumber:  c.ItemsNumber,
+		FPP:          c.FPP,
+		HashStrategy: c.HashStrategy,
+		Concurrent:   c.Concurrent,
 	}
-	if c.FPP == 0 {
-		c.FPP = defaultFPP
-	}
-	if c.FPP < 0 || c.FPP > 1 {
-		d.err = amq.ErrInvalidFPP
+	if d.s0, d.err = NewSketch(sc); d.err != nil {
 		return
 	}
-
-	d.m = optimalM(c.ItemsNumber, c.FPP)
-	d.k = optimalK(c.ItemsNumber, d.m)
-
-	if c.Concurrent != nil {
-		if d.v0, d.err = bitvector.NewConcurrentVector(d.m, c.Concurrent.WriteAttemptsLimit); d.err != nil {
-			return
-		}
-		d.v1, d.err = bitvector.NewConcurrentVector(d.m, c.Concurrent.WriteAttemptsLimit)
-	} else {
-		if d.v0, d.err = bitvector.NewVector(d.m); d.err != nil {
-			return
-		}
-		d.v1, d.err = bitvector.NewVector(d.m)
-	}
+	d.s1, d.err = NewSketch(sc)
 }
@@ -1,9 +1,13 @@
 package oddsketch
 
 import (
+	"encoding/binary"
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/koykov/hash/xxhash"
+	"github.com/koykov/pbtk"
 	"github.com/koykov/pbtk/lsh/minhash"
 	"github.com/koykov/pbtk/shingle"
 	"github.com/koykov/pbtk/symmetric"
@@ -37,6 +41,86 @@ func TestDiffer(t *testing.T) {
 		}
 		symmetric.TestMe(t, d, 0)
 	})
+	t.Run("double", func(t *testing.T) {
+		d, err := NewDiffer[[]byte](NewConfig[[]byte](testSz, testFPP, testlshc).
+			WithHashStrategy(pbtk.HashStrategyDouble))
+		if err != nil {
+			t.Fatal(err)
+		}
+		symmetric.TestMe(t, d, 0)
+	})
+	t.Run("accuracy", func(t *testing.T) {
+		strategies := []struct {
+			name     string
+			strategy pbtk.HashStrategy
+		}{
+			{"salt", pbtk.HashStrategySalt},
+			{"double", pbtk.HashStrategyDouble},
+			{"double128", pbtk.HashStrategyDouble128},
+		}
+		for _, st := range strategies {
+			t.Run(st.name, func(t *testing.T) {
+				testAccuracy(t, st.strategy)
+			})
+		}
+	})
+	t.Run("signatures", func(t *testing.T) {
+		d, _ := NewDiffer[[]byte](NewConfig[[]byte](testSz, testFPP, testlshc))
+		symmetric.TestSignaturesMe(t, d, testlshc)
+	})
+}
+
+// testAccuracy checks estimation against the exact symmetric difference of texts.
+func testAccuracy(t *testing.T, strategy pbtk.HashStrategy) {
+	rng := rand.New(rand.NewSource(1))
+	d, _ := NewDiffer[[]byte](NewConfig[[]byte](1e4, testFPP, &testKeys{}).WithHashStrategy(strategy))
+	for _, n := range []int{10, 100, 1000} {
+		t.Run(fmt.Sprintf("diff%d", n), func(t *testing.T) {
+			// texts share 1000 keys, each of them has n/2 own keys
+			var a, b []byte
+			for i := 0; i < 1000; i++ {
+				key := rng.Uint64()
+				a = binary.LittleEndian.AppendUint64(a, key)
+				b = binary.LittleEndian.AppendUint64(b, key)
+			}
+			for i := 0; i < n/2; i++ {
+				a = binary.LittleEndian.AppendUint64(a, rng.Uint64())
+				b = binary.LittleEndian.AppendUint64(b, rng.Uint64())
+			}
+			d.Reset()
+			r, err := d.Diff(a, b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r < float64(n)*.8 || r > float64(n)*1.2 {
+				t.Errorf("estimation too inaccurate: expected %d, got %f", n, r)
+			}
+		})
+	}
+}
+
+// testKeys is a LSH stub that returns keys encoded to the text as is, so the exact symmetric difference is known.
+type testKeys struct {
+	buf []uint64
+}
+
+func (h *testKeys) Add(value []byte) error {
+	for ; len(value) >= 8; value = value[8:] {
+		h.buf = append(h.buf, binary.LittleEndian.Uint64(value))
+	}
+	return nil
+}
+
+func (h *testKeys) Hash() []uint64 {
+	return h.buf
+}
+
+func (h *testKeys) AppendHash(dst []uint64) []uint64 {
+	return append(dst, h.buf...)
+}
+
+func (h *testKeys) Reset() {
+	h.buf = h.buf[:0]
 }
 
 func BenchmarkDiffer(b *testing.B) {
@@ -0,0 +1,5 @@
+package oddsketch
+
+import "errors"
+
+var ErrConfigMismatch = errors.New("sketches config mismatch")
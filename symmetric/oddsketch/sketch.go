@@ -0,0 +1,192 @@
+package oddsketch
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/koykov/bitvector"
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/amq"
+)
+
+const (
+	dumpSignature = 0x0dd5e7c4a91b2f63
+	dumpVersion   = 1.0
+)
+
+// Sketch is a standalone odd sketch of a set of uint64 keys (LSH values or hash sums of items). Sketch of a large set
+// may be built once, stored and compared with other sketches many times.
+//
+// Each key flips bits of the sketch, so bits of keys added even number of times cancel. XOR of sketches of sets A and B
+// is a sketch of symmetric difference of A and B.
+type Sketch struct {
+	once sync.Once
+	conf *SketchConfig
+	m, k uint64
+	vec  bitvector.Interface
+
+	err error
+}
+
+func NewSketch(conf *SketchConfig) (*Sketch, error) {
+	if conf == nil {
+		return nil, pbtk.ErrInvalidConfig
+	}
+	s := &Sketch{conf: conf.copy()}
+	if s.once.Do(s.init); s.err != nil {
+		return nil, s.err
+	}
+	return s, nil
+}
+
+// Add flips bits of key.
+func (s *Sketch) Add(key uint64) error {
+	if s.once.Do(s.init); s.err != nil {
+		return s.err
+	}
+	if s.conf.HashStrategy == pbtk.HashStrategySalt {
+		s.vec.Xor(key % s.m)
+		return nil
+	}
+	dh := pbtk.NewDoubleHash(key)
+	for j := uint64(0); j < s.k; j++ {
+		s.vec.Xor(dh.Next() % s.m)
+	}
+	return nil
+}
+
+// Xor merges other sketch to s, so s becomes sketch of symmetric difference of both sets. Both sketches must have the
+// same config.
+func (s *Sketch) Xor(other *Sketch) error {
+	if err := s.compatible(other); err != nil {
+		return err
+	}
+	for i := uint64(0); i < s.m; i++ {
+		if other.vec.Get(i) != 0 {
+			s.vec.Xor(i)
+		}
+	}
+	return nil
+}
+
+// EstimateDiff estimates size of symmetric difference of sets of s and other. Both sketches must have the same config.
+func (s *Sketch) EstimateDiff(other *Sketch) (r float64, err error) {
+	if err = s.compatible(other); err != nil {
+		return
+	}
+	var diff uint64
+	if diff, err = s.vec.Difference(other.vec); err != nil || diff == 0 {
+		return
+	}
+	m, k := float64(s.m), float64(diff)
+	r = -m / 2 * math.Log(1-(2*k)/m)
+	if s.conf.HashStrategy != pbtk.HashStrategySalt {
+		// each item flips k bits
+		r /= float64(s.k)
+	}
+	return
+}
+
+// Reset flushes the sketch.
+func (s *Sketch) Reset() {
+	if s.once.Do(s.init); s.err != nil {
+		return
+	}
+	s.vec.Reset()
+}
+
+// WriteTo writes sketch to w.
+func (s *Sketch) WriteTo(w io.Writer) (n int64, err error) {
+	if s.once.Do(s.init); s.err != nil {
+		return 0, s.err
+	}
+	var buf [40]byte
+	binary.LittleEndian.PutUint64(buf[0:8], dumpSignature)
+	binary.LittleEndian.PutUint64(buf[8:16], math.Float64bits(dumpVersion))
+	binary.LittleEndian.PutUint64(buf[16:24], s.m)
+	binary.LittleEndian.PutUint64(buf[24:32], s.k)
+	binary.LittleEndian.PutUint64(buf[32:40], uint64(s.conf.HashStrategy))
+	var m int
+	m, err = w.Write(buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	var n1 int64
+	n1, err = s.vec.WriteTo(w)
+	n += n1
+	return
+}
+
+// ReadFrom reads sketch from r. Dump must be written by sketch with the same config.
+func (s *Sketch) ReadFrom(r io.Reader) (n int64, err error) {
+	if s.once.Do(s.init); s.err != nil {
+		return 0, s.err
+	}
+	var (
+		buf [40]byte
+		m   int
+	)
+	m, err = io.ReadFull(r, buf[:])
+	n += int64(m)
+	if err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint64(buf[0:8]) != dumpSignature {
+		return n, pbtk.ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint64(buf[8:16]) != math.Float64bits(dumpVersion) {
+		return n, pbtk.ErrVersionMismatch
+	}
+	if binary.LittleEndian.Uint64(buf[16:24]) != s.m || binary.LittleEndian.Uint64(buf[24:32]) != s.k ||
+		binary.LittleEndian.Uint64(buf[32:40]) != uint64(s.conf.HashStrategy) {
+		return n, ErrConfigMismatch
+	}
+	var n1 int64
+	n1, err = s.vec.ReadFrom(r)
+	n += n1
+	return
+}
+
+func (s *Sketch) compatible(other *Sketch) error {
+	if s.once.Do(s.init); s.err != nil {
+		return s.err
+	}
+	if other.once.Do(other.init); other.err != nil {
+		return other.err
+	}
+	if s.m != other.m || s.k != other.k || s.conf.HashStrategy != other.conf.HashStrategy {
+		return ErrConfigMismatch
+	}
+	return nil
+}
+
+func (s *Sketch) init() {
+	c := s.conf
+	if c.HashStrategy > pbtk.HashStrategyDouble128 {
+		s.err = pbtk.ErrUnknownHashStrategy
+		return
+	}
+	if c.ItemsNumber == 0 {
+		s.err = amq.ErrNoItemsNumber
+		return
+	}
+	if c.FPP == 0 {
+		c.FPP = defaultFPP
+	}
+	if c.FPP < 0 || c.FPP > 1 {
+		s.err = amq.ErrInvalidFPP
+		return
+	}
+
+	s.m = optimalM(c.ItemsNumber, c.FPP)
+	s.k = optimalK(c.ItemsNumber, s.m)
+
+	if c.Concurrent != nil {
+		s.vec, s.err = bitvector.NewConcurrentVector(s.m, c.Concurrent.WriteAttemptsLimit)
+	} else {
+		s.vec, s.err = bitvector.NewVector(s.m)
+	}
+}
@@ -0,0 +1,180 @@
+package oddsketch
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/koykov/pbtk"
+	"github.com/koykov/pbtk/lsh"
+)
+
+func testSketch(t testing.TB, c *SketchConfig, keys []uint64) *Sketch {
+	s, err := NewSketch(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		if err = s.Add(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// Generates two sets with n common keys and d keys of symmetric difference.
+func testSketchSets(rng *rand.Rand, n, d int) (a, b, diff []uint64) {
+	for i := 0; i < n; i++ {
+		k := rng.Uint64()
+		a, b = append(a, k), append(b, k)
+	}
+	for i := 0; i < d; i++ {
+		k := rng.Uint64()
+		if i%2 == 0 {
+			a = append(a, k)
+		} else {
+			b = append(b, k)
+		}
+		diff = append(diff, k)
+	}
+	return
+}
+
+func TestSketch(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, strategy := range []pbtk.HashStrategy{pbtk.HashStrategySalt, pbtk.HashStrategyDouble} {
+		for _, d := range []int{0, 10, 100, 1000} {
+			t.Run(fmt.Sprintf("strategy%d/diff%d", strategy, d), func(t *testing.T) {
+				c := NewSketchConfig(1e4, testFPP).WithHashStrategy(strategy)
+				a, b, _ := testSketchSets(rng, 1000, d)
+				sa, sb := testSketch(t, c, a), testSketch(t, c, b)
+				r, err := sa.EstimateDiff(sb)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if d == 0 && r != 0 {
+					t.Errorf("expected zero difference, got %f", r)
+				}
+				if d > 0 && (r < float64(d)*.8 || r > float64(d)*1.2) {
+					t.Errorf("estimation too inaccurate: expected %d, got %f", d, r)
+				}
+			})
+		}
+	}
+	t.Run("xor", func(t *testing.T) {
+		c := NewSketchConfig(1e4, testFPP)
+		a, b, diff := testSketchSets(rng, 1000, 100)
+		sa, sb, sd := testSketch(t, c, a), testSketch(t, c, b), testSketch(t, c, diff)
+		if err := sa.Xor(sb); err != nil {
+			t.Fatal(err)
+		}
+		empty, _ := NewSketch(c)
+		r0, _ := sa.EstimateDiff(empty)
+		r1, _ := sd.EstimateDiff(empty)
+		if r0 != r1 {
+			t.Errorf("xor of sketches must be equal to sketch of symmetric difference: %f vs %f", r0, r1)
+		}
+	})
+	t.Run("differ", func(t *testing.T) {
+		d, _ := NewDiffer[[]byte](NewConfig[[]byte](testSz, testFPP, testlshc))
+		testlshc.Reset()
+		_ = testlshc.Add([]byte("A brown and white dog is running through the tall grass"))
+		sa := lsh.NewSignature[[]byte](testlshc)
+		testlshc.Reset()
+		_ = testlshc.Add([]byte("A brown and white dog is moving through the wild grass"))
+		sb := lsh.NewSignature[[]byte](testlshc)
+		r0, _ := d.DiffSignatures(sa, sb)
+		c := NewSketchConfig(testSz, testFPP)
+		r1, err := testSketch(t, c, sa.Values).EstimateDiff(testSketch(t, c, sb.Values))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r0 != r1 {
+			t.Errorf("expected differ result %f, got %f", r0, r1)
+		}
+	})
+	t.Run("io", func(t *testing.T) {
+		c := NewSketchConfig(1e4, testFPP)
+		a, b, _ := testSketchSets(rng, 1000, 50)
+		sa, sb := testSketch(t, c, a), testSketch(t, c, b)
+		var buf bytes.Buffer
+		wn, err := sb.WriteTo(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		remote, _ := NewSketch(c)
+		if rn, err := remote.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil || rn != wn {
+			t.Fatalf("expected %d bytes read, got %d: %v", wn, rn, err)
+		}
+		r0, _ := sa.EstimateDiff(sb)
+		r1, _ := sa.EstimateDiff(remote)
+		if r0 != r1 {
+			t.Errorf("expected estimation %f, got %f", r0, r1)
+		}
+		other, _ := NewSketch(NewSketchConfig(1e5, testFPP))
+		if _, err = other.ReadFrom(bytes.NewReader(buf.Bytes())); err != ErrConfigMismatch {
+			t.Errorf("expected config mismatch error, got %v", err)
+		}
+		if _, err = other.ReadFrom(bytes.NewReader(make([]byte, 40))); err != pbtk.ErrInvalidSignature {
+			t.Errorf("expected invalid signature error, got %v", err)
+		}
+		if _, err = sa.EstimateDiff(other); err != ErrConfigMismatch {
+			t.Errorf("expected config mismatch error, got %v", err)
+		}
+	})
+	t.Run("concurrent", func(t *testing.T) {
+		c := NewSketchConfig(1e4, testFPP).WithConcurrency()
+		a, b, _ := testSketchSets(rng, 1000, 100)
+		sa, _ := NewSketch(c)
+		var wg sync.WaitGroup
+		const workers = 8
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < len(a); i += workers {
+					_ = sa.Add(a[i])
+				}
+			}(w)
+		}
+		wg.Wait()
+		sb := testSketch(t, c, b)
+		r0, _ := sa.EstimateDiff(sb)
+		r1, _ := testSketch(t, NewSketchConfig(1e4, testFPP), a).EstimateDiff(testSketch(t, NewSketchConfig(1e4, testFPP), b))
+		if r0 != r1 {
+			t.Errorf("expected estimation %f, got %f", r1, r0)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		if _, err := NewSketch(nil); err != pbtk.ErrInvalidConfig {
+			t.Errorf("expected invalid config error, got %v", err)
+		}
+		if _, err := NewSketch(NewSketchConfig(0, testFPP)); err == nil {
+			t.Error("expected no items number error")
+		}
+	})
+}
+
+func BenchmarkSketch(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	keys, other, _ := testSketchSets(rng, 1e4, 100)
+	b.Run("add", func(b *testing.B) {
+		s, _ := NewSketch(NewSketchConfig(1e6, testFPP))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = s.Add(keys[i%len(keys)])
+		}
+	})
+	b.Run("estimate", func(b *testing.B) {
+		c := NewSketchConfig(1e4, testFPP)
+		sa, sb := testSketch(b, c, keys), testSketch(b, c, other)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = sa.EstimateDiff(sb)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package symmetric
 import (
 	"testing"
 
+	"github.com/koykov/pbtk/lsh"
 	"github.com/koykov/pbtk/simtest"
 )
 
@@ -24,6 +25,15 @@ func TestMe(t *testing.T, diff Differ[[]byte], threshold float64) {
 	})
 }
 
+// TestSignaturesMe checks that difference of signatures stored by hasher equals difference of the source texts. hash
+// must be the same hasher as differ's LSH.
+func TestSignaturesMe(t *testing.T, diff Differ[[]byte], hash lsh.Hasher[[]byte]) {
+	lsh.TestSignaturesMe(t, hash, func(a, b []byte) (float64, error) {
+		diff.Reset()
+		return diff.Diff(a, b)
+	}, diff.DiffSignatures)
+}
+
 func BenchMe(b *testing.B, diff Differ[[]byte]) {
 	simtest.EachTestingDataset(func(_ int, ds *simtest.Dataset) {
 		b.Run(ds.Name, func(b *testing.B) {
@@ -2,9 +2,7 @@ package pbtk
 
 import (
 	"bufio"
-	"io"
 	"io/fs"
-	"net/http"
 	"os"
 	"path/filepath"
 )
@@ -16,9 +14,16 @@ type TestingDataset[T []byte] struct {
 	All       []T
 }
 
-var datasets []TestingDataset[[]byte]
+// TestingDatasetSource is a source of testing dataset. Source must be deterministic and must not depend on network or
+// host environment, so tests using it are reproducible.
+type TestingDatasetSource interface {
+	Load() (TestingDataset[[]byte], error)
+}
 
-func init() {
+// DirTestingDataset loads dataset from directory contains positive.txt and negative.txt files (one key per line).
+type DirTestingDataset string
+
+func (d DirTestingDataset) Load() (ds TestingDataset[[]byte], err error) {
 	fread := func(dst [][]byte, path string) ([][]byte, error) {
 		fh, err := os.Open(path)
 		if err != nil {
@@ -33,6 +38,53 @@ func init() {
 		}
 		return dst, scr.Err()
 	}
+	path := string(d)
+	if ds.Positives, err = fread(ds.Positives, filepath.Join(path, "positive.txt")); err != nil {
+		return
+	}
+	if ds.Negatives, err = fread(ds.Negatives, filepath.Join(path, "negative.txt")); err != nil {
+		return
+	}
+	ds.Name, ds.All = filepath.Base(path), append(ds.Positives, ds.Negatives...)
+	return
+}
+
+// SyntheticTestingDataset generates Size distinct pseudo-words (see Words). Even words are positives, odd are negatives.
+type SyntheticTestingDataset struct {
+	Name string
+	Size int
+	Seed int64
+}
+
+func (d SyntheticTestingDataset) Load() (ds TestingDataset[[]byte], err error) {
+	w := NewWords(d.Seed)
+	ds.Name = d.Name
+	ds.All = make([][]byte, 0, d.Size)
+	for i := 0; i < d.Size; i++ {
+		key := w.AppendWord(nil, uint64(i))
+		ds.All = append(ds.All, key)
+		if i%2 == 0 {
+			ds.Positives = append(ds.Positives, key)
+		} else {
+			ds.Negatives = append(ds.Negatives, key)
+		}
+	}
+	return
+}
+
+var datasets []TestingDataset[[]byte]
+
+// RegisterTestingDataset loads dataset from src and adds it to the list of datasets of domains TestMe functions.
+func RegisterTestingDataset(src TestingDatasetSource) error {
+	ds, err := src.Load()
+	if err != nil {
+		return err
+	}
+	datasets = append(datasets, ds)
+	return nil
+}
+
+func init() {
 	probes := []string{
 		"testdata",
 		"../testdata",
@@ -43,69 +95,11 @@ func init() {
 			if info == nil || !info.IsDir() || cpath == path {
 				return nil
 			}
-			var ds TestingDataset[[]byte]
-			if ds.Positives, err = fread(ds.Positives, cpath+"/positive.txt"); err != nil {
-				return err
-			}
-			if ds.Negatives, err = fread(ds.Negatives, cpath+"/negative.txt"); err != nil {
-				return err
-			}
-			ds.Name, ds.All = info.Name(), append(ds.Positives, ds.Negatives...)
-			datasets = append(datasets, ds)
-			return nil
+			return RegisterTestingDataset(DirTestingDataset(cpath))
 		})
 	}
-	// Try to compose dataset based on system's dictionaries.
-	sysDS := TestingDataset[[]byte]{Name: "system/dict"}
-	if words, err := fread(nil, "/usr/share/dict/words"); err == nil && len(words) > 0 {
-		sysDS.All = words
-		for i := 0; i < len(words); i++ {
-			if i%2 == 0 {
-				sysDS.Positives = append(sysDS.Positives, words[i])
-			} else {
-				sysDS.Negatives = append(sysDS.Negatives, words[i])
-			}
-		}
-		datasets = append(datasets, sysDS)
-	}
-	{
-		const (
-			localDS  = "/tmp/probds_eng_voc.txt"
-			remoteDS = "https://raw.githubusercontent.com/koykov/dataset/master/vocabulary/freelang/English.txt"
-		)
-		// Try to compose dataset based on remote English vocabulary.
-		var (
-			vocDS TestingDataset[[]byte]
-			err   error
-		)
-		// - try local cache first
-		if vocDS.All, err = fread(vocDS.All, localDS); err != nil {
-			// - negative, try fetch remote data and save to local cache
-			var resp *http.Response
-			resp, err = http.Get(remoteDS)
-			if err == nil && resp.StatusCode == http.StatusOK {
-				defer func() { _ = resp.Body.Close() }()
-				var contents []byte
-				if contents, err = io.ReadAll(resp.Body); err == nil {
-					err = os.WriteFile(localDS, contents, 0644)
-				}
-				// - try local cache again
-				vocDS.All, err = fread(vocDS.All, localDS)
-			}
-		}
-		if err == nil {
-			// - fill up positives and negatives
-			for i := 0; i < len(vocDS.All); i++ {
-				if i%2 == 0 {
-					vocDS.Positives = append(vocDS.Positives, vocDS.All[i])
-				} else {
-					vocDS.Negatives = append(vocDS.Negatives, vocDS.All[i])
-				}
-			}
-			vocDS.Name = "english vocabulary"
-			datasets = append(datasets, vocDS)
-		}
-	}
+	// Synthetic vocabulary of size comparable with natural language vocabulary.
+	_ = RegisterTestingDataset(SyntheticTestingDataset{Name: "synthetic/words", Size: 5e4, Seed: 1})
 }
 
 func EachTestingDataset(f func(i int, ds *TestingDataset[[]byte])) {